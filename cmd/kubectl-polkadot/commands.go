@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/controller/polkadot"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// status prints the status of the CR, as reported by the operator
+func status(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	s := CRInstance.Status
+	fmt.Fprintf(w, "Name:\t%s\n", CRInstance.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", CRInstance.Namespace)
+	fmt.Fprintf(w, "Kind:\t%s\n", CRInstance.Spec.Kind)
+	fmt.Fprintf(w, "Paused:\t%t\n", CRInstance.Spec.Paused)
+	fmt.Fprintf(w, "Replicas:\t%d\n", s.Replicas)
+	fmt.Fprintf(w, "Synced:\t%t\n", s.Synced)
+	fmt.Fprintf(w, "Observed Generation:\t%d/%d\n", s.ObservedGeneration, CRInstance.Generation)
+	if s.LastReconcileTime != nil {
+		fmt.Fprintf(w, "Last Reconcile:\t%s\n", s.LastReconcileTime.Format(time.RFC3339))
+	}
+	if s.SessionKeys != nil {
+		fmt.Fprintf(w, "Session Keys:\t%s\n", s.SessionKeys.PublicKeys)
+	}
+
+	if len(s.Roles) > 0 {
+		fmt.Fprintln(w, "\nROLE\tSTATEFULSET\tREADY\tUPDATED\tBEST\tFINALIZED\tSYNCING\tIMAGE")
+		for _, role := range s.Roles {
+			fmt.Fprintf(w, "%s\t%s\t%d/%d\t%d\t%d\t%d\t%t\t%s\n", role.Role, role.StatefulSetName, role.ReadyReplicas,
+				role.Replicas, role.UpdatedReplicas, role.BestBlock, role.FinalizedBlock, role.IsSyncing, role.Image)
+		}
+		fmt.Fprintln(w, "\nNODE\tPEERS\tBEST\tFINALIZED\tSYNCING")
+		for _, role := range s.Roles {
+			for _, node := range role.Nodes {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%t\n", node.Name, node.Peers, node.BestBlock, node.FinalizedBlock, node.IsSyncing)
+			}
+		}
+	}
+
+	if len(s.Conditions) > 0 {
+		fmt.Fprintln(w, "\nCONDITION\tSTATUS\tREASON\tSINCE\tMESSAGE")
+		for _, condition := range s.Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason,
+				condition.LastTransitionTime.Format(time.RFC3339), condition.Message)
+		}
+	}
+
+	if len(s.History) > 0 {
+		fmt.Fprintln(w, "\nGENERATION\tAPPLIED\tCHANGED FIELDS\tACTIONS")
+		for _, applied := range s.History {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", applied.Generation, applied.Time.Format(time.RFC3339),
+				strings.Join(applied.ChangedFields, ", "), strings.Join(applied.Actions, ", "))
+		}
+	}
+
+	if s.DryRun != nil {
+		fmt.Fprintf(w, "\nDRY RUN CHANGES (%s)\n", s.DryRun.Time.Format(time.RFC3339))
+		for _, change := range s.DryRun.Changes {
+			fmt.Fprintf(w, "%s\n", change)
+		}
+	}
+	return w.Flush()
+}
+
+// pause stops the reconciliation of the resources of the CR
+func pause(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	return patchCR(c, CRInstance, "paused", func(CR *polkadotv1alpha1.Polkadot) {
+		CR.Spec.Paused = true
+	})
+}
+
+// resume resumes the reconciliation of the resources of the CR
+func resume(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	return patchCR(c, CRInstance, "resumed", func(CR *polkadotv1alpha1.Polkadot) {
+		CR.Spec.Paused = false
+	})
+}
+
+// rotateKeys requests a rotation of the session keys of the validator, the trigger being the current time
+func rotateKeys(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	trigger := time.Now().UTC().Format(time.RFC3339)
+	return patchCR(c, CRInstance, "session keys rotation requested (trigger "+trigger+")", func(CR *polkadotv1alpha1.Polkadot) {
+		CR.Spec.Validator.KeyRotation.Trigger = trigger
+	})
+}
+
+// dryRun sets or removes the dry-run annotation of the CR
+func dryRun(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return fmt.Errorf("usage: kubectl polkadot dry-run on|off <cr>")
+	}
+	return patchCR(c, CRInstance, "dry-run "+args[0], func(CR *polkadotv1alpha1.Polkadot) {
+		if args[0] == "off" {
+			delete(CR.Annotations, polkadot.DryRunAnnotation)
+			return
+		}
+		if CR.Annotations == nil {
+			CR.Annotations = map[string]string{}
+		}
+		CR.Annotations[polkadot.DryRunAnnotation] = "true"
+	})
+}
+
+// backup creates a Job from the backup CronJob of the CR, like "kubectl create job --from=cronjob/..."
+func backup(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error {
+	if len(args) != 1 || args[0] != "now" {
+		return fmt.Errorf("usage: kubectl polkadot backup now <cr>")
+	}
+	if !CRInstance.Spec.Backup.Enabled {
+		return fmt.Errorf("the backup of %s is not enabled", CRInstance.Name)
+	}
+
+	cronJob := &batchv1beta1.CronJob{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: polkadot.BackupName, Namespace: CRInstance.Namespace}, cronJob)
+	if err != nil {
+		return err
+	}
+	isController := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-manual-%d", cronJob.Name, time.Now().Unix()),
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: map[string]string{"cronjob.kubernetes.io/instantiate": "manual"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: batchv1beta1.SchemeGroupVersion.String(),
+				Kind:       "CronJob",
+				Name:       cronJob.Name,
+				UID:        cronJob.UID,
+				Controller: &isController,
+			}},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+	if err := c.Create(context.TODO(), job); err != nil {
+		return err
+	}
+	fmt.Printf("job.batch/%s created\n", job.Name)
+	return nil
+}
+
+// patchCR applies the change to the CR with a merge patch, the other fields being left untouched
+func patchCR(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, result string, change func(CR *polkadotv1alpha1.Polkadot)) error {
+	original := CRInstance.DeepCopy()
+	change(CRInstance)
+	if err := c.Patch(context.TODO(), CRInstance, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	fmt.Printf("polkadot.polkadot.swisscomblockchain.com/%s %s\n", CRInstance.Name, result)
+	return nil
+}
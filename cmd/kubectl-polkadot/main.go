@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+
+// kubectl-polkadot is a kubectl plugin driving the Polkadot CustomResources through the fields and the annotations
+// understood by the operator. Installed in the PATH, it is run as "kubectl polkadot <command>"
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `Drive the Polkadot CustomResources managed by the polkadot-k8s-operator.
+
+Usage:
+  kubectl polkadot <command> <cr> [flags]
+
+Commands:
+  status <cr>          Show the nodes, the roles and the conditions reported by the operator
+  pause <cr>           Stop the reconciliation of the resources of the CR (spec.paused)
+  resume <cr>          Resume the reconciliation of the resources of the CR
+  rotate-keys <cr>     Rotate the session keys of the validator (spec.validator.keyRotation.trigger)
+  backup now <cr>      Take the snapshots of the data volumes now, with a Job of the backup CronJob
+  dry-run on|off <cr>  Reconcile the CR in dry-run mode, reporting the changes without applying them
+
+Flags:
+`
+
+var (
+	kubeconfig = pflag.String("kubeconfig", "", "Path to the kubeconfig file, the KUBECONFIG environment variable or ~/.kube/config if not set")
+	namespace  = pflag.StringP("namespace", "n", "", "Namespace of the CR, the namespace of the current context if not set")
+)
+
+// command runs a command of the plugin against the CR
+type command func(c client.Client, CRInstance *polkadotv1alpha1.Polkadot, args []string) error
+
+var commands = map[string]command{
+	"status":      status,
+	"pause":       pause,
+	"resume":      resume,
+	"rotate-keys": rotateKeys,
+	"backup":      backup,
+	"dry-run":     dryRun,
+}
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	if err := run(pflag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command and its arguments, the name of the CR being the last one
+func run(args []string) error {
+	if len(args) < 2 {
+		pflag.Usage()
+		return fmt.Errorf("a command and a CR are required")
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		pflag.Usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	c, crNamespace, err := newClient()
+	if err != nil {
+		return err
+	}
+	CRInstance := &polkadotv1alpha1.Polkadot{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: args[len(args)-1], Namespace: crNamespace}, CRInstance)
+	if err != nil {
+		return err
+	}
+	return cmd(c, CRInstance, args[1:len(args)-1])
+}
+
+// newClient returns a client of the cluster of the kubeconfig and the namespace of the CR
+func newClient() (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *kubeconfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = *namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	crNamespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	return c, crNamespace, nil
+}
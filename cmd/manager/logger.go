@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newLogger returns the logger of the operator, writing on stderr the lines of the level or of a more severe one with
+// the json or the console encoder. The debug and the warn lines are the V(1) and V(-1) lines of the logr interface
+func newLogger(level string, encoding string) (logr.Logger, error) {
+	var zapLevel zapcore.Level
+	switch level {
+	case "debug", "info", "warn", "error":
+		if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid log level %q, one of debug, info, warn or error is expected", level)
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("invalid log encoder %q, json or console is expected", encoding)
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), zap.NewAtomicLevelAt(zapLevel))
+	return zapr.NewLogger(zap.New(core)), nil
+}
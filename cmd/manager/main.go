@@ -10,24 +10,28 @@ import (
 	config2 "github.com/swisscom-blockchain/polkadot-k8s-operator/config"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/controller"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/version"
 
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
-	"github.com/operator-framework/operator-sdk/pkg/leader"
-	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 	"github.com/operator-framework/operator-sdk/pkg/metrics"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -38,10 +42,48 @@ import (
 var (
 	metricsHost               = "0.0.0.0"
 	metricsPort         int32 = 8383
+	webhookPort               = 9443
 	operatorMetricsPort int32 = 8686
 )
 var log = logf.Log.WithName("cmd")
 
+// The leader election lets several replicas of the operator run for high availability, only the leader reconciling
+var (
+	leaderElection          = pflag.Bool("leader-elect", true, "Enable the leader election, so that a single replica of the operator is active")
+	leaderElectionID        = pflag.String("leader-election-id", "polkadot-operator-lock", "Name of the ConfigMap holding the leader election lock")
+	leaderElectionNamespace = pflag.String("leader-election-namespace", "", "Namespace of the leader election lock, the namespace of the operator if not set")
+	leaseDuration           = pflag.Duration("leader-election-lease-duration", 15*time.Second, "Duration the replicas wait before taking over the lock of a leader not renewing it")
+	renewDeadline           = pflag.Duration("leader-election-renew-deadline", 10*time.Second, "Duration the leader retries to renew the lock before giving up the leadership")
+	retryPeriod             = pflag.Duration("leader-election-retry-period", 2*time.Second, "Duration the replicas wait between two attempts to acquire or renew the lock")
+)
+
+// The logs are structured, the level filtering out the less severe ones
+var (
+	logLevel   = pflag.String("log-level", "info", "Minimum level of the logs: debug, info, warn or error")
+	logEncoder = pflag.String("log-encoder", "json", "Encoding of the logs: json or console")
+)
+
+// The label selector lets several instances of the operator run in the same cluster, each one reconciling its own CustomResources
+var watchLabelSelector = pflag.String("watch-label-selector", "", "Label selector of the Polkadot CustomResources reconciled by the operator, all of them if not set")
+
+// The namespaces let the operator run with namespace-scoped permissions, e.g. in the multi-tenant clusters
+var (
+	namespaceScoped = pflag.Bool("namespace-scoped", false, "Watch only the namespace of the operator, so that it runs with the Role of its namespace")
+	watchNamespaces = pflag.StringSlice("watch-namespaces", nil, "Namespaces watched by the operator, with a Role in each one of them, the WATCH_NAMESPACE environment variable if not set (all the namespaces if empty)")
+)
+
+// The reconcile pressure on the API server can be tuned on large installations
+var syncPeriod = pflag.Duration("sync-period", 10*time.Hour, "Period of the resync of the cache, requeuing all the watched CustomResources")
+
+func init() {
+	pflag.DurationVar(&config2.RequeueInterval, "requeue-interval", config2.RequeueInterval, "Period of the requeue of the successfully reconciled CustomResources, disabled if zero")
+	pflag.DurationVar(&config2.RequeueAfterCreation, "requeue-after-creation", config2.RequeueAfterCreation, "Delay of the requeue after the creation of a resource not watched by the operator")
+	pflag.DurationVar(&config2.MinRequeueBackoff, "requeue-backoff-min", config2.MinRequeueBackoff, "Delay of the requeue after the first failed reconciliation, doubled at every consecutive failure")
+	pflag.DurationVar(&config2.MaxRequeueBackoff, "requeue-backoff-max", config2.MaxRequeueBackoff, "Maximum delay of the requeue after a failed reconciliation")
+	// The dry-run mode validates a new version of the operator against the existing CustomResources
+	pflag.BoolVar(&config2.DryRun, "dry-run", config2.DryRun, "Report the changes the reconciliations would make to the resources, in the status and the events of the CustomResources, without applying them")
+}
+
 func printVersion() {
 	log.Info(fmt.Sprintf("Operator Version: %s", version.Version))
 	log.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
@@ -50,28 +92,28 @@ func printVersion() {
 }
 
 func main() {
-	// Add the zap logger flag set to the CLI. The flag set must
-	// be added before calling pflag.Parse().
-	pflag.CommandLine.AddFlagSet(zap.FlagSet())
-
 	// Add flags registered by imported packages (e.g. glog and
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
 	pflag.Parse()
 
-	// Use a zap logr.Logger implementation. If none of the zap
-	// flags are configured (or if the zap flag set is not being
-	// used), this defaults to a production zap logger.
+	// Use a zap logr.Logger implementation, with the level and the
+	// encoder of the log flags.
 	//
 	// The logger instantiated here can be changed to any logger
 	// implementing the logr.Logger interface. This logger will
 	// be propagated through the whole operator, generating
 	// uniform and structured logs.
-	logf.SetLogger(zap.Logger())
+	logger, err := newLogger(*logLevel, *logEncoder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create the logger: %v\n", err)
+		os.Exit(1)
+	}
+	logf.SetLogger(logger)
 
 	// Load all the env variables
-	err := config2.LoadAllEnvVar()
+	err = config2.LoadAllEnvVar()
 	if err != nil {
 		log.Error(err, "Failed to Load the environment variables")
 		os.Exit(1)
@@ -79,11 +121,38 @@ func main() {
 
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
+	config2.WatchLabelSelector, err = labels.Parse(*watchLabelSelector)
+	if err != nil {
+		log.Error(err, "Failed to parse the watch label selector")
+		os.Exit(1)
+	}
+	log.Info(fmt.Sprintf("Watching the CustomResources matching the label selector: %q", config2.WatchLabelSelector.String()))
+
+	if config2.MinRequeueBackoff <= 0 || config2.MaxRequeueBackoff < config2.MinRequeueBackoff {
+		log.Error(fmt.Errorf("invalid requeue backoff: min %v, max %v", config2.MinRequeueBackoff, config2.MaxRequeueBackoff), "Failed to parse the requeue flags")
+		os.Exit(1)
+	}
+
+	config2.WatchNamespaces, err = getWatchNamespaces()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
+	namespace := ""
+	var newCache cache.NewCacheFunc
+	if len(config2.WatchNamespaces) == 1 {
+		namespace = config2.WatchNamespaces[0]
+	} else if len(config2.WatchNamespaces) > 1 {
+		newCache = cache.MultiNamespacedCacheBuilder(config2.WatchNamespaces)
+	}
+	if len(config2.WatchNamespaces) == 0 {
+		log.Info("Watching all the namespaces")
+	} else {
+		log.Info(fmt.Sprintf("Watching the namespaces: %v", config2.WatchNamespaces))
+	}
+	if config2.DryRun {
+		log.Info("Running in dry-run mode, the changes of the resources are reported without being applied")
+	}
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -93,17 +162,27 @@ func main() {
 	}
 
 	ctx := context.TODO()
-	// Become the leader before proceeding
-	err = leader.Become(ctx, "polkadot-operator-lock")
-	if err != nil {
-		log.Error(err, "")
-		os.Exit(1)
+	// The lock is in the namespace of the operator, which is only known when running in a cluster
+	if *leaderElection && *leaderElectionNamespace == "" {
+		if _, err := k8sutil.GetOperatorNamespace(); err == k8sutil.ErrRunLocal || err == k8sutil.ErrNoNamespace {
+			log.Info("Skipping leader election; not running in a cluster.")
+			*leaderElection = false
+		}
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
+	// Create a new Cmd to provide shared dependencies and start components, the controllers start once the leadership is acquired
 	mgr, err := manager.New(cfg, manager.Options{
-		Namespace:          namespace,
-		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Namespace:               namespace,
+		NewCache:                newCache,
+		MetricsBindAddress:      fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:                    webhookPort,
+		LeaderElection:          *leaderElection,
+		LeaderElectionID:        *leaderElectionID,
+		LeaderElectionNamespace: *leaderElectionNamespace,
+		LeaseDuration:           leaseDuration,
+		RenewDeadline:           renewDeadline,
+		RetryPeriod:             retryPeriod,
+		SyncPeriod:              syncPeriod,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -118,14 +197,32 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup Scheme for the Prometheus Operator resources generated by the controller
+	if err := monitoringv1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
-	// Add the Metrics Service
-	addMetrics(ctx, cfg, namespace)
+	// Setup the admission webhooks, the serving certificate must be mounted in /tmp/k8s-webhook-server/serving-certs
+	if config2.WebhooksEnabledEnvVar.Value {
+		if err := (&polkadotv1alpha1.Polkadot{}).SetupWebhookWithManager(mgr); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
+	// Add the Metrics Service, monitored from the namespace of the operator whatever the watched namespaces
+	metricsNamespace := namespace
+	if operatorNamespace, err := k8sutil.GetOperatorNamespace(); err == nil {
+		metricsNamespace = operatorNamespace
+	}
+	addMetrics(ctx, cfg, metricsNamespace)
 
 	log.Info("Starting the Cmd.")
 
@@ -136,6 +233,29 @@ func main() {
 	}
 }
 
+// getWatchNamespaces returns the namespaces watched by the operator: its own with --namespace-scoped, the ones of
+// --watch-namespaces, or the WATCH_NAMESPACE environment variable otherwise. None of them is all the namespaces
+func getWatchNamespaces() ([]string, error) {
+	if *namespaceScoped && len(*watchNamespaces) > 0 {
+		return nil, fmt.Errorf("--namespace-scoped and --watch-namespaces are exclusive")
+	}
+	if *namespaceScoped {
+		operatorNamespace, err := k8sutil.GetOperatorNamespace()
+		if err != nil {
+			return nil, err
+		}
+		return []string{operatorNamespace}, nil
+	}
+	if len(*watchNamespaces) > 0 {
+		return *watchNamespaces, nil
+	}
+	namespace, err := k8sutil.GetWatchNamespace()
+	if err != nil || namespace == "" {
+		return nil, err
+	}
+	return strings.Split(namespace, ","), nil
+}
+
 // addMetrics will create the Services and Service Monitors to allow the operator export the metrics by using
 // the Prometheus operator
 func addMetrics(ctx context.Context, cfg *rest.Config, namespace string) {
@@ -0,0 +1,22 @@
+package config
+
+// ChainPreset defines the defaults of the nodes of a well-known chain, applied to the settings not set in the CR
+type ChainPreset struct {
+	// Image of the client, the clientVersion of the CR is its tag
+	Image         string
+	P2PPort       int32
+	RPCPort       int32
+	WSPort        int32
+	MetricsPort   int32
+	CPURequest    string
+	MemoryRequest string
+}
+
+// ChainPresets are the presets of the chains passed by name as --chain. Their ports differ from chain to chain, so that
+// the nodes of several chains can share the host network of the same nodes
+var ChainPresets = map[string]ChainPreset{
+	"polkadot": {Image: "parity/polkadot", P2PPort: 30333, RPCPort: 9933, WSPort: 9944, MetricsPort: 9615, CPURequest: "1", MemoryRequest: "2Gi"},
+	"kusama":   {Image: "parity/polkadot", P2PPort: 30334, RPCPort: 9934, WSPort: 9945, MetricsPort: 9616, CPURequest: "1", MemoryRequest: "2Gi"},
+	"westend":  {Image: "parity/polkadot", P2PPort: 30335, RPCPort: 9935, WSPort: 9946, MetricsPort: 9617, CPURequest: "500m", MemoryRequest: "1Gi"},
+	"rococo":   {Image: "parity/polkadot", P2PPort: 30336, RPCPort: 9936, WSPort: 9947, MetricsPort: 9618, CPURequest: "500m", MemoryRequest: "1Gi"},
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"k8s.io/apimachinery/pkg/labels"
 	"os"
 	"strconv"
+	"time"
 )
 
 type EnvVar struct {
@@ -16,6 +18,11 @@ type EnvVarInt struct {
 	Value int
 }
 
+type EnvVarBool struct {
+	name string
+	Value bool
+}
+
 // these vars are set by the main function at the startup
 var (
 	ControllerNameEnvVar = EnvVar{"CONTROLLER_NAME", ""}
@@ -24,8 +31,41 @@ var (
 	P2PPortEnvVar   = EnvVarInt{"P2P_PORT",-1}
 	RPCPortEnvVar   = EnvVarInt{"RPC_PORT",-1}
 	WSPortEnvVar   = EnvVarInt{"WS_PORT",-1}
+	// optional, the admission webhooks need a serving certificate so they are disabled by default
+	WebhooksEnabledEnvVar = EnvVarBool{"ENABLE_WEBHOOKS",false}
 )
 
+// these vars are set by the main function from the command line flags
+var (
+	// WatchLabelSelector restricts the reconciled CustomResources to the ones matching it, all of them by default
+	WatchLabelSelector = labels.Everything()
+	// WatchNamespaces restricts the reconciled CustomResources, and the resources read by the operator, to these
+	// namespaces, all of them if empty
+	WatchNamespaces []string
+	// RequeueInterval is the period of the requeue of the successfully reconciled CustomResources, disabled if zero
+	RequeueInterval time.Duration = 0
+	// RequeueAfterCreation is the delay of the requeue after the creation of a resource not watched by the operator
+	RequeueAfterCreation = 10 * time.Second
+	// MinRequeueBackoff and MaxRequeueBackoff bound the exponential delay of the requeues after the failed reconciliations
+	MinRequeueBackoff = 1 * time.Second
+	MaxRequeueBackoff = 5 * time.Minute
+	// DryRun reconciles all the CustomResources in dry-run mode, their changes being reported instead of applied
+	DryRun = false
+)
+
+// IsNamespaceWatched tells if the resources of the namespace are visible to the operator
+func IsNamespaceWatched(namespace string) bool {
+	if len(WatchNamespaces) == 0 {
+		return true
+	}
+	for _, watchNamespace := range WatchNamespaces {
+		if watchNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // this function is called by the main at the startup
 func LoadAllEnvVar() error {
 	var err error
@@ -35,6 +75,7 @@ func LoadAllEnvVar() error {
 	if err = loadEnvVarInt(&P2PPortEnvVar); err != nil {return err }
 	if err = loadEnvVarInt(&RPCPortEnvVar); err != nil {return err }
 	if err = loadEnvVarInt(&WSPortEnvVar); err != nil {return err }
+	if err = loadOptionalEnvVarBool(&WebhooksEnabledEnvVar); err != nil {return err }
 	return err
 }
 
@@ -58,6 +99,20 @@ func loadEnvVarInt(envVar *EnvVarInt) error{
 	return err
 }
 
+// loadOptionalEnvVarBool keeps the default value if the variable is not set
+func loadOptionalEnvVarBool(envVar *EnvVarBool) error{
+	stringValue, isFound := os.LookupEnv(envVar.name)
+	if !isFound {
+		return nil
+	}
+	boolValue, err := strconv.ParseBool(stringValue)
+	if err != nil {
+		return err
+	}
+	envVar.Value = boolValue
+	return nil
+}
+
 func getEnvVar(name string) (string,error){
 	n, isFound := os.LookupEnv(name)
 	if !isFound {
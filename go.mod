@@ -5,9 +5,13 @@ module github.com/swisscom-blockchain/polkadot-k8s-operator
 go 1.13
 
 require (
+	github.com/coreos/prometheus-operator v0.34.0
 	github.com/go-logr/logr v0.1.0
+	github.com/go-logr/zapr v0.1.1
 	github.com/operator-framework/operator-sdk v0.16.0
+	github.com/prometheus/client_golang v1.2.1
 	github.com/spf13/pflag v1.0.5
+	go.uber.org/zap v1.10.0
 	k8s.io/api v0.0.0
 	k8s.io/apimachinery v0.0.0
 	k8s.io/client-go v12.0.0+incompatible
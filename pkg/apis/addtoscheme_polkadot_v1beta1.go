@@ -0,0 +1,12 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package apis
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1beta1"
+)
+
+func init() {
+	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
+	AddToSchemes = append(AddToSchemes, v1beta1.SchemeBuilder.AddToScheme)
+}
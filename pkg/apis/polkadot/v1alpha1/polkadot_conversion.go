@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the storage version, the other versions of the Polkadot API are converted to and from it
+func (*Polkadot) Hub() {}
@@ -1,8 +1,13 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -15,53 +20,928 @@ type PolkadotSpec struct {
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 
 	ClientVersion              string                     `json:"clientVersion"`
-	Kind                       string                     `json:"kind"`
+	Kind                       string                     `json:"kind,omitempty"`
 	Validator                  Validator                  `json:"validator,omitempty"`
 	Sentry                     Sentry                     `json:"sentry,omitempty"`
+	Bootnode                   Bootnode                   `json:"bootnode,omitempty"`
+	Archive                    Archive                    `json:"archive,omitempty"`
+	RpcNode                    RpcNode                    `json:"rpcNode,omitempty"`
+	Collator                   Collator                   `json:"collator,omitempty"`
 	MetricsSupport             MetricsSupport             `json:"metricsSupport"`
 	SecureCommunicationSupport SecureCommunicationSupport `json:"secureCommunicationSupport"`
+	// Metadata is added to the StatefulSets, pods and Services generated by the operator
+	Metadata ResourceMetadata `json:"metadata,omitempty"`
+	// ImagePullSecrets are the references to the secrets used to pull the client images from private registries
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Hardened generates pods satisfying the restricted Pod Security Standard: non-root user, RuntimeDefault seccomp profile,
+	// dropped capabilities and read-only root filesystem, only the data and the temporary directories being writable
+	Hardened bool `json:"hardened,omitempty"`
+	// Backup defines the scheduled snapshots of the data volumes
+	Backup Backup `json:"backup,omitempty"`
+	// Monitoring defines the Prometheus Operator resources generated for the nodes
+	Monitoring Monitoring `json:"monitoring,omitempty"`
+	// NetworkHealth defines when the nodes are reported with too few peers in the status conditions
+	NetworkHealth NetworkHealth `json:"networkHealth,omitempty"`
+	// CleanupPolicy defines the resources, not owned by the CR, deleted by the operator together with the CR
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+	// Paused suspends the creation and the update of the resources of the CR, while its status is still reported
+	Paused bool `json:"paused,omitempty"`
+	// HistoryLimit is the number of applied generations of the spec kept in the history of the status, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+	// Upgrade defines how the changes of the nodes (e.g. of the client version) are rolled out
+	Upgrade Upgrade `json:"upgrade,omitempty"`
+	// Payouts defines the scheduled payout of the staking rewards of validators
+	Payouts Payouts `json:"payouts,omitempty"`
+	// BootnodesFrom are the Polkadot CRs of Kind Bootnode whose bootnodes are passed to the nodes of this CR (--bootnodes)
+	BootnodesFrom []PolkadotReference `json:"bootnodesFrom,omitempty"`
+	// Ports are the ports the nodes listen on, propagated to their arguments, their containers, their Services and their probes
+	Ports Ports `json:"ports,omitempty"`
+	// Chain is the chain of the nodes, passed as --chain: a well-known chain, whose presets (image, ports and resource requests)
+	// apply to the settings not set, or "custom" for the chainspec of chainspecConfigMapRef. The default chain of the client if empty
+	// +kubebuilder:validation:Enum=polkadot;kusama;westend;rococo;custom
+	Chain string `json:"chain,omitempty"`
+	// ChainspecConfigMapRef selects the key of a ConfigMap holding the raw chainspec JSON of a custom chain (e.g. of a private
+	// network), mounted in the nodes and passed as --chain. For the collators, it is the chain of the embedded relay chain node
+	ChainspecConfigMapRef *corev1.ConfigMapKeySelector `json:"chainspecConfigMapRef,omitempty"`
+	// ChainspecBuilder builds the chainspec of a private network from its genesis parameters, run by the nodes as the custom chain
+	ChainspecBuilder ChainspecBuilder `json:"chainspecBuilder,omitempty"`
+	// NodePools are the pools of nodes deployed by the CR, in place of the roles of the Kind (left empty), so that several
+	// roles (e.g. a validator behind its sentries, RPC nodes and archive nodes) are deployed together
+	NodePools []NodePool `json:"nodePools,omitempty"`
+}
+
+// NodePool defines the nodes of a role, its settings override the ones of the role section, which applies otherwise
+type NodePool struct {
+	// Role of the nodes of the pool, at most one pool of each role is deployed
+	// +kubebuilder:validation:Enum=sentry;validator;bootnode;archive;rpcnode;collator
+	Role string `json:"role"`
+	// Replicas of the pool, the ones of the role section if not set. A single validator is deployed (two with its highAvailability)
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas,omitempty"`
+	// ExtraArgs are appended to the extraArgs of the role section
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+	// Resources of the client container, the ones of the role section if not set
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Storage defines the persistent volumes of the chain data, the dataPersistenceSupport of the role section if not set
+	Storage *DataPersistenceSupport `json:"storage,omitempty"`
+}
+
+// Ports defines the listen ports of the nodes, the ones configured in the operator (P2P_PORT, RPC_PORT, WS_PORT and
+// METRICS_PORT, by default 30333, 9933, 9944 and 9615) apply to the ports not set
+type Ports struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	P2P int32 `json:"p2p,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	RPC int32 `json:"rpc,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	WS int32 `json:"ws,omitempty"`
+	// Prometheus is the port of the metrics endpoint, served with the metrics support
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Prometheus int32 `json:"prometheus,omitempty"`
+}
+
+// PolkadotReference identifies another Polkadot CustomResource
+type PolkadotReference struct {
+	Name string `json:"name"`
+	// Namespace of the CustomResource, the one of the referencing CustomResource if not set
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type Validator struct {
-	ClientName             string                      `json:"clientName"`
-	NodeKey                string                      `json:"nodeKey"`
-	ReservedSentryID       string                      `json:"reservedSentryID,omitempty"`
+	ClientName string `json:"clientName"`
+	NodeKey    string `json:"nodeKey"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKeySecretRef *corev1.SecretKeySelector `json:"nodeKeySecretRef,omitempty"`
+	// ReservedSentryID is the peer ID of the sentries, discovered by the operator if not set
+	ReservedSentryID string `json:"reservedSentryID,omitempty"`
+	// ExternalSentries are the multiaddresses (peer ID included) of sentries running in other clusters, reserved by the
+	// validator together with the sentries of the CR. The validator only connects to its reserved nodes once set
+	ExternalSentries       []string                    `json:"externalSentries,omitempty"`
 	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
 	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	// KeyRotation defines when the operator rotates the session keys of the validator
+	KeyRotation KeyRotation `json:"keyRotation,omitempty"`
+	// Staking bonds the stash of the validator and registers its rotated session keys on chain
+	Staking Staking `json:"staking,omitempty"`
+	// SlashDetection watches the offences and the slashes of the stash of the validator
+	SlashDetection SlashDetection `json:"slashDetection,omitempty"`
+	// EraExporter injects a sidecar in the validator pod, exporting the era points and the session progress of the stash
+	EraExporter EraExporter `json:"eraExporter,omitempty"`
+	// KeystoreSecretRef is the Secret holding the keystore files of the session keys, mounted as the keystore of the validator
+	KeystoreSecretRef *corev1.LocalObjectReference `json:"keystoreSecretRef,omitempty"`
+	// RemoteSigner makes the validator sign with keys held by a remote keystore service instead of its filesystem
+	RemoteSigner *RemoteSigner `json:"remoteSigner,omitempty"`
+	// HighAvailability runs a standby validator, activated by the operator when the active one is down
+	HighAvailability HighAvailability `json:"highAvailability,omitempty"`
+	NodeOptions      `json:",inline"`
+}
+
+// HighAvailability defines the active/passive mode of the validator: two pods are deployed, only the active one runs with
+// the --validator role and the session keys, the standby one is a synced full node
+type HighAvailability struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// FailoverDelay is how long the active validator is not ready before the operator fails over to the standby one, 2m by default
+	FailoverDelay *metav1.Duration `json:"failoverDelay,omitempty"`
+	// LeaderElection makes the validator pods elect the active one through a Lease, instead of the failover of the operator
+	LeaderElection LeaderElection `json:"leaderElection,omitempty"`
+}
+
+// LeaderElection defines the election of the active validator through a coordination.k8s.io Lease, acquired and renewed
+// by an elector sidecar of the pods. The client of a pod switches between the validator and the standby node with the leadership
+type LeaderElection struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// LeaseDuration is how long the leadership lasts without being renewed before another pod can take it, 30s by default
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+	// Image of the elector sidecar, it must provide kubectl and GNU date. "bitnami/kubectl" if not set
+	Image string `json:"image,omitempty"`
+}
+
+// RemoteSigner defines the remote keystore service signing for the validator
+type RemoteSigner struct {
+	// Endpoint is the URI of the keystore service, passed as --keystore-uri (e.g. http://localhost:8000 for a sidecar)
+	Endpoint string `json:"endpoint"`
+	// Sidecar is the optional signer container injected in the validator pod
+	Sidecar *corev1.Container `json:"sidecar,omitempty"`
+}
+
+// KeyRotation defines the rotation of the session keys of the validator through the author_rotateKeys RPC
+type KeyRotation struct {
+	// OnFirstStart rotates the session keys once the validator is ready for the first time
+	OnFirstStart bool `json:"onFirstStart,omitempty"`
+	// Trigger requests a new rotation whenever its value changes (e.g. the current date)
+	Trigger string `json:"trigger,omitempty"`
+	// SecretName is the optional Secret, managed by the operator, the result of the last rotation is written to
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// Staking defines the on-chain bootstrap of the validator, run by a Job once its session keys are rotated
+type Staking struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Stash is the SS58 address of the stash account, bonded if it is not bonded yet
+	Stash string `json:"stash,omitempty"`
+	// StashSecretRef selects the key of a Secret holding the secret seed of the stash, signing the bond
+	StashSecretRef *corev1.SecretKeySelector `json:"stashSecretRef,omitempty"`
+	// Controller is the SS58 address of the controller account, the stash itself if empty
+	Controller string `json:"controller,omitempty"`
+	// ControllerSecretRef selects the key of a Secret holding the secret seed of the controller, signing the session keys
+	// and the validator preferences. The stash signs them if empty
+	ControllerSecretRef *corev1.SecretKeySelector `json:"controllerSecretRef,omitempty"`
+	// Bond is the amount bonded by the stash, in the smallest unit of the chain (e.g. planck)
+	Bond string `json:"bond,omitempty"`
+	// Validate declares the intention to validate with staking.validate once the session keys are set
+	Validate bool `json:"validate,omitempty"`
+	// Commission is the commission of the validator in percent, with Validate
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Commission int32 `json:"commission,omitempty"`
+	// Image runs the bootstrap job and must provide polkadot-js-api, jacogr/polkadot-js-tools by default
+	Image string `json:"image,omitempty"`
+}
+
+// SlashDetection defines the monitoring of the stash of the validator on chain: the unapplied slashes of the reported
+// offences and the slashes recorded in its slashing spans are read at each reconcile
+type SlashDetection struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Stash is the SS58 address of the watched stash, the stash of the staking bootstrap if empty
+	Stash string `json:"stash,omitempty"`
+	// RecentEras is the number of eras a slash keeps the Slashed condition true, 7 by default
+	// +kubebuilder:validation:Minimum=1
+	RecentEras int32 `json:"recentEras,omitempty"`
+}
+
+// EraExporter defines the sidecar of the validator publishing the staking state of the stash as Prometheus metrics: its
+// era points, its membership of the active set and the progress of the session and of the era, read from the local node
+type EraExporter struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Stash is the SS58 address of the exported stash, the stash of the staking bootstrap if empty
+	Stash string `json:"stash,omitempty"`
+	// Port serves the metrics on /metrics, 9618 by default
+	Port int32 `json:"port,omitempty"`
+	// Image of the sidecar, it must provide polkadot-js-api and node. jacogr/polkadot-js-tools by default
+	Image string `json:"image,omitempty"`
+	// Resources of the sidecar
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type Sentry struct {
-	Replicas               int32                       `json:"replicas"`
-	ClientName             string                      `json:"clientName"`
-	NodeKey                string                      `json:"nodeKey"`
-	ReservedValidatorID    string                      `json:"reservedValidatorID,omitempty"`
+	Replicas   int32  `json:"replicas"`
+	ClientName string `json:"clientName"`
+	NodeKey    string `json:"nodeKey"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKeySecretRef *corev1.SecretKeySelector `json:"nodeKeySecretRef,omitempty"`
+	// ReservedValidatorID is the peer ID of the validator, discovered by the operator if not set
+	ReservedValidatorID string `json:"reservedValidatorID,omitempty"`
+	// ExternalValidators are the multiaddresses (peer ID included) of validators running in other clusters, reserved by
+	// the sentries
+	ExternalValidators []string `json:"externalValidators,omitempty"`
+	// ExternalValidatorAddr is the multiaddress (without peer ID) of a validator running outside of Kubernetes, e.g. on
+	// bare metal, fronted by the sentries of a CR without validator. It is reserved with the peer ID reservedValidatorID
+	ExternalValidatorAddr  string                      `json:"externalValidatorAddr,omitempty"`
+	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
+	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	NodeOptions            `json:",inline"`
+}
+
+// Bootnode defines a set of nodes with stable public P2P identities, usable as chain bootnodes
+type Bootnode struct {
+	Replicas   int32  `json:"replicas"`
+	ClientName string `json:"clientName"`
+	// NodeKeys are the private identities of the bootnodes, one for each replica (ordered by pod ordinal)
+	NodeKeys               []string                    `json:"nodeKeys"`
+	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
+	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	NodeOptions            `json:",inline"`
+}
+
+// Archive defines a set of nodes keeping the whole history of the chain state (e.g. for indexers)
+type Archive struct {
+	Replicas   int32  `json:"replicas"`
+	ClientName string `json:"clientName"`
+	NodeKey    string `json:"nodeKey,omitempty"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKeySecretRef       *corev1.SecretKeySelector   `json:"nodeKeySecretRef,omitempty"`
+	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
+	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	NodeOptions            `json:",inline"`
+}
+
+// RpcNode defines a set of full nodes serving RPC and WebSocket traffic (e.g. for dApps) behind a load balancer.
+// Besides the RpcNode kind, it is deployed next to the other kinds whenever replicas is greater than zero
+type RpcNode struct {
+	Replicas   int32  `json:"replicas"`
+	ClientName string `json:"clientName"`
+	NodeKey    string `json:"nodeKey,omitempty"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKeySecretRef       *corev1.SecretKeySelector   `json:"nodeKeySecretRef,omitempty"`
 	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
 	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	NodeOptions            `json:",inline"`
+}
+
+// Collator defines a set of parachain collators, each one embedding a relay chain node
+type Collator struct {
+	Replicas   int32  `json:"replicas"`
+	ClientName string `json:"clientName"`
+	NodeKey    string `json:"nodeKey,omitempty"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKeySecretRef       *corev1.SecretKeySelector   `json:"nodeKeySecretRef,omitempty"`
+	Resources              corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,opt,name=resources"`
+	DataPersistenceSupport DataPersistenceSupport      `json:"dataPersistenceSupport"`
+	NodeOptions            `json:",inline"`
+	// Image of the parachain client, tag included (e.g. "parity/polkadot-collator:latest")
+	Image string `json:"image"`
+	// Binary is the executable of the parachain client inside the image, "polkadot-collator" if not set
+	Binary string `json:"binary,omitempty"`
+	// ParachainChainSpec is the chain of the parachain, passed as --chain (built-in name or path of the raw chainspec)
+	ParachainChainSpec string `json:"parachainChainSpec"`
+	// RelayChainSpec is the chain of the embedded relay chain node, passed as --chain after the "--" separator (e.g. relay.json)
+	RelayChainSpec string `json:"relayChainSpec"`
+	// RelayChainEndpoint is the optional RPC endpoint of a relay chain node, used instead of the embedded relay chain node
+	RelayChainEndpoint string `json:"relayChainEndpoint,omitempty"`
+}
+
+// NodeOptions defines the settings shared by all the node roles
+type NodeOptions struct {
+	// NodeSelector constrains the pods of the role to the nodes having the given labels
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity defines the scheduling constraints of the pods of the role
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// AntiAffinity injects a default pod anti-affinity spreading the pods of the nodes across the zones and the hosts,
+	// ignored if the Affinity of the role already defines a podAntiAffinity
+	AntiAffinity AntiAffinity `json:"antiAffinity,omitempty"`
+	// Tolerations allow the pods of the role to be scheduled on tainted nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// TopologySpreadConstraints define how the pods of the role are spread across the topology domains (e.g. zones, nodes)
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName defines the scheduling priority of the pods of the role (e.g. to avoid the preemption of the validator)
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// HostNetwork runs the pods of the role in the network namespace of their node, e.g. to peer without NAT on bare-metal.
+	// The ports of the client are reserved on the node, so at most one pod listening on them is scheduled on each node
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// SyncMode is the blockchain synchronization mode of the node (--sync)
+	// +kubebuilder:validation:Enum=full;fast;warp
+	SyncMode string `json:"syncMode,omitempty"`
+	// StatePruning is the number of recent block states kept by the node, or "archive" to keep all of them (--pruning)
+	// +kubebuilder:validation:Pattern="^(archive|archive-canonical|[0-9]+)$"
+	StatePruning string `json:"statePruning,omitempty"`
+	// BlocksPruning is the number of recent finalized blocks kept by the node, or "archive" to keep all of them (--blocks-pruning)
+	// +kubebuilder:validation:Pattern="^(archive|archive-canonical|[0-9]+)$"
+	BlocksPruning string `json:"blocksPruning,omitempty"`
+	// TelemetryURL is the telemetry endpoint the node reports to, instead of the default one of the chain (--telemetry-url)
+	TelemetryURL string `json:"telemetryUrl,omitempty"`
+	// TelemetryVerbosity is the verbosity of the messages sent to the TelemetryURL, from 0 to 9
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=9
+	TelemetryVerbosity int32 `json:"telemetryVerbosity,omitempty"`
+	// NoTelemetry disables the telemetry of the node (--no-telemetry)
+	NoTelemetry bool `json:"noTelemetry,omitempty"`
+	// ExtraArgs are appended to the arguments of the client generated by the operator
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+	// Env defines the environment variables of the client container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// PodSecurityContext overrides the default pod security context (non root user and group 1000)
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityContext defines the security options of the client container (e.g. readOnlyRootFilesystem, capabilities)
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// ServiceAccountName is the ServiceAccount the pods of the role run with
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// CreateServiceAccount makes the operator create the ServiceAccount named by serviceAccountName
+	CreateServiceAccount bool `json:"createServiceAccount,omitempty"`
+	// ServiceAccountAnnotations are added to the ServiceAccount created by the operator (e.g. to bind a cloud IAM role)
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// Vault makes the Vault Agent injector fetch the keys of the node from Vault at the pod start
+	Vault *Vault `json:"vault,omitempty"`
+	// Maintenance takes the role out of the reconciliation, while the other roles are still reconciled
+	Maintenance Maintenance `json:"maintenance,omitempty"`
+	// Autoscaling makes the operator create a HorizontalPodAutoscaler scaling the StatefulSet of the role
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+	// VerticalAutoscaling makes the operator create a VerticalPodAutoscaler right-sizing the resources of the pods of the role
+	VerticalAutoscaling VerticalAutoscaling `json:"verticalAutoscaling,omitempty"`
+	// PodDisruptionBudget makes the operator create a PodDisruptionBudget limiting the voluntary evictions of the pods of the role
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+	// UpdateStrategy is the update strategy of the StatefulSet of the role, RollingUpdate if not set. With OnDelete, a new
+	// revision is staged by the operator and each pod is only updated once deleted by the user
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// PodManagementPolicy is OrderedReady to create and delete the pods of the role one at a time, or Parallel to start them
+	// all at once (e.g. a scale up of a large sentry pool). OrderedReady if not set
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// ServiceType is the type of the Service of the role, the default one of the role if not set (e.g. NodePort for the sentries)
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+	// Ingress makes the operator create an Ingress routing the HTTPS RPC and the WSS traffic to the Service of the role
+	Ingress Ingress `json:"ingress,omitempty"`
+	// ExternalDNS makes the operator annotate the Service and the Ingress of the role for external-dns
+	ExternalDNS ExternalDNS `json:"externalDNS,omitempty"`
+	// AutoPublicAddr makes the operator pass the address external peers reach the role at as --public-addr, resolved from
+	// its Service (LoadBalancer or NodePort) or from the node of each pod on the host network
+	AutoPublicAddr bool `json:"autoPublicAddr,omitempty"`
+	// GenerateNodeKeys makes the operator generate a node key for each pod of the role, stored in a Secret per ordinal
+	// (e.g. <cr>-sentry-0-nodekey), so that every replica keeps a distinct and stable peer identity
+	GenerateNodeKeys bool `json:"generateNodeKeys,omitempty"`
+	// Probes defines the startup, liveness and readiness probes of the client container
+	Probes Probes `json:"probes,omitempty"`
+	// StallDetection restarts the client once its best block has not advanced for a while, e.g. a node stuck on a fork
+	StallDetection StallDetection `json:"stallDetection,omitempty"`
+	// SyncReadiness keeps the pods of the role not ready until their node is synced, so that the Services of the role only
+	// route to synced nodes
+	SyncReadiness SyncReadiness `json:"syncReadiness,omitempty"`
+	// Sidecars are containers appended to the pods of the role, e.g. exporters, log shippers or watchdogs. Their schema is
+	// left out of the CRD, which would otherwise exceed the size limit of the API server, they are validated by the webhook
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// InitContainers run in the pods of the role before the client starts, after the init containers of the operator, e.g.
+	// to fetch keys or download a snapshot into the data volume. Like the sidecars, their schema is left out of the CRD
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// ClientVersion is the image tag of the client of the role, the clientVersion of the CR if empty, e.g. to canary a new
+	// client version on the sentries before rolling it out to the validator
+	ClientVersion string `json:"clientVersion,omitempty"`
+	// ClientImage is the image repository of the client of the role, without tag, the one of the chain otherwise
+	ClientImage string `json:"clientImage,omitempty"`
+}
+
+// Ingress defines the Ingress of the RPC and WebSocket endpoints of a role, e.g. of the RPC nodes
+type Ingress struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Host is the host name of the RPC endpoint
+	Host string `json:"host,omitempty"`
+	// WSHost is the host name of the WebSocket endpoint, the WebSocket traffic is routed from the /ws path of the Host if not set
+	WSHost string `json:"wsHost,omitempty"`
+	// TLSSecretName is the Secret holding the certificate of the hosts, terminating HTTPS and WSS at the ingress controller
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// IssuerRef makes the operator request the certificate of the hosts from a cert-manager Issuer, issued into the TLSSecretName
+	// Secret, or into <StatefulSet name>-tls if not set
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+	// ClassName selects the ingress controller, set as the kubernetes.io/ingress.class annotation
+	ClassName string `json:"className,omitempty"`
+	// Annotations are added to the Ingress, e.g. the settings of the ingress controller
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExternalDNS defines the DNS records published by external-dns for a role, external-dns must be installed in the cluster
+type ExternalDNS struct {
+	// Hostname is the DNS name of the Service of the role, e.g. a stable public name of the sentries usable in --public-addr.
+	// The dedicated Service of each bootnode is published as <ordinal>.<hostname>
+	Hostname string `json:"hostname,omitempty"`
+	// TTL is the time to live of the DNS records in seconds, the default one of external-dns if not set
+	// +kubebuilder:validation:Minimum=1
+	TTL *int32 `json:"ttl,omitempty"`
+}
+
+// Probes defines the HTTP probes of the health endpoint served on the RPC port of the client
+type Probes struct {
+	// Startup holds the liveness and readiness probes back until the health endpoint succeeds once, e.g. for the days of
+	// the initial sync. Its period and failure threshold are 60 and 10080 (one week) by default
+	Startup   HealthProbe `json:"startup,omitempty"`
+	Liveness  HealthProbe `json:"liveness,omitempty"`
+	Readiness HealthProbe `json:"readiness,omitempty"`
+}
+
+// HealthProbe defines a probe of the health endpoint of the client, the defaults of the operator apply to the settings not set
+type HealthProbe struct {
+	// Path of the health endpoint, /health by default (e.g. /health/readiness on the clients serving it, failing while the
+	// node is syncing or has no peer)
+	Path string `json:"path,omitempty"`
+	// InitialDelaySeconds is the delay before the first probe, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is the interval between the probes, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the timeout of a probe, 1 by default
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failures after which the probe fails, 3 by default
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// StallDetection defines the liveness of the client based on the progression of its best block, polled by a sidecar
+type StallDetection struct {
+	Enabled bool `json:"enabled"`
+	// Timeout is the duration without a new best block after which the client is restarted, 10m by default
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Image of the sidecar polling the best block, it must provide sh, wget and sed, alpine by default
+	Image string `json:"image,omitempty"`
+}
+
+// SyncReadiness defines the readiness of the pods based on the sync state of their node, polled by a sidecar
+type SyncReadiness struct {
+	Enabled bool `json:"enabled"`
+	// MaxBlocksBehind is the number of blocks a ready node may be behind the highest block announced by its peers, 5 by default
+	// +kubebuilder:validation:Minimum=0
+	MaxBlocksBehind *int32 `json:"maxBlocksBehind,omitempty"`
+	// Image of the sidecar polling the sync state, it must provide sh, wget, sed and grep, alpine by default
+	Image string `json:"image,omitempty"`
+}
+
+// IssuerReference selects the cert-manager Issuer signing the certificate of an Ingress
+type IssuerReference struct {
+	Name string `json:"name"`
+	// Kind is Issuer, in the namespace of the CR (default), or ClusterIssuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// PodDisruptionBudget defines the PodDisruptionBudget of a role, e.g. so that a node drain does not evict all the sentries at once
+type PodDisruptionBudget struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxUnavailable is the number, or the percentage, of pods of the role which can be evicted at the same time, 1 by default
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// VerticalAutoscaling defines the VerticalPodAutoscaler of a role, the VPA components must be installed in the cluster
+type VerticalAutoscaling struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// UpdateMode is Off to only publish the recommendations in the status of the VerticalPodAutoscaler (default),
+	// Initial to apply them to the new pods, Recreate or Auto to also evict the running pods
+	// +kubebuilder:validation:Enum=Off;Initial;Recreate;Auto
+	UpdateMode string `json:"updateMode,omitempty"`
+	// MinAllowed is the lower limit of the recommended resources of the client container
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+	// MaxAllowed is the upper limit of the recommended resources of the client container
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+}
+
+// Autoscaling defines the HorizontalPodAutoscaler of a role, the replicas of the role are then the initial ones only
+type Autoscaling struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// MinReplicas is the lower limit of the replicas, the replicas of the role if not set
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit of the replicas
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the pods targeted by the autoscaler, 80 by default
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// Metrics replace the CPU utilization target with custom metrics (e.g. the RPC requests rate)
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+	// Keda makes the operator create a KEDA ScaledObject, driven by Prometheus queries, instead of the HorizontalPodAutoscaler
+	Keda *Keda `json:"keda,omitempty"`
+}
+
+// Keda defines the ScaledObject of a role, the KEDA operator must be installed in the cluster
+type Keda struct {
+	// ServerAddress is the address of the Prometheus server evaluating the queries (e.g. http://prometheus.monitoring:9090)
+	ServerAddress string `json:"serverAddress"`
+	// Triggers are the Prometheus queries scaling the role, the RPC requests rate and the WebSocket connections of the role if not set
+	Triggers []KedaTrigger `json:"triggers,omitempty"`
+	// PollingInterval is the interval in seconds between the evaluations of the triggers, 30 by default
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// CooldownPeriod is the period in seconds waited after the last active trigger before scaling down, 300 by default
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+}
+
+// KedaTrigger defines a Prometheus query scaling a role
+type KedaTrigger struct {
+	MetricName string `json:"metricName"`
+	// Query is the PromQL query, its value is divided by the Threshold to compute the replicas
+	Query string `json:"query"`
+	// Threshold is the target value of the query for each replica (e.g. "100")
+	Threshold string `json:"threshold"`
+}
+
+// AntiAffinity defines the default pod anti-affinity of a role, so that a single node or zone failure does not take out all its pods
+type AntiAffinity struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Mode is Preferred to only prefer the pods on different zones and hosts, or Required to reject two pods on the same host
+	// (the zones are still preferred). Preferred if not set
+	// +kubebuilder:validation:Enum=Preferred;Required
+	Mode string `json:"mode,omitempty"`
+}
+
+// Maintenance defines how the nodes of a role are handled during a manual intervention (e.g. on the data volumes of the validator)
+type Maintenance struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Mode is ScaleDown to scale the StatefulSet of the role to zero, or Freeze to stop updating it. ScaleDown if not set
+	// +kubebuilder:validation:Enum=ScaleDown;Freeze
+	Mode string `json:"mode,omitempty"`
+}
+
+// Vault defines the keys fetched from the KV secrets engine (version 2) of Vault by the Vault Agent injector
+type Vault struct {
+	// Role is the Vault Kubernetes auth role of the pods, bound to their ServiceAccount
+	Role string `json:"role"`
+	// NodeKey is the secret holding the node key, passed as --node-key-file instead of nodeKey
+	NodeKey *VaultSecret `json:"nodeKey,omitempty"`
+	// Keystore are the secrets holding the keystore files of the session keys, passed as --keystore-path
+	Keystore []VaultSecret `json:"keystore,omitempty"`
+}
+
+// VaultSecret selects a field of a Vault secret, rendered as a file in the pod
+type VaultSecret struct {
+	// Path of the secret (e.g. secret/data/polkadot/validator)
+	Path string `json:"path"`
+	// Field of the secret holding the value
+	Field string `json:"field"`
+	// FileName is the name of the keystore file (e.g. the key type and the hex public key), not used by the nodeKey
+	FileName string `json:"fileName,omitempty"`
 }
 
 type DataPersistenceSupport struct {
 	Enabled               bool                         `json:"enabled"`
 	PersistentVolumeClaim corev1.PersistentVolumeClaim `json:"persistentVolumeClaim,omitempty" protobuf:"bytes,name=volumeClaimTemplates"`
+	// StorageClassName overrides the storage class of the persistentVolumeClaim
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// StorageSize overrides the requested storage of the persistentVolumeClaim, it can only grow
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+	// AccessMode overrides the access modes of the persistentVolumeClaim
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// RestoreFrom provisions the volumes of a newly created StatefulSet from an existing snapshot
+	RestoreFrom *RestoreFrom `json:"restoreFrom,omitempty"`
+	// Bootstrap fills the empty volumes with a chain database snapshot before the node starts
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+}
+
+// Bootstrap defines the chain database archive downloaded into the data volume by an init container
+type Bootstrap struct {
+	// URL of the tar archive (optionally compressed) of the chain database, e.g. a public or pre-signed S3/GCS https URL
+	URL string `json:"url"`
+	// Checksum is the optional sha256 of the archive, verified before the extraction
+	Checksum string `json:"checksum,omitempty"`
+	// Image runs the bootstrap and must provide wget, sha256sum and tar, alpine by default
+	Image string `json:"image,omitempty"`
+}
+
+// RestoreFrom defines the source of the data of the volumes to be created
+type RestoreFrom struct {
+	// VolumeSnapshotName is the CSI VolumeSnapshot, in the namespace of the CR, the volumes are provisioned from
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+}
+
+// ResourceMetadata defines labels and annotations propagated to the generated resources
+type ResourceMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type MetricsSupport struct {
 	Enabled bool `json:"enabled"`
 }
 
+// Monitoring defines the PodMonitor scraping the metrics of the nodes, the metricsSupport has to be enabled too
+type Monitoring struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval between the scrapes, e.g. 30s. Defaults to the interval of the Prometheus instance
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the generated resources, to match the selectors of the Prometheus instance
+	Labels map[string]string `json:"labels,omitempty"`
+	// Alerts defines the PrometheusRule with the health alerts of the nodes
+	Alerts Alerts `json:"alerts,omitempty"`
+	// GrafanaDashboards generates a ConfigMap with the dashboard of each role, loaded by the Grafana sidecar
+	GrafanaDashboards bool `json:"grafanaDashboards,omitempty"`
+}
+
+type Alerts struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// MinPeers is the peer count under which a node is reported, 3 by default
+	MinPeers int32 `json:"minPeers,omitempty"`
+}
+
+// NetworkHealth defines the NetworkHealthy and Degraded conditions of the status
+type NetworkHealth struct {
+	// MinPeers is the peer count under which a node is not healthy, 3 by default
+	MinPeers int32 `json:"minPeers,omitempty"`
+	// Period is how long a node can stay under MinPeers before the CR is Degraded, 5m by default
+	Period *metav1.Duration `json:"period,omitempty"`
+}
+
+// Upgrade defines the rollout of the changes of the pods of the StatefulSets
+type Upgrade struct {
+	// SyncGated rolls the pods of a role one at a time, moving to the next pod only once the updated node
+	// is synced and has at least networkHealth.minPeers peers
+	SyncGated bool `json:"syncGated,omitempty"`
+	// Canary rolls the changes out to the pod with the highest ordinal first, the other pods are upgraded
+	// once the canary has been healthy for the soak time
+	Canary Canary `json:"canary,omitempty"`
+	// ImagePinning pins the pods to the digests the client images resolve to, so that a moving tag (e.g. "latest") can not
+	// change the client of the nodes silently
+	ImagePinning ImagePinning `json:"imagePinning,omitempty"`
+}
+
+// ImagePinning defines the resolution of the client image tags to digests
+type ImagePinning struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// AutoUpdate rolls the new digest of a tag out once it is resolved, otherwise it is only reported in the status
+	AutoUpdate bool `json:"autoUpdate,omitempty"`
+	// RefreshPeriod is how often the pinned tags are resolved again to track their updates, 1h by default
+	RefreshPeriod *metav1.Duration `json:"refreshPeriod,omitempty"`
+}
+
+// Canary defines the canary pod of the upgrades
+type Canary struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// SoakTime is how long the canary node must stay synced with enough peers before the upgrade goes on, 10m by default
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// CleanupPolicy defines the resources deleted before the CR is removed, they are kept by default
+type CleanupPolicy struct {
+	// DeleteVolumes deletes the data volumes (PersistentVolumeClaims) of the nodes
+	DeleteVolumes bool `json:"deleteVolumes,omitempty"`
+	// DeleteSnapshots deletes the VolumeSnapshots taken by the backups
+	DeleteSnapshots bool `json:"deleteSnapshots,omitempty"`
+}
+
 type SecureCommunicationSupport struct {
 	Enabled bool `json:"enabled"`
 }
 
+// ChainspecBuilder defines the genesis of a private network. Its raw chainspec is built by a Job from a built-in chain of the
+// client and stored in the "chainspec" ConfigMap. The genesis follows the runtime of the substrate node template (aura, grandpa,
+// balances and sudo pallets), the settings not set are the ones of the base chain
+type ChainspecBuilder struct {
+	Enabled bool `json:"enabled"`
+	// BaseChain is the built-in chain of the client the chainspec is derived from, "local" by default
+	BaseChain string `json:"baseChain,omitempty"`
+	// Authorities are the block production and finality authorities of the genesis
+	Authorities []GenesisAuthority `json:"authorities,omitempty"`
+	// Balances are the endowed accounts of the genesis
+	Balances []GenesisBalance `json:"balances,omitempty"`
+	// SudoKey is the SS58 address of the sudo account
+	SudoKey string `json:"sudoKey,omitempty"`
+	// Image runs the genesis and the ConfigMap steps of the Job and must provide jq and kubectl, bitnami/kubectl by default
+	Image string `json:"image,omitempty"`
+}
+
+// GenesisAuthority defines the session keys of an authority of the genesis
+type GenesisAuthority struct {
+	// Aura is the SS58 address of the sr25519 block production key
+	Aura string `json:"aura"`
+	// Grandpa is the SS58 address of the ed25519 finality key
+	Grandpa string `json:"grandpa"`
+}
+
+// GenesisBalance defines an endowed account of the genesis
+type GenesisBalance struct {
+	// Address is the SS58 address of the account
+	Address string `json:"address"`
+	// Amount is the free balance of the account, in the smallest unit of the chain
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	Amount string `json:"amount"`
+}
+
+// Backup defines the CSI VolumeSnapshots of the data volumes, taken by a CronJob managed by the operator
+type Backup struct {
+	Enabled bool `json:"enabled"`
+	// Schedule is the cron expression of the backups, daily at midnight by default
+	Schedule string `json:"schedule,omitempty"`
+	// SnapshotClass is the VolumeSnapshotClass of the snapshots, the default class of the cluster if empty
+	SnapshotClass string `json:"snapshotClass,omitempty"`
+	// Retention is the number of snapshots kept for each volume, the older ones are deleted (7 by default)
+	Retention int32 `json:"retention,omitempty"`
+	// Image runs the backup job and must provide kubectl, bitnami/kubectl by default
+	Image string `json:"image,omitempty"`
+}
+
+// Payouts defines the scheduled payout of the staking rewards of validators
+type Payouts struct {
+	Enabled bool `json:"enabled"`
+	// Schedule is the cron expression of the payouts, daily at 01:00 by default
+	Schedule string `json:"schedule,omitempty"`
+	// Stashes are the SS58 addresses of the validator stashes whose rewards are paid out
+	Stashes []string `json:"stashes,omitempty"`
+	// AccountSecretRef selects the key of a Secret holding the secret seed of the account submitting the payouts, e.g. a
+	// proxy account. It only pays the transaction fees and must be funded
+	AccountSecretRef *corev1.SecretKeySelector `json:"accountSecretRef,omitempty"`
+	// Eras is the number of past eras checked for unclaimed rewards at each run, 4 by default
+	// +kubebuilder:validation:Minimum=1
+	Eras int32 `json:"eras,omitempty"`
+	// Image runs the payout job and must provide polkadot-js-api, jacogr/polkadot-js-tools by default
+	Image string `json:"image,omitempty"`
+}
+
 // PolkadotStatus defines the observed state of Polkadot
 type PolkadotStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 
-	// TODO add observable status here
+	// Nodes are the names of the pods expected to run for this CustomResource
+	Nodes []string `json:"nodes,omitempty"`
+	// Roles reports the observed state of every node role deployed by this CustomResource
+	Roles []RoleStatus `json:"roles,omitempty"`
+	// Replicas is the number of nodes expected to run for this CustomResource
+	Replicas int32 `json:"replicas,omitempty"`
+	// Synced is true if the nodes reachable by the operator are all synced
+	Synced bool `json:"synced"`
+	// ObservedGeneration is the most recent generation of the CustomResource handled by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastReconcileTime is the last time the operator completed a reconcile of this CustomResource
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+	// SessionKeys are the public session keys of the validator returned by the last rotation
+	SessionKeys *SessionKeysStatus `json:"sessionKeys,omitempty"`
+	// Failover reports the active validator pod of the high availability mode
+	Failover *FailoverStatus `json:"failover,omitempty"`
+	// ReservedPeers reports the peer IDs of the validator and of the sentries, wired with each other as reserved nodes
+	ReservedPeers *ReservedPeersStatus `json:"reservedPeers,omitempty"`
+	// Bootnodes are the addresses of the bootnodes resolved from bootnodesFrom
+	Bootnodes []string `json:"bootnodes,omitempty"`
+	// PublicAddresses are the --public-addr resolved for the roles enabling autoPublicAddr
+	PublicAddresses []PublicAddress `json:"publicAddresses,omitempty"`
+	// SentryAddresses are the public multiaddresses of the sentries of the CR, peer IDs included, to be listed in the
+	// externalSentries of the validators of other clusters
+	SentryAddresses []string `json:"sentryAddresses,omitempty"`
+	// Payouts reports the outcome of the last payout job
+	Payouts *PayoutsStatus `json:"payouts,omitempty"`
+	// Slashes reports the offences and the slashes of the stash of the validator, when its slashDetection is enabled
+	Slashes *SlashesStatus `json:"slashes,omitempty"`
+	// PinnedImages are the digests the client images are pinned to, when the imagePinning of the upgrades is enabled
+	PinnedImages []PinnedImage `json:"pinnedImages,omitempty"`
+	// History lists the last generations of the spec applied by the operator, the most recent first
+	History []AppliedGeneration `json:"history,omitempty"`
+	// DryRun reports the changes the operator would make to the resources of the CR, while it is reconciled in dry-run mode
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// Conditions are the latest observations of the state of the CustomResource (NetworkHealthy, Degraded, Paused,
+	// OffenceDetected, Slashed)
+	Conditions []PolkadotCondition `json:"conditions,omitempty"`
+}
+
+// PolkadotCondition defines an observation of the state of the CustomResource
+type PolkadotCondition struct {
+	Type   string                 `json:"type"`
+	Status corev1.ConditionStatus `json:"status"`
+	// LastTransitionTime is the last time the condition changed its status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+}
+
+// SessionKeysStatus defines the result of a session keys rotation, to be submitted on chain with session.setKeys
+type SessionKeysStatus struct {
+	// PublicKeys are the concatenated public session keys (hex encoded)
+	PublicKeys string `json:"publicKeys"`
+	// Trigger is the keyRotation trigger the rotation has been done for
+	Trigger string `json:"trigger,omitempty"`
+	// RotationTime is the time of the rotation
+	RotationTime *metav1.Time `json:"rotationTime,omitempty"`
+	// Registration is the state of the on-chain registration of the keys by the staking bootstrap (Pending, Registered, Failed)
+	Registration string `json:"registration,omitempty"`
+	// RegistrationTime is the time the keys have been registered on chain
+	RegistrationTime *metav1.Time `json:"registrationTime,omitempty"`
+}
+
+// SlashesStatus defines the offences and the slashes of the stash of the validator, as read on chain
+type SlashesStatus struct {
+	// Stash is the SS58 address of the watched stash
+	Stash string `json:"stash"`
+	// ActiveEra is the active era of the chain when the slashes have been read
+	ActiveEra int64 `json:"activeEra"`
+	// UnappliedSlashes is the number of slashes of reported offences of the stash, not applied yet
+	UnappliedSlashes int32 `json:"unappliedSlashes"`
+	// LastSlashEra is the era of the last slash recorded for the stash, if it has ever been slashed
+	LastSlashEra *int64 `json:"lastSlashEra,omitempty"`
+}
+
+// FailoverStatus defines the state of the active/passive validators
+type FailoverStatus struct {
+	// ActivePod is the validator pod allowed to run with the --validator role
+	ActivePod string `json:"activePod"`
+	// FencedPod is the previous active pod during a failover, no pod is activated until it is terminated
+	FencedPod string `json:"fencedPod,omitempty"`
+	// FencedPodUID is the UID of the fenced pod, a pod recreated with the same name is no longer fenced
+	FencedPodUID types.UID `json:"fencedPodUID,omitempty"`
+	// UnhealthySince is the time the active pod has been found not ready
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+	// LastFailoverTime is the time of the last completed failover
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+}
+
+// ReservedPeersStatus defines the peer IDs discovered by the operator for a SentryAndValidator deployment, ordered by pod
+// ordinal (a single one for the nodes sharing a node key). An empty peer ID is a pod not discovered yet
+type ReservedPeersStatus struct {
+	// Validators are the peer IDs of the validator pods, reserved by the sentries
+	Validators []string `json:"validators,omitempty"`
+	// Sentries are the peer IDs of the sentry pods, reserved by the validator
+	Sentries []string `json:"sentries,omitempty"`
+}
+
+// PublicAddress defines the multiaddress external peers reach a role at. $(NODE_IP) is the IP of the node of each pod
+type PublicAddress struct {
+	Role    string `json:"role"`
+	Address string `json:"address"`
+}
+
+// PayoutsStatus defines the outcome of the last completed payout job
+type PayoutsStatus struct {
+	// LastJob is the name of the last completed payout job
+	LastJob string `json:"lastJob"`
+	// Result is Succeeded if every unclaimed era has been paid out, Failed otherwise
+	Result string `json:"result"`
+	// CompletionTime is the time the last payout job completed or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// AppliedGeneration defines a generation of the spec applied by the operator
+type AppliedGeneration struct {
+	// Generation is the generation of the CR
+	Generation int64 `json:"generation"`
+	// Time is the time the generation has been applied, i.e. the end of the first reconciliation observing it
+	Time metav1.Time `json:"time"`
+	// ChangedFields are the fields of the spec changed since the previous applied generation, e.g. "spec.sentry.replicas"
+	ChangedFields []string `json:"changedFields,omitempty"`
+	// Actions are the creations, updates and deletions of resources applying the generation, e.g. "Updated the StatefulSet
+	// sentry-sset"
+	Actions []string `json:"actions,omitempty"`
+}
+
+// DryRunStatus defines the outcome of the last dry-run reconciliation
+type DryRunStatus struct {
+	// Time is the time the changes were reported, they are reported again only when they differ
+	Time metav1.Time `json:"time"`
+	// Changes are the creations, updates and deletions of resources the reconciliation would have made, e.g.
+	// "update StatefulSet polkadot-sentry: spec.replicas"
+	Changes []string `json:"changes,omitempty"`
+}
+
+// PinnedImage defines the digest a client image tag is pinned to
+type PinnedImage struct {
+	// Image is the client image, tag included, as generated from the spec
+	Image string `json:"image"`
+	// Digest is the digest of the image the pods run (e.g. "sha256:0123...")
+	Digest string `json:"digest"`
+	// ResolvedTime is the last time the tag has been resolved
+	ResolvedTime metav1.Time `json:"resolvedTime,omitempty"`
+	// AvailableDigest is the newer digest the tag resolves to, not rolled out as autoUpdate is disabled
+	AvailableDigest string `json:"availableDigest,omitempty"`
+}
+
+// RoleStatus defines the observed state of a single node role (e.g. sentry, validator)
+type RoleStatus struct {
+	Role            string `json:"role"`
+	StatefulSetName string `json:"statefulSetName"`
+	ServiceName     string `json:"serviceName"`
+	Replicas        int32  `json:"replicas"`
+	ReadyReplicas   int32  `json:"readyReplicas"`
+	CurrentReplicas int32  `json:"currentReplicas"`
+	UpdatedReplicas int32  `json:"updatedReplicas"`
+	// Image is the client image of the StatefulSet of the role, the version of every role can differ
+	Image string `json:"image,omitempty"`
+	// IsSyncing is true if any node of the role is still syncing
+	IsSyncing bool `json:"isSyncing,omitempty"`
+	// BestBlock is the highest best block among the nodes of the role
+	BestBlock int64 `json:"bestBlock,omitempty"`
+	// FinalizedBlock is the highest finalized block among the nodes of the role
+	FinalizedBlock int64 `json:"finalizedBlock,omitempty"`
+	// Nodes reports the sync state of every node of the role reachable through its RPC endpoint
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+	// Maintenance is the maintenance mode of the role, empty if the role is not in maintenance
+	Maintenance string `json:"maintenance,omitempty"`
+}
 
-	// Nodes are the names of the CustomResource pods... ?? to check
-	Nodes []string `json:"nodes"`
+// NodeStatus defines the state of a node, as returned by its RPC endpoint
+type NodeStatus struct {
+	Name           string `json:"name"`
+	IsSyncing      bool   `json:"isSyncing"`
+	BestBlock      int64  `json:"bestBlock"`
+	FinalizedBlock int64  `json:"finalizedBlock"`
+	Peers          int32  `json:"peers"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -69,6 +949,12 @@ type PolkadotStatus struct {
 // Polkadot is the Schema for the polkadots API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=polkadots,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.kind`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.clientVersion`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type Polkadot struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
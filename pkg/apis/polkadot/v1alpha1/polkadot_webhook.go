@@ -0,0 +1,781 @@
+package v1alpha1
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"strings"
+)
+
+// validKinds are the values accepted for the spec.kind
+var validKinds = []string{"Sentry", "Validator", "SentryAndValidator", "Bootnode", "Archive", "RpcNode", "Collator"}
+
+// the defaults filled by the mutating webhook
+const (
+	defaultClientVersion   = "latest"
+	defaultCollatorBinary  = "polkadot-collator"
+	defaultCPURequest      = "500m"
+	defaultMemoryRequest   = "1Gi"
+	customChain            = "custom"
+	defaultEraExporterPort = 9618
+)
+
+// reservedContainerNames are the names of the containers generated by the operator in the pods of the nodes
+var reservedContainerNames = []string{"polkadot", "stall-detector", "sync-readiness", "validator-leader-election", "era-exporter"}
+
+// reservedInitContainerNames are the names of the init containers generated by the operator in the pods of the nodes
+var reservedInitContainerNames = []string{"volume-mount-permissions-data", "bootstrap-data", "node-key"}
+
+// clientVersionRegexp matches the valid tags of the client image
+var clientVersionRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// ss58AddressRegexp matches the base58 encoded SS58 addresses of the accounts
+var ss58AddressRegexp = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{46,48}$`)
+
+// reservedNodeRegexp matches the multiaddresses of the reserved nodes of other clusters, with their peer ID
+var reservedNodeRegexp = regexp.MustCompile(`^/(ip4|ip6|dns|dns4|dns6)/[^/]+/tcp/[0-9]{1,5}/p2p/[1-9A-HJ-NP-Za-km-z]+$`)
+
+// externalValidatorAddrRegexp matches the multiaddress of a validator running outside of Kubernetes, without its peer ID
+var externalValidatorAddrRegexp = regexp.MustCompile(`^/(ip4|ip6|dns|dns4|dns6)/[^/]+/tcp/[0-9]{1,5}$`)
+
+// peerIDRegexp matches a base58 encoded peer ID
+var peerIDRegexp = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]+$`)
+
+// balanceRegexp matches the positive amounts in the smallest unit of the chain
+var balanceRegexp = regexp.MustCompile(`^[1-9][0-9]*$`)
+
+// SetupWebhookWithManager registers the admission webhooks of the Polkadot CustomResource. The builder is imported
+// directly, as the root controller-runtime package registers the kubeconfig flag in every binary importing the API types
+func (r *Polkadot) SetupWebhookWithManager(mgr manager.Manager) error {
+	return builder.WebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-polkadot-swisscomblockchain-com-v1alpha1-polkadot,mutating=true,failurePolicy=fail,groups=polkadot.swisscomblockchain.com,resources=polkadots,verbs=create;update,versions=v1alpha1,name=mpolkadot.swisscomblockchain.com
+
+var _ webhook.Defaulter = &Polkadot{}
+
+// Default implements webhook.Defaulter, filling the fields left empty so that the defaults are visible on the stored object
+func (r *Polkadot) Default() {
+	spec := &r.Spec
+	if spec.ClientVersion == "" {
+		spec.ClientVersion = defaultClientVersion
+	}
+	if spec.Chain == "" && (spec.ChainspecConfigMapRef != nil || spec.ChainspecBuilder.Enabled) {
+		spec.Chain = customChain
+	}
+
+	if hasRole(*spec, "sentry") {
+		r.defaultRole("sentry", &spec.Sentry.Replicas, &spec.Sentry.ClientName, &spec.Sentry.Resources)
+	}
+	if hasRole(*spec, "validator") {
+		r.defaultRole("validator", nil, &spec.Validator.ClientName, &spec.Validator.Resources)
+	}
+	if hasRole(*spec, "bootnode") {
+		r.defaultRole("bootnode", &spec.Bootnode.Replicas, &spec.Bootnode.ClientName, &spec.Bootnode.Resources)
+	}
+	if hasRole(*spec, "archive") {
+		r.defaultRole("archive", &spec.Archive.Replicas, &spec.Archive.ClientName, &spec.Archive.Resources)
+	}
+	if hasRole(*spec, "rpcnode") {
+		r.defaultRole("rpcnode", &spec.RpcNode.Replicas, &spec.RpcNode.ClientName, &spec.RpcNode.Resources)
+	}
+	if hasRole(*spec, "collator") {
+		r.defaultRole("collator", &spec.Collator.Replicas, &spec.Collator.ClientName, &spec.Collator.Resources)
+		if spec.Collator.Binary == "" {
+			spec.Collator.Binary = defaultCollatorBinary
+		}
+	}
+	if spec.Validator.EraExporter.Enabled && spec.Validator.EraExporter.Port == 0 {
+		spec.Validator.EraExporter.Port = defaultEraExporterPort
+	}
+}
+
+// defaultRole fills the replicas, the client name and the resource requests of a deployed role, the requests being the
+// ones of the preset of the chain if any
+func (r *Polkadot) defaultRole(role string, replicas *int32, clientName *string, resources *corev1.ResourceRequirements) {
+	if replicas != nil && *replicas == 0 {
+		*replicas = 1
+	}
+	if *clientName == "" {
+		*clientName = r.Name + "-" + role
+	}
+	if len(resources.Requests) == 0 {
+		cpuRequest, memoryRequest := defaultCPURequest, defaultMemoryRequest
+		if preset, isFound := config.ChainPresets[r.Spec.Chain]; isFound {
+			cpuRequest, memoryRequest = preset.CPURequest, preset.MemoryRequest
+		}
+		resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+			corev1.ResourceMemory: resource.MustParse(memoryRequest),
+		}
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-polkadot-swisscomblockchain-com-v1alpha1-polkadot,mutating=false,failurePolicy=fail,groups=polkadot.swisscomblockchain.com,resources=polkadots,verbs=create;update,versions=v1alpha1,name=vpolkadot.swisscomblockchain.com
+
+var _ webhook.Validator = &Polkadot{}
+
+// ValidateCreate implements webhook.Validator
+func (r *Polkadot) ValidateCreate() error {
+	return r.validatePolkadot()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (r *Polkadot) ValidateUpdate(old runtime.Object) error {
+	return r.validatePolkadot()
+}
+
+// ValidateDelete implements webhook.Validator, the deletions are always allowed
+func (r *Polkadot) ValidateDelete() error {
+	return nil
+}
+
+// validatePolkadot rejects the specs the operator can not reconcile, instead of failing at reconcile time
+func (r *Polkadot) validatePolkadot() error {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	spec := r.Spec
+
+	if len(spec.NodePools) > 0 {
+		if spec.Kind != "" {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("kind"), "the nodePools are deployed in place of the roles of the kind"))
+		}
+		allErrs = append(allErrs, validateNodePools(spec, specPath.Child("nodePools"))...)
+	} else if !isValidKind(spec.Kind) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("kind"), spec.Kind, validKinds))
+	}
+	if !clientVersionRegexp.MatchString(spec.ClientVersion) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("clientVersion"), spec.ClientVersion, "must be a valid image tag"))
+	}
+	allErrs = append(allErrs, validateReplicas(spec, specPath)...)
+	allErrs = append(allErrs, validatePorts(spec, specPath.Child("ports"))...)
+
+	allErrs = append(allErrs, validateSecretKeySelector(spec.Validator.NodeKeySecretRef, specPath.Child("validator", "nodeKeySecretRef"))...)
+	allErrs = append(allErrs, validateSecretKeySelector(spec.Sentry.NodeKeySecretRef, specPath.Child("sentry", "nodeKeySecretRef"))...)
+	allErrs = append(allErrs, validateSecretKeySelector(spec.Archive.NodeKeySecretRef, specPath.Child("archive", "nodeKeySecretRef"))...)
+	allErrs = append(allErrs, validateSecretKeySelector(spec.RpcNode.NodeKeySecretRef, specPath.Child("rpcNode", "nodeKeySecretRef"))...)
+	allErrs = append(allErrs, validateSecretKeySelector(spec.Collator.NodeKeySecretRef, specPath.Child("collator", "nodeKeySecretRef"))...)
+	allErrs = append(allErrs, validateConfigMapKeySelector(spec.ChainspecConfigMapRef, specPath.Child("chainspecConfigMapRef"))...)
+	if spec.Chain == customChain && spec.ChainspecConfigMapRef == nil && !spec.ChainspecBuilder.Enabled {
+		allErrs = append(allErrs, field.Required(specPath.Child("chainspecConfigMapRef"), "the chainspec of the custom chain is required"))
+	}
+	if spec.Chain != "" && spec.Chain != customChain && spec.ChainspecConfigMapRef != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("chainspecConfigMapRef"), "a chainspec is only used by the custom chain"))
+	}
+	allErrs = append(allErrs, validateChainspecBuilder(spec, specPath.Child("chainspecBuilder"))...)
+	allErrs = append(allErrs, validatePayouts(spec.Payouts, specPath.Child("payouts"))...)
+	allErrs = append(allErrs, validateStaking(spec.Validator, specPath.Child("validator"))...)
+	allErrs = append(allErrs, validateSlashDetection(spec.Validator, specPath.Child("validator"))...)
+	allErrs = append(allErrs, validateReservedNodes(spec.Validator.ExternalSentries, specPath.Child("validator", "externalSentries"))...)
+	allErrs = append(allErrs, validateReservedNodes(spec.Sentry.ExternalValidators, specPath.Child("sentry", "externalValidators"))...)
+	allErrs = append(allErrs, validateExternalValidatorAddr(spec, specPath.Child("sentry"))...)
+	allErrs = append(allErrs, validateEraExporter(spec, specPath.Child("validator", "eraExporter"))...)
+
+	if spec.Validator.Autoscaling.Enabled {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "autoscaling"), "a single validator is deployed, it can not be autoscaled"))
+	}
+	if spec.Bootnode.Autoscaling.Enabled {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("bootnode", "autoscaling"), "the bootnodes need a node key for each replica, they can not be autoscaled"))
+	}
+	allErrs = append(allErrs, validateAutoscaling(spec.Sentry.Autoscaling, spec.Sentry.VerticalAutoscaling, specPath.Child("sentry", "autoscaling"))...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Archive.Autoscaling, spec.Archive.VerticalAutoscaling, specPath.Child("archive", "autoscaling"))...)
+	allErrs = append(allErrs, validateAutoscaling(spec.RpcNode.Autoscaling, spec.RpcNode.VerticalAutoscaling, specPath.Child("rpcNode", "autoscaling"))...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Collator.Autoscaling, spec.Collator.VerticalAutoscaling, specPath.Child("collator", "autoscaling"))...)
+
+	if spec.Validator.Ingress.Enabled {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "ingress"), "the RPC of the validator must not be exposed"))
+	}
+	allErrs = append(allErrs, validateIngress(spec.Sentry.Ingress, specPath.Child("sentry", "ingress"))...)
+	allErrs = append(allErrs, validateIngress(spec.Bootnode.Ingress, specPath.Child("bootnode", "ingress"))...)
+	allErrs = append(allErrs, validateIngress(spec.Archive.Ingress, specPath.Child("archive", "ingress"))...)
+	allErrs = append(allErrs, validateIngress(spec.RpcNode.Ingress, specPath.Child("rpcNode", "ingress"))...)
+	allErrs = append(allErrs, validateIngress(spec.Collator.Ingress, specPath.Child("collator", "ingress"))...)
+
+	if isSentryAndValidator(spec) && spec.Validator.HostNetwork {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "hostNetwork"), "the validator is only reachable through its sentries, the NetworkPolicies do not apply to the host network"))
+	}
+	if spec.Validator.HostNetwork && spec.Validator.RemoteSigner != nil && spec.Validator.RemoteSigner.Sidecar != nil {
+		allErrs = append(allErrs, validateHostPorts(spec.Validator.RemoteSigner.Sidecar.Ports, spec, specPath.Child("validator", "remoteSigner", "sidecar", "ports"))...)
+	}
+
+	if isSentryAndValidator(spec) && spec.Validator.AutoPublicAddr {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "autoPublicAddr"), "the validator is only reachable through its sentries"))
+	}
+	if spec.Bootnode.AutoPublicAddr {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("bootnode", "autoPublicAddr"), "each bootnode has its own Service, the public address can not be shared by the pods"))
+	}
+
+	if spec.Validator.GenerateNodeKeys {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "generateNodeKeys"), "the identity of the validator is set by its nodeKey or nodeKeySecretRef, shared by its failover"))
+	}
+	if spec.Bootnode.GenerateNodeKeys {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("bootnode", "generateNodeKeys"), "the bootnodes take their identities from nodeKeys, their addresses are derived from them"))
+	}
+	if hasRole(spec, "bootnode") {
+		allErrs = append(allErrs, validateBootnodeNodeKeys(spec, specPath.Child("bootnode", "nodeKeys"))...)
+	}
+	allErrs = append(allErrs, validateGenerateNodeKeys(spec.Sentry.NodeOptions, spec.Sentry.NodeKey, spec.Sentry.NodeKeySecretRef, specPath.Child("sentry"))...)
+	allErrs = append(allErrs, validateGenerateNodeKeys(spec.Archive.NodeOptions, spec.Archive.NodeKey, spec.Archive.NodeKeySecretRef, specPath.Child("archive"))...)
+	allErrs = append(allErrs, validateGenerateNodeKeys(spec.RpcNode.NodeOptions, spec.RpcNode.NodeKey, spec.RpcNode.NodeKeySecretRef, specPath.Child("rpcNode"))...)
+	allErrs = append(allErrs, validateGenerateNodeKeys(spec.Collator.NodeOptions, spec.Collator.NodeKey, spec.Collator.NodeKeySecretRef, specPath.Child("collator"))...)
+
+	if isSentryAndValidator(spec) && spec.Validator.ExternalDNS.Hostname != "" {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "externalDNS", "hostname"), "the validator is only reachable through its sentries"))
+	}
+	allErrs = append(allErrs, validateExternalDNS(spec.Validator.ExternalDNS, specPath.Child("validator", "externalDNS"))...)
+	allErrs = append(allErrs, validateExternalDNS(spec.Sentry.ExternalDNS, specPath.Child("sentry", "externalDNS"))...)
+	allErrs = append(allErrs, validateExternalDNS(spec.Bootnode.ExternalDNS, specPath.Child("bootnode", "externalDNS"))...)
+	allErrs = append(allErrs, validateExternalDNS(spec.Archive.ExternalDNS, specPath.Child("archive", "externalDNS"))...)
+	allErrs = append(allErrs, validateExternalDNS(spec.RpcNode.ExternalDNS, specPath.Child("rpcNode", "externalDNS"))...)
+	allErrs = append(allErrs, validateExternalDNS(spec.Collator.ExternalDNS, specPath.Child("collator", "externalDNS"))...)
+
+	if spec.Collator.Image != "" && (spec.Collator.ClientVersion != "" || spec.Collator.ClientImage != "") {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("collator", "clientVersion"), "the image of the collator, tag included, is set by its image"))
+	}
+	for _, role := range getRoleOptions(spec) {
+		if role.options.ClientVersion != "" && !clientVersionRegexp.MatchString(role.options.ClientVersion) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child(role.name, "clientVersion"), role.options.ClientVersion, "must be a valid image tag"))
+		}
+		allErrs = append(allErrs, validateContainers(role.options.Sidecars, reservedContainerNames, specPath.Child(role.name, "sidecars"))...)
+		allErrs = append(allErrs, validateContainers(role.options.InitContainers, reservedInitContainerNames, specPath.Child(role.name, "initContainers"))...)
+		allErrs = append(allErrs, validateUpdateStrategy(role.options.UpdateStrategy, spec.Upgrade, specPath.Child(role.name, "updateStrategy"))...)
+		if spec.Hardened && role.options.HostNetwork {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child(role.name, "hostNetwork"), "the hardened pods satisfy the restricted Pod Security Standard, which forbids the host network"))
+		}
+		if role.options.HostNetwork {
+			for i, sidecar := range role.options.Sidecars {
+				allErrs = append(allErrs, validateHostPorts(sidecar.Ports, spec, specPath.Child(role.name, "sidecars").Index(i).Child("ports"))...)
+			}
+		}
+	}
+
+	keystorePath := specPath.Child("validator", "keystoreSecretRef")
+	if spec.Validator.KeystoreSecretRef != nil {
+		if spec.Validator.KeystoreSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(keystorePath.Child("name"), "the name of the keystore Secret is required"))
+		}
+		keyRotation := spec.Validator.KeyRotation
+		if keyRotation.OnFirstStart || keyRotation.Trigger != "" {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "keyRotation"), "the session keys can not be rotated into the keystore Secret"))
+		}
+	}
+	if spec.Validator.HighAvailability.Enabled && spec.Validator.KeystoreSecretRef == nil && spec.Validator.RemoteSigner == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("validator", "highAvailability"), "the session keys must be shared by the validators, in a keystoreSecretRef or a remoteSigner"))
+	}
+	// the egress of the validator behind its sentries is restricted by the secure communication, the elector sidecar could
+	// not reach the API server to acquire the Lease
+	if isSentryAndValidator(spec) && spec.SecureCommunicationSupport.Enabled && spec.Validator.HighAvailability.Enabled && spec.Validator.HighAvailability.LeaderElection.Enabled {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("validator", "highAvailability", "leaderElection"), "the leader election needs the Kubernetes API, which the validator can not reach with the secureCommunicationSupport"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: SchemeGroupVersion.Group, Kind: "Polkadot"}, r.Name, allErrs)
+}
+
+type roleOptions struct {
+	name    string
+	options NodeOptions
+}
+
+// getRoleOptions returns the options of each role section, named after its field
+func getRoleOptions(spec PolkadotSpec) []roleOptions {
+	return []roleOptions{
+		{"validator", spec.Validator.NodeOptions},
+		{"sentry", spec.Sentry.NodeOptions},
+		{"bootnode", spec.Bootnode.NodeOptions},
+		{"archive", spec.Archive.NodeOptions},
+		{"rpcNode", spec.RpcNode.NodeOptions},
+		{"collator", spec.Collator.NodeOptions},
+	}
+}
+
+// hasRole tells if the spec deploys nodes of the role, through its node pools or the roles of its Kind
+func hasRole(spec PolkadotSpec, role string) bool {
+	if len(spec.NodePools) > 0 {
+		for _, pool := range spec.NodePools {
+			if pool.Role == role {
+				return true
+			}
+		}
+		return false
+	}
+	kindRoles := map[string][]string{
+		"Sentry":             {"sentry"},
+		"Validator":          {"validator"},
+		"SentryAndValidator": {"sentry", "validator"},
+		"Bootnode":           {"bootnode"},
+		"Archive":            {"archive"},
+		"RpcNode":            {"rpcnode"},
+		"Collator":           {"collator"},
+	}
+	if role == "rpcnode" && spec.RpcNode.Replicas > 0 {
+		return true
+	}
+	for _, kindRole := range kindRoles[spec.Kind] {
+		if kindRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isSentryAndValidator tells if the validator is deployed behind the sentries of the spec
+func isSentryAndValidator(spec PolkadotSpec) bool {
+	return hasRole(spec, "sentry") && hasRole(spec, "validator")
+}
+
+func isValidKind(kind string) bool {
+	for _, validKind := range validKinds {
+		if kind == validKind {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNodePools rejects the roles deployed by several pools, and the pools without replicas
+func validateNodePools(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sectionReplicas := map[string]int32{
+		"sentry":   spec.Sentry.Replicas,
+		"bootnode": spec.Bootnode.Replicas,
+		"archive":  spec.Archive.Replicas,
+		"rpcnode":  spec.RpcNode.Replicas,
+		"collator": spec.Collator.Replicas,
+	}
+	isUsed := map[string]bool{}
+	for i, pool := range spec.NodePools {
+		poolPath := path.Index(i)
+		if isUsed[pool.Role] {
+			allErrs = append(allErrs, field.Duplicate(poolPath.Child("role"), pool.Role))
+		}
+		isUsed[pool.Role] = true
+		if pool.Role == "validator" {
+			if pool.Replicas > 1 {
+				allErrs = append(allErrs, field.Invalid(poolPath.Child("replicas"), pool.Replicas, "a single validator is deployed, see the highAvailability of the validator"))
+			}
+			continue
+		}
+		if pool.Replicas == 0 && sectionReplicas[pool.Role] == 0 {
+			allErrs = append(allErrs, field.Invalid(poolPath.Child("replicas"), pool.Replicas, "must be greater than 0, in the pool or in its role section"))
+		}
+	}
+	return allErrs
+}
+
+// validateReplicas rejects the Kinds deploying no node at all
+func validateReplicas(spec PolkadotSpec, specPath *field.Path) field.ErrorList {
+	type roleReplicas struct {
+		role     string
+		replicas int32
+	}
+	kindReplicas := map[string]roleReplicas{
+		"Sentry":             {"sentry", spec.Sentry.Replicas},
+		"SentryAndValidator": {"sentry", spec.Sentry.Replicas},
+		"Bootnode":           {"bootnode", spec.Bootnode.Replicas},
+		"Archive":            {"archive", spec.Archive.Replicas},
+		"RpcNode":            {"rpcNode", spec.RpcNode.Replicas},
+		"Collator":           {"collator", spec.Collator.Replicas},
+	}
+
+	rr, isFound := kindReplicas[spec.Kind]
+	if !isFound || rr.replicas > 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(specPath.Child(rr.role, "replicas"), rr.replicas, "must be greater than 0 for the Kind "+spec.Kind)}
+}
+
+func isVerticalAutoscalingUpdating(verticalAutoscaling VerticalAutoscaling) bool {
+	return verticalAutoscaling.Enabled && verticalAutoscaling.UpdateMode != "" && verticalAutoscaling.UpdateMode != "Off"
+}
+
+func validateAutoscaling(autoscaling Autoscaling, verticalAutoscaling VerticalAutoscaling, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !autoscaling.Enabled {
+		return allErrs
+	}
+	if autoscaling.MaxReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxReplicas"), autoscaling.MaxReplicas, "must be greater than 0"))
+	}
+	if autoscaling.MinReplicas != nil && *autoscaling.MinReplicas > autoscaling.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(path.Child("minReplicas"), *autoscaling.MinReplicas, "must not be greater than maxReplicas"))
+	}
+	if autoscaling.Keda == nil && len(autoscaling.Metrics) == 0 && isVerticalAutoscalingUpdating(verticalAutoscaling) {
+		allErrs = append(allErrs, field.Forbidden(path, "the CPU utilization target conflicts with the updates of the verticalAutoscaling, set its updateMode to Off or scale on custom metrics"))
+	}
+	if autoscaling.Keda != nil && autoscaling.Keda.ServerAddress == "" {
+		allErrs = append(allErrs, field.Required(path.Child("keda", "serverAddress"), "the address of the Prometheus server is required"))
+	}
+	return allErrs
+}
+
+func validateIngress(ingress Ingress, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if ingress.Enabled && ingress.Host == "" {
+		allErrs = append(allErrs, field.Required(path.Child("host"), "the host of the RPC endpoint is required"))
+	}
+	if ingress.IssuerRef != nil && ingress.IssuerRef.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("issuerRef", "name"), "the name of the Issuer is required"))
+	}
+	return allErrs
+}
+
+// validatePorts rejects the listen ports of the nodes clashing with each other, the ones not set being the ports of the
+// preset of the chain or the ones configured in the operator
+func validatePorts(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	isUsed := map[int32]bool{}
+	for i, port := range getClientPorts(spec) {
+		if port <= 0 {
+			continue
+		}
+		if isUsed[port] {
+			allErrs = append(allErrs, field.Duplicate(path.Child([]string{"p2p", "rpc", "ws", "prometheus"}[i]), port))
+		}
+		isUsed[port] = true
+	}
+	return allErrs
+}
+
+// getClientPorts returns the P2P, RPC, WebSocket and Prometheus ports of the nodes, the ones of the preset of the chain or
+// the ones configured in the operator if not set
+func getClientPorts(spec PolkadotSpec) []int32 {
+	ports := spec.Ports
+	defaultPorts := []int32{int32(config.P2PPortEnvVar.Value), int32(config.RPCPortEnvVar.Value), int32(config.WSPortEnvVar.Value), int32(config.MetricsPortEnvVar.Value)}
+	if preset, isFound := config.ChainPresets[spec.Chain]; isFound {
+		defaultPorts = []int32{preset.P2PPort, preset.RPCPort, preset.WSPort, preset.MetricsPort}
+	}
+	clientPorts := []int32{}
+	for i, port := range []int32{ports.P2P, ports.RPC, ports.WS, ports.Prometheus} {
+		if port == 0 {
+			port = defaultPorts[i]
+		}
+		clientPorts = append(clientPorts, port)
+	}
+	return clientPorts
+}
+
+// validateHostPorts rejects the ports of a container sharing the host network with the client, which clash with the
+// ports of the client or with each other
+func validateHostPorts(ports []corev1.ContainerPort, spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	isUsed := map[int32]bool{}
+	for _, port := range getClientPorts(spec) {
+		isUsed[port] = true
+	}
+	for i, port := range ports {
+		if isUsed[port.ContainerPort] {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("containerPort"), port.ContainerPort))
+		}
+		isUsed[port.ContainerPort] = true
+	}
+	return allErrs
+}
+
+// validateContainers rejects the containers of a role without a name or an image, and the ones named after another
+// container of the pods
+// validateContainers validates the containers added to the pods of a role, whose schema is left out of the CRD: their
+// names, not used by the operator, their image, their ports and the names of their environment variables
+func validateContainers(containers []corev1.Container, reservedNames []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	isUsed := map[string]bool{}
+	for _, name := range reservedNames {
+		isUsed[name] = true
+	}
+	for i, container := range containers {
+		containerPath := path.Index(i)
+		if container.Name == "" {
+			allErrs = append(allErrs, field.Required(containerPath.Child("name"), "the name of the container is required"))
+		}
+		for _, msg := range validation.IsDNS1123Label(container.Name) {
+			allErrs = append(allErrs, field.Invalid(containerPath.Child("name"), container.Name, msg))
+		}
+		if isUsed[container.Name] {
+			allErrs = append(allErrs, field.Duplicate(containerPath.Child("name"), container.Name))
+		}
+		isUsed[container.Name] = true
+		if container.Image == "" {
+			allErrs = append(allErrs, field.Required(containerPath.Child("image"), "the image of the container is required"))
+		}
+		for j, port := range container.Ports {
+			portPath := containerPath.Child("ports").Index(j)
+			for _, msg := range validation.IsValidPortNum(int(port.ContainerPort)) {
+				allErrs = append(allErrs, field.Invalid(portPath.Child("containerPort"), port.ContainerPort, msg))
+			}
+			switch port.Protocol {
+			case "", corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+			default:
+				allErrs = append(allErrs, field.NotSupported(portPath.Child("protocol"), port.Protocol, []string{string(corev1.ProtocolTCP), string(corev1.ProtocolUDP), string(corev1.ProtocolSCTP)}))
+			}
+		}
+		for j, env := range container.Env {
+			for _, msg := range validation.IsEnvVarName(env.Name) {
+				allErrs = append(allErrs, field.Invalid(containerPath.Child("env").Index(j).Child("name"), env.Name, msg))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateExternalDNS(externalDNS ExternalDNS, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if externalDNS.Hostname == "" {
+		return allErrs
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(externalDNS.Hostname) {
+		allErrs = append(allErrs, field.Invalid(path.Child("hostname"), externalDNS.Hostname, msg))
+	}
+	return allErrs
+}
+
+// validateChainspecBuilder rejects a chainspec builder together with another chain, and the invalid addresses of its genesis
+func validateChainspecBuilder(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	builder := spec.ChainspecBuilder
+	if !builder.Enabled {
+		return allErrs
+	}
+	if spec.ChainspecConfigMapRef != nil {
+		allErrs = append(allErrs, field.Forbidden(path, "the chainspec is either built or given in the chainspecConfigMapRef"))
+	}
+	if spec.Chain != "" && spec.Chain != customChain {
+		allErrs = append(allErrs, field.Forbidden(path, "the built chainspec is only used by the custom chain"))
+	}
+	for i, authority := range builder.Authorities {
+		allErrs = append(allErrs, validateSS58Address(authority.Aura, path.Child("authorities").Index(i).Child("aura"))...)
+		allErrs = append(allErrs, validateSS58Address(authority.Grandpa, path.Child("authorities").Index(i).Child("grandpa"))...)
+	}
+	for i, balance := range builder.Balances {
+		allErrs = append(allErrs, validateSS58Address(balance.Address, path.Child("balances").Index(i).Child("address"))...)
+	}
+	if builder.SudoKey != "" {
+		allErrs = append(allErrs, validateSS58Address(builder.SudoKey, path.Child("sudoKey"))...)
+	}
+	return allErrs
+}
+
+func validateSS58Address(address string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !ss58AddressRegexp.MatchString(address) {
+		allErrs = append(allErrs, field.Invalid(path, address, "must be a SS58 address"))
+	}
+	return allErrs
+}
+
+func validateConfigMapKeySelector(selector *corev1.ConfigMapKeySelector, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if selector == nil {
+		return allErrs
+	}
+	if selector.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "the name of the ConfigMap is required"))
+	}
+	if selector.Key == "" {
+		allErrs = append(allErrs, field.Required(path.Child("key"), "the key of the ConfigMap is required"))
+	}
+	return allErrs
+}
+
+// validatePayouts requires the stashes paid out and the account submitting the payouts
+func validatePayouts(payouts Payouts, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !payouts.Enabled {
+		return allErrs
+	}
+	if len(payouts.Stashes) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("stashes"), "the stashes to pay out are required"))
+	}
+	for i, stash := range payouts.Stashes {
+		allErrs = append(allErrs, validateSS58Address(stash, path.Child("stashes").Index(i))...)
+	}
+	if payouts.AccountSecretRef == nil {
+		allErrs = append(allErrs, field.Required(path.Child("accountSecretRef"), "the account submitting the payouts is required"))
+	}
+	return append(allErrs, validateSecretKeySelector(payouts.AccountSecretRef, path.Child("accountSecretRef"))...)
+}
+
+// validateStaking requires the stash, its seed and the bond of the staking bootstrap, and the rotation of the session keys
+// it registers
+func validateStaking(validator Validator, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	staking := validator.Staking
+	stakingPath := path.Child("staking")
+	if !staking.Enabled {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateSS58Address(staking.Stash, stakingPath.Child("stash"))...)
+	if staking.Controller != "" {
+		allErrs = append(allErrs, validateSS58Address(staking.Controller, stakingPath.Child("controller"))...)
+	}
+	if staking.StashSecretRef == nil {
+		allErrs = append(allErrs, field.Required(stakingPath.Child("stashSecretRef"), "the seed of the stash is required"))
+	}
+	allErrs = append(allErrs, validateSecretKeySelector(staking.StashSecretRef, stakingPath.Child("stashSecretRef"))...)
+	allErrs = append(allErrs, validateSecretKeySelector(staking.ControllerSecretRef, stakingPath.Child("controllerSecretRef"))...)
+	if !balanceRegexp.MatchString(staking.Bond) {
+		allErrs = append(allErrs, field.Invalid(stakingPath.Child("bond"), staking.Bond, "must be an amount in the smallest unit of the chain"))
+	}
+	if !validator.KeyRotation.OnFirstStart && validator.KeyRotation.Trigger == "" {
+		allErrs = append(allErrs, field.Required(path.Child("keyRotation"), "the session keys registered by the staking bootstrap are rotated by the keyRotation"))
+	}
+	return allErrs
+}
+
+func validateSlashDetection(validator Validator, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	slashDetection := validator.SlashDetection
+	if !slashDetection.Enabled {
+		return allErrs
+	}
+	if slashDetection.Stash == "" && validator.Staking.Stash == "" {
+		allErrs = append(allErrs, field.Required(path.Child("slashDetection", "stash"), "the stash is required when the staking does not define it"))
+	} else if slashDetection.Stash != "" {
+		allErrs = append(allErrs, validateSS58Address(slashDetection.Stash, path.Child("slashDetection", "stash"))...)
+	}
+	return allErrs
+}
+
+// validateEraExporter rejects an exporter without stash, or listening on a port of the client it shares the pod network with
+func validateEraExporter(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	eraExporter := spec.Validator.EraExporter
+	if !eraExporter.Enabled {
+		return allErrs
+	}
+	if eraExporter.Stash == "" && spec.Validator.Staking.Stash == "" {
+		allErrs = append(allErrs, field.Required(path.Child("stash"), "the stash is required when the staking does not define it"))
+	} else if eraExporter.Stash != "" {
+		allErrs = append(allErrs, validateSS58Address(eraExporter.Stash, path.Child("stash"))...)
+	}
+	exporterPort := eraExporter.Port
+	if exporterPort == 0 {
+		exporterPort = defaultEraExporterPort
+	}
+	for _, port := range getClientPorts(spec) {
+		if exporterPort == port {
+			allErrs = append(allErrs, field.Duplicate(path.Child("port"), port))
+		}
+	}
+	return allErrs
+}
+
+func validateReservedNodes(addresses []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, address := range addresses {
+		if !reservedNodeRegexp.MatchString(address) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), address, "must be a multiaddress with a TCP port and a peer ID (e.g. /dns4/sentry.example.com/tcp/30333/p2p/<peer ID>)"))
+		}
+	}
+	return allErrs
+}
+
+func validateExternalValidatorAddr(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	address := spec.Sentry.ExternalValidatorAddr
+	if address == "" {
+		return allErrs
+	}
+	if !externalValidatorAddrRegexp.MatchString(address) {
+		allErrs = append(allErrs, field.Invalid(path.Child("externalValidatorAddr"), address, "must be a multiaddress with a TCP port, without peer ID (e.g. /ip4/198.51.100.7/tcp/30333)"))
+	}
+	if hasRole(spec, "validator") {
+		allErrs = append(allErrs, field.Forbidden(path.Child("externalValidatorAddr"), "the sentries only front an external validator when the spec deploys no validator"))
+	}
+	if spec.Sentry.ReservedValidatorID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("reservedValidatorID"), "the peer ID of the external validator is required"))
+	} else if !peerIDRegexp.MatchString(spec.Sentry.ReservedValidatorID) {
+		allErrs = append(allErrs, field.Invalid(path.Child("reservedValidatorID"), spec.Sentry.ReservedValidatorID, "must be a base58 encoded peer ID"))
+	}
+	return allErrs
+}
+
+// validateUpdateStrategy checks the type of the update strategy of a role, and that its partition is not driven by the upgrades
+func validateUpdateStrategy(updateStrategy *appsv1.StatefulSetUpdateStrategy, upgrade Upgrade, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if updateStrategy == nil {
+		return allErrs
+	}
+	switch updateStrategy.Type {
+	case "", appsv1.RollingUpdateStatefulSetStrategyType:
+	case appsv1.OnDeleteStatefulSetStrategyType:
+		if updateStrategy.RollingUpdate != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("rollingUpdate"), "only allowed with the RollingUpdate type"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("type"), updateStrategy.Type, []string{string(appsv1.RollingUpdateStatefulSetStrategyType), string(appsv1.OnDeleteStatefulSetStrategyType)}))
+	}
+	if rollingUpdate := updateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		if *rollingUpdate.Partition < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("rollingUpdate", "partition"), *rollingUpdate.Partition, "must not be negative"))
+		}
+		if upgrade.SyncGated || upgrade.Canary.Enabled {
+			allErrs = append(allErrs, field.Forbidden(path.Child("rollingUpdate", "partition"), "the partition of the sync gated and canary upgrades is set by the operator"))
+		}
+	}
+	return allErrs
+}
+
+// validateBootnodeNodeKeys checks that every replica of the bootnodes, of their pool if any, has a node key, and that the
+// node keys are hex encoded ed25519 secret keys of 32 bytes
+func validateBootnodeNodeKeys(spec PolkadotSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	replicas := spec.Bootnode.Replicas
+	for _, pool := range spec.NodePools {
+		if pool.Role == "bootnode" && pool.Replicas > 0 {
+			replicas = pool.Replicas
+		}
+	}
+	if int32(len(spec.Bootnode.NodeKeys)) < replicas {
+		allErrs = append(allErrs, field.Invalid(path, len(spec.Bootnode.NodeKeys), fmt.Sprintf("a node key is required for each of the %d replicas of the bootnodes", replicas)))
+	}
+	for i, nodeKey := range spec.Bootnode.NodeKeys {
+		if !isNodeKeyValid(nodeKey) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), "<redacted>", "must be a hex encoded key of 32 bytes"))
+		}
+	}
+	return allErrs
+}
+
+// isNodeKeyValid tells if a node key is a hex encoded key of 32 bytes, as expected by the --node-key argument of the client
+func isNodeKeyValid(nodeKey string) bool {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(nodeKey, "0x"))
+	return err == nil && len(decoded) == 32
+}
+
+// validateGenerateNodeKeys checks that the generated node keys of a role are not set together with its own node key
+func validateGenerateNodeKeys(options NodeOptions, nodeKey string, nodeKeySecretRef *corev1.SecretKeySelector, path *field.Path) field.ErrorList {
+	if options.GenerateNodeKeys && (nodeKey != "" || nodeKeySecretRef != nil) {
+		return field.ErrorList{field.Forbidden(path.Child("generateNodeKeys"), "the node keys are either generated or set by nodeKey or nodeKeySecretRef")}
+	}
+	return nil
+}
+
+func validateSecretKeySelector(selector *corev1.SecretKeySelector, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if selector == nil {
+		return allErrs
+	}
+	if selector.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "the name of the Secret is required"))
+	}
+	if selector.Key == "" {
+		allErrs = append(allErrs, field.Required(path.Child("key"), "the key of the Secret is required"))
+	}
+	return allErrs
+}
@@ -0,0 +1,642 @@
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"strings"
+	"testing"
+)
+
+func TestValidatePolkadot(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		mutate  func(polkadot *Polkadot)
+		isValid bool
+	}{
+		{
+			name:    "Valid SentryAndValidator",
+			mutate:  func(polkadot *Polkadot) {},
+			isValid: true,
+		},
+		{
+			name:    "Unknown Kind",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Kind = "Relayer" },
+			isValid: false,
+		},
+		{
+			name:    "Invalid client version",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.ClientVersion = "v0.8.2 latest" },
+			isValid: false,
+		},
+		{
+			name:    "No sentry replicas",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Sentry.Replicas = 0 },
+			isValid: false,
+		},
+		{
+			name: "Node key Secret without key",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.NodeKeySecretRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "node-key"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Keystore Secret with key rotation",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+				polkadot.Spec.Validator.KeyRotation.OnFirstStart = true
+			},
+			isValid: false,
+		},
+		{
+			name: "High availability with keystore Secret",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+				polkadot.Spec.Validator.HighAvailability.Enabled = true
+			},
+			isValid: true,
+		},
+		{
+			name: "Leader election with secure communication",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+				polkadot.Spec.Validator.HighAvailability.Enabled = true
+				polkadot.Spec.Validator.HighAvailability.LeaderElection.Enabled = true
+				polkadot.Spec.SecureCommunicationSupport.Enabled = true
+			},
+			isValid: false,
+		},
+		{
+			name: "Leader election without secure communication",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+				polkadot.Spec.Validator.HighAvailability.Enabled = true
+				polkadot.Spec.Validator.HighAvailability.LeaderElection.Enabled = true
+				polkadot.Spec.SecureCommunicationSupport.Enabled = false
+			},
+			isValid: true,
+		},
+		{
+			name:    "High availability without shared keys",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Validator.HighAvailability.Enabled = true },
+			isValid: false,
+		},
+		{
+			name: "Autoscaled sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Autoscaling = Autoscaling{Enabled: true, MaxReplicas: 5}
+			},
+			isValid: true,
+		},
+		{
+			name:    "Autoscaled sentries without maxReplicas",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Sentry.Autoscaling = Autoscaling{Enabled: true} },
+			isValid: false,
+		},
+		{
+			name: "KEDA without Prometheus server",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Autoscaling = Autoscaling{Enabled: true, MaxReplicas: 5, Keda: &Keda{}}
+			},
+			isValid: false,
+		},
+		{
+			name: "CPU autoscaling with vertical autoscaling",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Autoscaling = Autoscaling{Enabled: true, MaxReplicas: 5}
+				polkadot.Spec.Sentry.VerticalAutoscaling = VerticalAutoscaling{Enabled: true, UpdateMode: "Auto"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Autoscaled validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.Autoscaling = Autoscaling{Enabled: true, MaxReplicas: 2}
+			},
+			isValid: false,
+		},
+		{
+			name: "Ingress of the RPC nodes",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.RpcNode.Ingress = Ingress{Enabled: true, Host: "rpc.example.com", TLSSecretName: "rpc-tls"}
+			},
+			isValid: true,
+		},
+		{
+			name:    "Ingress without host",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.RpcNode.Ingress = Ingress{Enabled: true} },
+			isValid: false,
+		},
+		{
+			name: "Ingress with a cert-manager Issuer",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.RpcNode.Ingress = Ingress{Enabled: true, Host: "rpc.example.com", IssuerRef: &IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"}}
+			},
+			isValid: true,
+		},
+		{
+			name: "Ingress with an unnamed Issuer",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.RpcNode.Ingress = Ingress{Enabled: true, Host: "rpc.example.com", IssuerRef: &IssuerReference{}}
+			},
+			isValid: false,
+		},
+		{
+			name:    "External DNS hostname of the sentries",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Sentry.ExternalDNS = ExternalDNS{Hostname: "sentry.example.com"} },
+			isValid: true,
+		},
+		{
+			name:    "Invalid external DNS hostname",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Sentry.ExternalDNS = ExternalDNS{Hostname: "Sentry_example"} },
+			isValid: false,
+		},
+		{
+			name: "External DNS hostname of the validator behind the sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "SentryAndValidator"
+				polkadot.Spec.Validator.ExternalDNS = ExternalDNS{Hostname: "validator.example.com"}
+			},
+			isValid: false,
+		},
+		{
+			name:    "Custom ports",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Ports = Ports{P2P: 30334, RPC: 9934, WS: 9945, Prometheus: 9616} },
+			isValid: true,
+		},
+		{
+			name:    "Clashing ports",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Ports = Ports{P2P: 30334, RPC: 9934, WS: 9934, Prometheus: 9616} },
+			isValid: false,
+		},
+		{
+			name: "Host network of the validator behind the sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "SentryAndValidator"
+				polkadot.Spec.Validator.HostNetwork = true
+			},
+			isValid: false,
+		},
+		{
+			name: "Remote signer clashing with the client on the host network",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Validator"
+				polkadot.Spec.Validator.HostNetwork = true
+				polkadot.Spec.Validator.RemoteSigner = &RemoteSigner{
+					Endpoint: "http://localhost:8000",
+					Sidecar:  &corev1.Container{Name: "signer", Ports: []corev1.ContainerPort{{ContainerPort: 8000}, {ContainerPort: 8000}}},
+				}
+			},
+			isValid: false,
+		},
+		{
+			name:    "Public address of the sentries",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Sentry.AutoPublicAddr = true },
+			isValid: true,
+		},
+		{
+			name:    "Public address of the bootnodes",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Bootnode.AutoPublicAddr = true },
+			isValid: false,
+		},
+		{
+			name: "Node key of each bootnode",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Bootnode"
+				polkadot.Spec.Bootnode.Replicas = 2
+				polkadot.Spec.Bootnode.NodeKeys = []string{strings.Repeat("01", 32), "0x" + strings.Repeat("02", 32)}
+			},
+			isValid: true,
+		},
+		{
+			name: "Bootnodes without a node key for each replica",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Bootnode"
+				polkadot.Spec.Bootnode.Replicas = 2
+				polkadot.Spec.Bootnode.NodeKeys = []string{strings.Repeat("01", 32)}
+			},
+			isValid: false,
+		},
+		{
+			name: "Bootnode pool without a node key for each replica",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = ""
+				polkadot.Spec.NodePools = []NodePool{{Role: "bootnode", Replicas: 2}}
+				polkadot.Spec.Bootnode.NodeKeys = []string{strings.Repeat("01", 32)}
+			},
+			isValid: false,
+		},
+		{
+			name: "Invalid node key of a bootnode",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Bootnode"
+				polkadot.Spec.Bootnode.Replicas = 1
+				polkadot.Spec.Bootnode.NodeKeys = []string{"0000000000000000000000000000000000000000000000000000000000000031z"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Custom chainspec",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "chainspec.json"}
+			},
+			isValid: true,
+		},
+		{
+			name: "Custom chainspec without key",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}}
+			},
+			isValid: false,
+		},
+		{
+			name:    "Well-known chain",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Chain = "kusama" },
+			isValid: true,
+		},
+		{
+			name:    "Custom chain without chainspec",
+			mutate:  func(polkadot *Polkadot) { polkadot.Spec.Chain = "custom" },
+			isValid: false,
+		},
+		{
+			name: "Chainspec of a well-known chain",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Chain = "kusama"
+				polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "chainspec.json"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Chainspec builder",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Chain = "custom"
+				polkadot.Spec.ChainspecBuilder = ChainspecBuilder{
+					Enabled:     true,
+					Authorities: []GenesisAuthority{{Aura: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", Grandpa: "5FA9nQDVg267DEd8m1ZypXLBnvN7SFxYwV7ndqSYGiN9TTpu"}},
+					Balances:    []GenesisBalance{{Address: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", Amount: "1152921504606846976"}},
+					SudoKey:     "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+				}
+			},
+			isValid: true,
+		},
+		{
+			name: "Chainspec builder with an invalid sudo key",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.ChainspecBuilder = ChainspecBuilder{Enabled: true, SudoKey: "0x1234"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Chainspec builder of a well-known chain",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Chain = "westend"
+				polkadot.Spec.ChainspecBuilder = ChainspecBuilder{Enabled: true}
+			},
+			isValid: false,
+		},
+		{
+			name: "Payouts",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Payouts = Payouts{
+					Enabled:          true,
+					Stashes:          []string{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"},
+					AccountSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "payouts"}, Key: "seed"},
+				}
+			},
+			isValid: true,
+		},
+		{
+			name: "Payouts without account",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Payouts = Payouts{Enabled: true, Stashes: []string{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Staking bootstrap",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.KeyRotation.OnFirstStart = true
+				polkadot.Spec.Validator.Staking = Staking{
+					Enabled:        true,
+					Stash:          "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+					StashSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "stash"}, Key: "seed"},
+					Bond:           "1000000000000",
+				}
+			},
+			isValid: true,
+		},
+		{
+			name: "Staking bootstrap without key rotation",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.Staking = Staking{
+					Enabled:        true,
+					Stash:          "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+					StashSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "stash"}, Key: "seed"},
+					Bond:           "1000000000000",
+				}
+			},
+			isValid: false,
+		},
+		{
+			name: "Slash detection",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.SlashDetection = SlashDetection{Enabled: true, Stash: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}
+			},
+			isValid: true,
+		},
+		{
+			name: "Slash detection without stash",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.SlashDetection = SlashDetection{Enabled: true}
+			},
+			isValid: false,
+		},
+		{
+			name: "External sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.ExternalSentries = []string{"/dns4/sentry.b.example.com/tcp/30333/p2p/12D3KooWEyoppNCUx8Yx66oV9fJnriXwCcXwDDUA2kj6vnc6iDEp"}
+			},
+			isValid: true,
+		},
+		{
+			name: "External sentry without peer ID",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.ExternalSentries = []string{"/dns4/sentry.b.example.com/tcp/30333"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Sentries fronting an external validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Sentry"
+				polkadot.Spec.Sentry.ExternalValidatorAddr = "/ip4/198.51.100.7/tcp/30333"
+				polkadot.Spec.Sentry.ReservedValidatorID = "12D3KooWEyoppNCUx8Yx66oV9fJnriXwCcXwDDUA2kj6vnc6iDEp"
+			},
+			isValid: true,
+		},
+		{
+			name: "External validator without peer ID",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "Sentry"
+				polkadot.Spec.Sentry.ExternalValidatorAddr = "/ip4/198.51.100.7/tcp/30333"
+				polkadot.Spec.Sentry.ReservedValidatorID = ""
+			},
+			isValid: false,
+		},
+		{
+			name: "External validator with a deployed validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = "SentryAndValidator"
+				polkadot.Spec.Sentry.ExternalValidatorAddr = "/ip4/198.51.100.7/tcp/30333"
+				polkadot.Spec.Sentry.ReservedValidatorID = "12D3KooWEyoppNCUx8Yx66oV9fJnriXwCcXwDDUA2kj6vnc6iDEp"
+			},
+			isValid: false,
+		},
+		{
+			name: "Hardened pods",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Hardened = true
+			},
+			isValid: true,
+		},
+		{
+			name: "Hardened pods on the host network",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Hardened = true
+				polkadot.Spec.Sentry.HostNetwork = true
+			},
+			isValid: false,
+		},
+		{
+			name: "Era exporter",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.EraExporter = EraExporter{Enabled: true, Stash: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}
+			},
+			isValid: true,
+		},
+		{
+			name: "Era exporter on a port of the client",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Ports.Prometheus = 9700
+				polkadot.Spec.Validator.EraExporter = EraExporter{Enabled: true, Stash: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", Port: 9700}
+			},
+			isValid: false,
+		},
+		{
+			name: "Client version of the sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.ClientVersion = "v0.8.3"
+			},
+			isValid: true,
+		},
+		{
+			name: "Invalid client version of the validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.ClientVersion = "v0.8.3:latest"
+			},
+			isValid: false,
+		},
+		{
+			name: "Sidecar of the sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "log-shipper", Image: "fluent/fluent-bit"}}
+			},
+			isValid: true,
+		},
+		{
+			name: "Sidecar named after the client container",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "polkadot", Image: "fluent/fluent-bit"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Sidecar without image",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.Sidecars = []corev1.Container{{Name: "exporter"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Sidecar with an invalid port",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "exporter", Image: "exporter", Ports: []corev1.ContainerPort{{ContainerPort: 70000}}}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Sidecar with an invalid environment variable",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "exporter", Image: "exporter", Env: []corev1.EnvVar{{Name: "1-PORT", Value: "9100"}}}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Init container named after the bootstrap one",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.InitContainers = []corev1.Container{{Name: "bootstrap-data", Image: "alpine"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Init container with an invalid protocol",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.InitContainers = []corev1.Container{{Name: "fetch-keys", Image: "alpine", Ports: []corev1.ContainerPort{{ContainerPort: 8080, Protocol: "HTTP"}}}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Node pools of a mixed topology",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = ""
+				polkadot.Spec.NodePools = []NodePool{{Role: "sentry", Replicas: 2}, {Role: "validator"}, {Role: "rpcnode", Replicas: 2}, {Role: "archive", Replicas: 1}}
+			},
+			isValid: true,
+		},
+		{
+			name: "Node pools together with a Kind",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.NodePools = []NodePool{{Role: "archive", Replicas: 1}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Node pools with a duplicate role",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = ""
+				polkadot.Spec.NodePools = []NodePool{{Role: "sentry"}, {Role: "sentry", Replicas: 2}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Node pool of several validators",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = ""
+				polkadot.Spec.NodePools = []NodePool{{Role: "validator", Replicas: 2}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Node pool without replicas",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Kind = ""
+				polkadot.Spec.NodePools = []NodePool{{Role: "archive"}}
+			},
+			isValid: false,
+		},
+		{
+			name: "Generated node keys of the sentries",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.GenerateNodeKeys = true
+			},
+			isValid: true,
+		},
+		{
+			name: "Generated node keys with a node key",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.GenerateNodeKeys = true
+				polkadot.Spec.Sentry.NodeKey = "0000000000000000000000000000000000000000000000000000000000000001"
+			},
+			isValid: false,
+		},
+		{
+			name: "Generated node keys of the validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.GenerateNodeKeys = true
+			},
+			isValid: false,
+		},
+		{
+			name: "OnDelete update strategy of the validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+				polkadot.Spec.Upgrade.SyncGated = true
+			},
+			isValid: true,
+		},
+		{
+			name: "Unknown update strategy",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Sentry.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{Type: "Recreate"}
+			},
+			isValid: false,
+		},
+		{
+			name: "Partition of a sync gated upgrade",
+			mutate: func(polkadot *Polkadot) {
+				partition := int32(1)
+				polkadot.Spec.Sentry.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}}
+				polkadot.Spec.Upgrade.SyncGated = true
+			},
+			isValid: false,
+		},
+		{
+			name: "Ingress of the validator",
+			mutate: func(polkadot *Polkadot) {
+				polkadot.Spec.Validator.Ingress = Ingress{Enabled: true, Host: "validator.example.com"}
+			},
+			isValid: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polkadot := &Polkadot{
+				Spec: PolkadotSpec{
+					ClientVersion: "v0.8.2",
+					Kind:          "SentryAndValidator",
+					Sentry:        Sentry{Replicas: 1},
+				},
+			}
+			test.mutate(polkadot)
+
+			err := polkadot.ValidateCreate()
+			if (err == nil) != test.isValid {
+				t.Fatalf("ValidateCreate: unexpected result (%v)", err)
+			}
+		})
+	}
+}
+
+func TestDefaultPolkadot(t *testing.T) {
+
+	polkadot := &Polkadot{Spec: PolkadotSpec{Kind: "SentryAndValidator"}}
+	polkadot.Name = "polkadot-cr"
+	polkadot.Spec.Sentry.ClientName = "my-sentry"
+
+	polkadot.Default()
+
+	spec := polkadot.Spec
+	if spec.ClientVersion != defaultClientVersion {
+		t.Fatalf("Default: unexpected clientVersion (%v)", spec.ClientVersion)
+	}
+	if spec.Sentry.Replicas != 1 || spec.Sentry.ClientName != "my-sentry" || spec.Validator.ClientName != "polkadot-cr-validator" {
+		t.Fatalf("Default: unexpected node defaults (%v)", spec)
+	}
+	if spec.Validator.Resources.Requests.Cpu().String() != defaultCPURequest || spec.Sentry.Resources.Requests.Memory().String() != defaultMemoryRequest {
+		t.Fatalf("Default: unexpected resource requests (%v)", spec)
+	}
+	if spec.Bootnode.Replicas != 0 || spec.Bootnode.ClientName != "" {
+		t.Fatalf("Default: defaults set on a role not deployed by the Kind (%v)", spec.Bootnode)
+	}
+	if err := polkadot.ValidateCreate(); err != nil {
+		t.Fatalf("Default: the defaulted CR is not valid (%v)", err)
+	}
+}
+
+func TestDefaultPolkadotChain(t *testing.T) {
+
+	polkadot := &Polkadot{Spec: PolkadotSpec{Kind: "Sentry", Chain: "westend"}}
+	polkadot.Default()
+	if requests := polkadot.Spec.Sentry.Resources.Requests; requests.Cpu().String() != "500m" || requests.Memory().String() != "1Gi" {
+		t.Fatalf("Default: unexpected resource requests of the chain (%v)", requests)
+	}
+
+	polkadot = &Polkadot{Spec: PolkadotSpec{Kind: "Sentry", ChainspecConfigMapRef: &corev1.ConfigMapKeySelector{Key: "chainspec.json"}}}
+	polkadot.Default()
+	if polkadot.Spec.Chain != customChain {
+		t.Fatalf("Default: unexpected chain (%v)", polkadot.Spec.Chain)
+	}
+}
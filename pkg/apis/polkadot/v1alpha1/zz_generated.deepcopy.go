@@ -5,140 +5,1358 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Alerts) DeepCopyInto(out *Alerts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Alerts.
+func (in *Alerts) DeepCopy() *Alerts {
+	if in == nil {
+		return nil
+	}
+	out := new(Alerts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntiAffinity) DeepCopyInto(out *AntiAffinity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntiAffinity.
+func (in *AntiAffinity) DeepCopy() *AntiAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(AntiAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedGeneration) DeepCopyInto(out *AppliedGeneration) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.ChangedFields != nil {
+		in, out := &in.ChangedFields, &out.ChangedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedGeneration.
+func (in *AppliedGeneration) DeepCopy() *AppliedGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Archive) DeepCopyInto(out *Archive) {
+	*out = *in
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Archive.
+func (in *Archive) DeepCopy() *Archive {
+	if in == nil {
+		return nil
+	}
+	out := new(Archive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2beta2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keda != nil {
+		in, out := &in.Keda, &out.Keda
+		*out = new(Keda)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Autoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bootnode) DeepCopyInto(out *Bootnode) {
+	*out = *in
+	if in.NodeKeys != nil {
+		in, out := &in.NodeKeys, &out.NodeKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bootnode.
+func (in *Bootnode) DeepCopy() *Bootnode {
+	if in == nil {
+		return nil
+	}
+	out := new(Bootnode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bootstrap.
+func (in *Bootstrap) DeepCopy() *Bootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(Bootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Canary) DeepCopyInto(out *Canary) {
+	*out = *in
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Canary.
+func (in *Canary) DeepCopy() *Canary {
+	if in == nil {
+		return nil
+	}
+	out := new(Canary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChainspecBuilder) DeepCopyInto(out *ChainspecBuilder) {
+	*out = *in
+	if in.Authorities != nil {
+		in, out := &in.Authorities, &out.Authorities
+		*out = make([]GenesisAuthority, len(*in))
+		copy(*out, *in)
+	}
+	if in.Balances != nil {
+		in, out := &in.Balances, &out.Balances
+		*out = make([]GenesisBalance, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainspecBuilder.
+func (in *ChainspecBuilder) DeepCopy() *ChainspecBuilder {
+	if in == nil {
+		return nil
+	}
+	out := new(ChainspecBuilder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicy) DeepCopyInto(out *CleanupPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicy.
+func (in *CleanupPolicy) DeepCopy() *CleanupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Collator) DeepCopyInto(out *Collator) {
+	*out = *in
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Collator.
+func (in *Collator) DeepCopy() *Collator {
+	if in == nil {
+		return nil
+	}
+	out := new(Collator)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataPersistenceSupport) DeepCopyInto(out *DataPersistenceSupport) {
 	*out = *in
-	in.PersistentVolumeClaim.DeepCopyInto(&out.PersistentVolumeClaim)
+	in.PersistentVolumeClaim.DeepCopyInto(&out.PersistentVolumeClaim)
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(RestoreFrom)
+		**out = **in
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(Bootstrap)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPersistenceSupport.
+func (in *DataPersistenceSupport) DeepCopy() *DataPersistenceSupport {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPersistenceSupport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EraExporter) DeepCopyInto(out *EraExporter) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EraExporter.
+func (in *EraExporter) DeepCopy() *EraExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(EraExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNS.
+func (in *ExternalDNS) DeepCopy() *ExternalDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverStatus) DeepCopyInto(out *FailoverStatus) {
+	*out = *in
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverStatus.
+func (in *FailoverStatus) DeepCopy() *FailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenesisAuthority) DeepCopyInto(out *GenesisAuthority) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenesisAuthority.
+func (in *GenesisAuthority) DeepCopy() *GenesisAuthority {
+	if in == nil {
+		return nil
+	}
+	out := new(GenesisAuthority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenesisBalance) DeepCopyInto(out *GenesisBalance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenesisBalance.
+func (in *GenesisBalance) DeepCopy() *GenesisBalance {
+	if in == nil {
+		return nil
+	}
+	out := new(GenesisBalance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbe.
+func (in *HealthProbe) DeepCopy() *HealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HighAvailability) DeepCopyInto(out *HighAvailability) {
+	*out = *in
+	if in.FailoverDelay != nil {
+		in, out := &in.FailoverDelay, &out.FailoverDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.LeaderElection.DeepCopyInto(&out.LeaderElection)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HighAvailability.
+func (in *HighAvailability) DeepCopy() *HighAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(HighAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePinning) DeepCopyInto(out *ImagePinning) {
+	*out = *in
+	if in.RefreshPeriod != nil {
+		in, out := &in.RefreshPeriod, &out.RefreshPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePinning.
+func (in *ImagePinning) DeepCopy() *ImagePinning {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(IssuerReference)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Keda) DeepCopyInto(out *Keda) {
+	*out = *in
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]KedaTrigger, len(*in))
+		copy(*out, *in)
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Keda.
+func (in *Keda) DeepCopy() *Keda {
+	if in == nil {
+		return nil
+	}
+	out := new(Keda)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KedaTrigger) DeepCopyInto(out *KedaTrigger) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaTrigger.
+func (in *KedaTrigger) DeepCopy() *KedaTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(KedaTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotation) DeepCopyInto(out *KeyRotation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotation.
+func (in *KeyRotation) DeepCopy() *KeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElection) DeepCopyInto(out *LeaderElection) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElection.
+func (in *LeaderElection) DeepCopy() *LeaderElection {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsSupport) DeepCopyInto(out *MetricsSupport) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSupport.
+func (in *MetricsSupport) DeepCopy() *MetricsSupport {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsSupport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitoring) DeepCopyInto(out *Monitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Alerts = in.Alerts
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
+func (in *Monitoring) DeepCopy() *Monitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkHealth) DeepCopyInto(out *NetworkHealth) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkHealth.
+func (in *NetworkHealth) DeepCopy() *NetworkHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOptions) DeepCopyInto(out *NodeOptions) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	out.AntiAffinity = in.AntiAffinity
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(Vault)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Maintenance = in.Maintenance
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	in.VerticalAutoscaling.DeepCopyInto(&out.VerticalAutoscaling)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	in.ExternalDNS.DeepCopyInto(&out.ExternalDNS)
+	out.Probes = in.Probes
+	in.StallDetection.DeepCopyInto(&out.StallDetection)
+	in.SyncReadiness.DeepCopyInto(&out.SyncReadiness)
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOptions.
+func (in *NodeOptions) DeepCopy() *NodeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(DataPersistenceSupport)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Payouts) DeepCopyInto(out *Payouts) {
+	*out = *in
+	if in.Stashes != nil {
+		in, out := &in.Stashes, &out.Stashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccountSecretRef != nil {
+		in, out := &in.AccountSecretRef, &out.AccountSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Payouts.
+func (in *Payouts) DeepCopy() *Payouts {
+	if in == nil {
+		return nil
+	}
+	out := new(Payouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PayoutsStatus) DeepCopyInto(out *PayoutsStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PayoutsStatus.
+func (in *PayoutsStatus) DeepCopy() *PayoutsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PayoutsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PinnedImage) DeepCopyInto(out *PinnedImage) {
+	*out = *in
+	in.ResolvedTime.DeepCopyInto(&out.ResolvedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PinnedImage.
+func (in *PinnedImage) DeepCopy() *PinnedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PinnedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudget) DeepCopyInto(out *PodDisruptionBudget) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudget.
+func (in *PodDisruptionBudget) DeepCopy() *PodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Polkadot) DeepCopyInto(out *Polkadot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Polkadot.
+func (in *Polkadot) DeepCopy() *Polkadot {
+	if in == nil {
+		return nil
+	}
+	out := new(Polkadot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Polkadot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotCondition) DeepCopyInto(out *PolkadotCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotCondition.
+func (in *PolkadotCondition) DeepCopy() *PolkadotCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotList) DeepCopyInto(out *PolkadotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Polkadot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotList.
+func (in *PolkadotList) DeepCopy() *PolkadotList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolkadotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotReference) DeepCopyInto(out *PolkadotReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotReference.
+func (in *PolkadotReference) DeepCopy() *PolkadotReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotSpec) DeepCopyInto(out *PolkadotSpec) {
+	*out = *in
+	in.Validator.DeepCopyInto(&out.Validator)
+	in.Sentry.DeepCopyInto(&out.Sentry)
+	in.Bootnode.DeepCopyInto(&out.Bootnode)
+	in.Archive.DeepCopyInto(&out.Archive)
+	in.RpcNode.DeepCopyInto(&out.RpcNode)
+	in.Collator.DeepCopyInto(&out.Collator)
+	out.MetricsSupport = in.MetricsSupport
+	out.SecureCommunicationSupport = in.SecureCommunicationSupport
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Backup = in.Backup
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	in.NetworkHealth.DeepCopyInto(&out.NetworkHealth)
+	out.CleanupPolicy = in.CleanupPolicy
+	in.Upgrade.DeepCopyInto(&out.Upgrade)
+	in.Payouts.DeepCopyInto(&out.Payouts)
+	if in.BootnodesFrom != nil {
+		in, out := &in.BootnodesFrom, &out.BootnodesFrom
+		*out = make([]PolkadotReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Ports = in.Ports
+	if in.ChainspecConfigMapRef != nil {
+		in, out := &in.ChainspecConfigMapRef, &out.ChainspecConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ChainspecBuilder.DeepCopyInto(&out.ChainspecBuilder)
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotSpec.
+func (in *PolkadotSpec) DeepCopy() *PolkadotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotStatus) DeepCopyInto(out *PolkadotStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SessionKeys != nil {
+		in, out := &in.SessionKeys, &out.SessionKeys
+		*out = new(SessionKeysStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReservedPeers != nil {
+		in, out := &in.ReservedPeers, &out.ReservedPeers
+		*out = new(ReservedPeersStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootnodes != nil {
+		in, out := &in.Bootnodes, &out.Bootnodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicAddresses != nil {
+		in, out := &in.PublicAddresses, &out.PublicAddresses
+		*out = make([]PublicAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.SentryAddresses != nil {
+		in, out := &in.SentryAddresses, &out.SentryAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Payouts != nil {
+		in, out := &in.Payouts, &out.Payouts
+		*out = new(PayoutsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slashes != nil {
+		in, out := &in.Slashes, &out.Slashes
+		*out = new(SlashesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PinnedImages != nil {
+		in, out := &in.PinnedImages, &out.PinnedImages
+		*out = make([]PinnedImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]AppliedGeneration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]PolkadotCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotStatus.
+func (in *PolkadotStatus) DeepCopy() *PolkadotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ports) DeepCopyInto(out *Ports) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPersistenceSupport.
-func (in *DataPersistenceSupport) DeepCopy() *DataPersistenceSupport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ports.
+func (in *Ports) DeepCopy() *Ports {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPersistenceSupport)
+	out := new(Ports)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsSupport) DeepCopyInto(out *MetricsSupport) {
+func (in *Probes) DeepCopyInto(out *Probes) {
 	*out = *in
+	out.Startup = in.Startup
+	out.Liveness = in.Liveness
+	out.Readiness = in.Readiness
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSupport.
-func (in *MetricsSupport) DeepCopy() *MetricsSupport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probes.
+func (in *Probes) DeepCopy() *Probes {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsSupport)
+	out := new(Probes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Polkadot) DeepCopyInto(out *Polkadot) {
+func (in *PublicAddress) DeepCopyInto(out *PublicAddress) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Polkadot.
-func (in *Polkadot) DeepCopy() *Polkadot {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicAddress.
+func (in *PublicAddress) DeepCopy() *PublicAddress {
 	if in == nil {
 		return nil
 	}
-	out := new(Polkadot)
+	out := new(PublicAddress)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Polkadot) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSigner) DeepCopyInto(out *RemoteSigner) {
+	*out = *in
+	if in.Sidecar != nil {
+		in, out := &in.Sidecar, &out.Sidecar
+		*out = new(v1.Container)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSigner.
+func (in *RemoteSigner) DeepCopy() *RemoteSigner {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSigner)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PolkadotList) DeepCopyInto(out *PolkadotList) {
+func (in *ReservedPeersStatus) DeepCopyInto(out *ReservedPeersStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Polkadot, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Validators != nil {
+		in, out := &in.Validators, &out.Validators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sentries != nil {
+		in, out := &in.Sentries, &out.Sentries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotList.
-func (in *PolkadotList) DeepCopy() *PolkadotList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReservedPeersStatus.
+func (in *ReservedPeersStatus) DeepCopy() *ReservedPeersStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PolkadotList)
+	out := new(ReservedPeersStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PolkadotList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceMetadata) DeepCopyInto(out *ResourceMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	return nil
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMetadata.
+func (in *ResourceMetadata) DeepCopy() *ResourceMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceMetadata)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PolkadotSpec) DeepCopyInto(out *PolkadotSpec) {
+func (in *RestoreFrom) DeepCopyInto(out *RestoreFrom) {
 	*out = *in
-	in.Validator.DeepCopyInto(&out.Validator)
-	in.Sentry.DeepCopyInto(&out.Sentry)
-	out.MetricsSupport = in.MetricsSupport
-	out.SecureCommunicationSupport = in.SecureCommunicationSupport
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotSpec.
-func (in *PolkadotSpec) DeepCopy() *PolkadotSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreFrom.
+func (in *RestoreFrom) DeepCopy() *RestoreFrom {
 	if in == nil {
 		return nil
 	}
-	out := new(PolkadotSpec)
+	out := new(RestoreFrom)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PolkadotStatus) DeepCopyInto(out *PolkadotStatus) {
+func (in *RoleStatus) DeepCopyInto(out *RoleStatus) {
 	*out = *in
 	if in.Nodes != nil {
 		in, out := &in.Nodes, &out.Nodes
-		*out = make([]string, len(*in))
+		*out = make([]NodeStatus, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotStatus.
-func (in *PolkadotStatus) DeepCopy() *PolkadotStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleStatus.
+func (in *RoleStatus) DeepCopy() *RoleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PolkadotStatus)
+	out := new(RoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RpcNode) DeepCopyInto(out *RpcNode) {
+	*out = *in
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RpcNode.
+func (in *RpcNode) DeepCopy() *RpcNode {
+	if in == nil {
+		return nil
+	}
+	out := new(RpcNode)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -162,8 +1380,19 @@ func (in *SecureCommunicationSupport) DeepCopy() *SecureCommunicationSupport {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sentry) DeepCopyInto(out *Sentry) {
 	*out = *in
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalValidators != nil {
+		in, out := &in.ExternalValidators, &out.ExternalValidators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
 	return
 }
 
@@ -177,11 +1406,184 @@ func (in *Sentry) DeepCopy() *Sentry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionKeysStatus) DeepCopyInto(out *SessionKeysStatus) {
+	*out = *in
+	if in.RotationTime != nil {
+		in, out := &in.RotationTime, &out.RotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RegistrationTime != nil {
+		in, out := &in.RegistrationTime, &out.RegistrationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionKeysStatus.
+func (in *SessionKeysStatus) DeepCopy() *SessionKeysStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionKeysStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlashDetection) DeepCopyInto(out *SlashDetection) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlashDetection.
+func (in *SlashDetection) DeepCopy() *SlashDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(SlashDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlashesStatus) DeepCopyInto(out *SlashesStatus) {
+	*out = *in
+	if in.LastSlashEra != nil {
+		in, out := &in.LastSlashEra, &out.LastSlashEra
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlashesStatus.
+func (in *SlashesStatus) DeepCopy() *SlashesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlashesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Staking) DeepCopyInto(out *Staking) {
+	*out = *in
+	if in.StashSecretRef != nil {
+		in, out := &in.StashSecretRef, &out.StashSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerSecretRef != nil {
+		in, out := &in.ControllerSecretRef, &out.ControllerSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Staking.
+func (in *Staking) DeepCopy() *Staking {
+	if in == nil {
+		return nil
+	}
+	out := new(Staking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StallDetection) DeepCopyInto(out *StallDetection) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StallDetection.
+func (in *StallDetection) DeepCopy() *StallDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(StallDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncReadiness) DeepCopyInto(out *SyncReadiness) {
+	*out = *in
+	if in.MaxBlocksBehind != nil {
+		in, out := &in.MaxBlocksBehind, &out.MaxBlocksBehind
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncReadiness.
+func (in *SyncReadiness) DeepCopy() *SyncReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+	*out = *in
+	in.Canary.DeepCopyInto(&out.Canary)
+	in.ImagePinning.DeepCopyInto(&out.ImagePinning)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(Upgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Validator) DeepCopyInto(out *Validator) {
 	*out = *in
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalSentries != nil {
+		in, out := &in.ExternalSentries, &out.ExternalSentries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	in.DataPersistenceSupport.DeepCopyInto(&out.DataPersistenceSupport)
+	out.KeyRotation = in.KeyRotation
+	in.Staking.DeepCopyInto(&out.Staking)
+	out.SlashDetection = in.SlashDetection
+	in.EraExporter.DeepCopyInto(&out.EraExporter)
+	if in.KeystoreSecretRef != nil {
+		in, out := &in.KeystoreSecretRef, &out.KeystoreSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RemoteSigner != nil {
+		in, out := &in.RemoteSigner, &out.RemoteSigner
+		*out = new(RemoteSigner)
+		(*in).DeepCopyInto(*out)
+	}
+	in.HighAvailability.DeepCopyInto(&out.HighAvailability)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
 	return
 }
 
@@ -194,3 +1596,75 @@ func (in *Validator) DeepCopy() *Validator {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Vault) DeepCopyInto(out *Vault) {
+	*out = *in
+	if in.NodeKey != nil {
+		in, out := &in.NodeKey, &out.NodeKey
+		*out = new(VaultSecret)
+		**out = **in
+	}
+	if in.Keystore != nil {
+		in, out := &in.Keystore, &out.Keystore
+		*out = make([]VaultSecret, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vault.
+func (in *Vault) DeepCopy() *Vault {
+	if in == nil {
+		return nil
+	}
+	out := new(Vault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecret) DeepCopyInto(out *VaultSecret) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecret.
+func (in *VaultSecret) DeepCopy() *VaultSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalAutoscaling) DeepCopyInto(out *VerticalAutoscaling) {
+	*out = *in
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoscaling.
+func (in *VerticalAutoscaling) DeepCopy() *VerticalAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,6 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+// Package v1beta1 contains API Schema definitions for the polkadot v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=polkadot.swisscomblockchain.com
+package v1beta1
@@ -0,0 +1,234 @@
+package v1beta1
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &Polkadot{}
+
+// ConvertTo converts this Polkadot to the Hub version (v1alpha1)
+func (src *Polkadot) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Polkadot)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	spec := src.Spec
+	pools := spec.NodePools
+	dst.Spec = v1alpha1.PolkadotSpec{
+		ClientVersion: spec.ClientVersion,
+		Kind:          spec.Kind,
+		Validator: v1alpha1.Validator{
+			ClientName:             pools.Validator.ClientName,
+			NodeKey:                getFirstNodeKey(pools.Validator.NodeKeys),
+			NodeKeySecretRef:       pools.Validator.NodeKeySecretRef,
+			ReservedSentryID:       pools.Validator.ReservedPeerID,
+			ExternalSentries:       pools.Validator.ExternalReservedPeers,
+			Resources:              pools.Validator.Resources,
+			DataPersistenceSupport: pools.Validator.Storage,
+			KeyRotation:            pools.Validator.KeyRotation,
+			Staking:                pools.Validator.Staking,
+			SlashDetection:         pools.Validator.SlashDetection,
+			EraExporter:            pools.Validator.EraExporter,
+			KeystoreSecretRef:      pools.Validator.KeystoreSecretRef,
+			RemoteSigner:           pools.Validator.RemoteSigner,
+			HighAvailability:       pools.Validator.HighAvailability,
+			NodeOptions:            pools.Validator.NodeOptions,
+		},
+		Sentry: v1alpha1.Sentry{
+			Replicas:               pools.Sentry.Replicas,
+			ClientName:             pools.Sentry.ClientName,
+			NodeKey:                getFirstNodeKey(pools.Sentry.NodeKeys),
+			NodeKeySecretRef:       pools.Sentry.NodeKeySecretRef,
+			ReservedValidatorID:    pools.Sentry.ReservedPeerID,
+			ExternalValidators:     pools.Sentry.ExternalReservedPeers,
+			ExternalValidatorAddr:  pools.Sentry.ReservedPeerAddr,
+			Resources:              pools.Sentry.Resources,
+			DataPersistenceSupport: pools.Sentry.Storage,
+			NodeOptions:            pools.Sentry.NodeOptions,
+		},
+		Bootnode: v1alpha1.Bootnode{
+			Replicas:               pools.Bootnode.Replicas,
+			ClientName:             pools.Bootnode.ClientName,
+			NodeKeys:               pools.Bootnode.NodeKeys,
+			Resources:              pools.Bootnode.Resources,
+			DataPersistenceSupport: pools.Bootnode.Storage,
+			NodeOptions:            pools.Bootnode.NodeOptions,
+		},
+		Archive: v1alpha1.Archive{
+			Replicas:               pools.Archive.Replicas,
+			ClientName:             pools.Archive.ClientName,
+			NodeKey:                getFirstNodeKey(pools.Archive.NodeKeys),
+			NodeKeySecretRef:       pools.Archive.NodeKeySecretRef,
+			Resources:              pools.Archive.Resources,
+			DataPersistenceSupport: pools.Archive.Storage,
+			NodeOptions:            pools.Archive.NodeOptions,
+		},
+		RpcNode: v1alpha1.RpcNode{
+			Replicas:               pools.RpcNode.Replicas,
+			ClientName:             pools.RpcNode.ClientName,
+			NodeKey:                getFirstNodeKey(pools.RpcNode.NodeKeys),
+			NodeKeySecretRef:       pools.RpcNode.NodeKeySecretRef,
+			Resources:              pools.RpcNode.Resources,
+			DataPersistenceSupport: pools.RpcNode.Storage,
+			NodeOptions:            pools.RpcNode.NodeOptions,
+		},
+		Collator: v1alpha1.Collator{
+			Replicas:               pools.Collator.Replicas,
+			ClientName:             pools.Collator.ClientName,
+			NodeKey:                getFirstNodeKey(pools.Collator.NodeKeys),
+			NodeKeySecretRef:       pools.Collator.NodeKeySecretRef,
+			Resources:              pools.Collator.Resources,
+			DataPersistenceSupport: pools.Collator.Storage,
+			NodeOptions:            pools.Collator.NodeOptions,
+			Image:                  pools.Collator.Image,
+			Binary:                 pools.Collator.Binary,
+			ParachainChainSpec:     pools.Collator.ParachainChainSpec,
+			RelayChainSpec:         pools.Collator.RelayChainSpec,
+			RelayChainEndpoint:     pools.Collator.RelayChainEndpoint,
+		},
+		MetricsSupport:             v1alpha1.MetricsSupport{Enabled: spec.Metrics},
+		SecureCommunicationSupport: v1alpha1.SecureCommunicationSupport{Enabled: spec.SecureCommunication},
+		Metadata:                   spec.Metadata,
+		ImagePullSecrets:           spec.ImagePullSecrets,
+		Hardened:                   spec.Hardened,
+		Backup:                     spec.Backup,
+		Monitoring:                 spec.Monitoring,
+		NetworkHealth:              spec.NetworkHealth,
+		CleanupPolicy:              spec.CleanupPolicy,
+		Paused:                     spec.Paused,
+		HistoryLimit:               spec.HistoryLimit,
+		Upgrade:                    spec.Upgrade,
+		Payouts:                    spec.Payouts,
+		BootnodesFrom:              spec.BootnodesFrom,
+		Ports:                      spec.Ports,
+		Chain:                      spec.Chain,
+		ChainspecConfigMapRef:      spec.ChainspecConfigMapRef,
+		ChainspecBuilder:           spec.ChainspecBuilder,
+		NodePools:                  spec.Pools,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version
+func (dst *Polkadot) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Polkadot)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	spec := src.Spec
+	dst.Spec = PolkadotSpec{
+		ClientVersion: spec.ClientVersion,
+		Kind:          spec.Kind,
+		NodePools: NodePools{
+			Sentry: NodePool{
+				Replicas:              spec.Sentry.Replicas,
+				ClientName:            spec.Sentry.ClientName,
+				NodeKeys:              getNodeKeys(spec.Sentry.NodeKey),
+				NodeKeySecretRef:      spec.Sentry.NodeKeySecretRef,
+				ReservedPeerID:        spec.Sentry.ReservedValidatorID,
+				ExternalReservedPeers: spec.Sentry.ExternalValidators,
+				ReservedPeerAddr:      spec.Sentry.ExternalValidatorAddr,
+				Resources:             spec.Sentry.Resources,
+				Storage:               spec.Sentry.DataPersistenceSupport,
+				NodeOptions:           spec.Sentry.NodeOptions,
+			},
+			Validator: ValidatorPool{
+				NodePool: NodePool{
+					ClientName:            spec.Validator.ClientName,
+					NodeKeys:              getNodeKeys(spec.Validator.NodeKey),
+					NodeKeySecretRef:      spec.Validator.NodeKeySecretRef,
+					ReservedPeerID:        spec.Validator.ReservedSentryID,
+					ExternalReservedPeers: spec.Validator.ExternalSentries,
+					Resources:             spec.Validator.Resources,
+					Storage:               spec.Validator.DataPersistenceSupport,
+					NodeOptions:           spec.Validator.NodeOptions,
+				},
+				KeyRotation:       spec.Validator.KeyRotation,
+				Staking:           spec.Validator.Staking,
+				SlashDetection:    spec.Validator.SlashDetection,
+				EraExporter:       spec.Validator.EraExporter,
+				KeystoreSecretRef: spec.Validator.KeystoreSecretRef,
+				RemoteSigner:      spec.Validator.RemoteSigner,
+				HighAvailability:  spec.Validator.HighAvailability,
+			},
+			Bootnode: NodePool{
+				Replicas:    spec.Bootnode.Replicas,
+				ClientName:  spec.Bootnode.ClientName,
+				NodeKeys:    spec.Bootnode.NodeKeys,
+				Resources:   spec.Bootnode.Resources,
+				Storage:     spec.Bootnode.DataPersistenceSupport,
+				NodeOptions: spec.Bootnode.NodeOptions,
+			},
+			Archive: NodePool{
+				Replicas:         spec.Archive.Replicas,
+				ClientName:       spec.Archive.ClientName,
+				NodeKeys:         getNodeKeys(spec.Archive.NodeKey),
+				NodeKeySecretRef: spec.Archive.NodeKeySecretRef,
+				Resources:        spec.Archive.Resources,
+				Storage:          spec.Archive.DataPersistenceSupport,
+				NodeOptions:      spec.Archive.NodeOptions,
+			},
+			RpcNode: NodePool{
+				Replicas:         spec.RpcNode.Replicas,
+				ClientName:       spec.RpcNode.ClientName,
+				NodeKeys:         getNodeKeys(spec.RpcNode.NodeKey),
+				NodeKeySecretRef: spec.RpcNode.NodeKeySecretRef,
+				Resources:        spec.RpcNode.Resources,
+				Storage:          spec.RpcNode.DataPersistenceSupport,
+				NodeOptions:      spec.RpcNode.NodeOptions,
+			},
+			Collator: CollatorPool{
+				NodePool: NodePool{
+					Replicas:         spec.Collator.Replicas,
+					ClientName:       spec.Collator.ClientName,
+					NodeKeys:         getNodeKeys(spec.Collator.NodeKey),
+					NodeKeySecretRef: spec.Collator.NodeKeySecretRef,
+					Resources:        spec.Collator.Resources,
+					Storage:          spec.Collator.DataPersistenceSupport,
+					NodeOptions:      spec.Collator.NodeOptions,
+				},
+				Image:              spec.Collator.Image,
+				Binary:             spec.Collator.Binary,
+				ParachainChainSpec: spec.Collator.ParachainChainSpec,
+				RelayChainSpec:     spec.Collator.RelayChainSpec,
+				RelayChainEndpoint: spec.Collator.RelayChainEndpoint,
+			},
+		},
+		Metrics:               spec.MetricsSupport.Enabled,
+		SecureCommunication:   spec.SecureCommunicationSupport.Enabled,
+		Metadata:              spec.Metadata,
+		ImagePullSecrets:      spec.ImagePullSecrets,
+		Hardened:              spec.Hardened,
+		Backup:                spec.Backup,
+		Monitoring:            spec.Monitoring,
+		NetworkHealth:         spec.NetworkHealth,
+		CleanupPolicy:         spec.CleanupPolicy,
+		Paused:                spec.Paused,
+		HistoryLimit:          spec.HistoryLimit,
+		Upgrade:               spec.Upgrade,
+		Payouts:               spec.Payouts,
+		BootnodesFrom:         spec.BootnodesFrom,
+		Ports:                 spec.Ports,
+		Chain:                 spec.Chain,
+		ChainspecConfigMapRef: spec.ChainspecConfigMapRef,
+		ChainspecBuilder:      spec.ChainspecBuilder,
+		Pools:                 spec.NodePools,
+	}
+	return nil
+}
+
+// getFirstNodeKey returns the node key of the roles supporting a single one
+func getFirstNodeKey(nodeKeys []string) string {
+	if len(nodeKeys) == 0 {
+		return ""
+	}
+	return nodeKeys[0]
+}
+
+func getNodeKeys(nodeKey string) []string {
+	if nodeKey == "" {
+		return nil
+	}
+	return []string{nodeKey}
+}
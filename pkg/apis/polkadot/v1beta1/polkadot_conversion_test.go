@@ -0,0 +1,89 @@
+package v1beta1
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"testing"
+)
+
+func TestConvertPolkadot(t *testing.T) {
+
+	resources := corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5")}}
+	nodeOptions := v1alpha1.NodeOptions{SyncMode: "warp", ExtraArgs: []string{"--rpc-cors=all"}}
+
+	hub := &v1alpha1.Polkadot{
+		ObjectMeta: metav1.ObjectMeta{Name: "polkadot-cr", Namespace: "default"},
+		Spec: v1alpha1.PolkadotSpec{
+			ClientVersion: "v0.8.2",
+			Kind:          "SentryAndValidator",
+			Sentry: v1alpha1.Sentry{
+				Replicas:              2,
+				ClientName:            "sentry",
+				NodeKey:               "0000000000000000000000000000000000000000000000000000000000000013",
+				ReservedValidatorID:   "QmQtR1cdEaJM11qBWQBd34FoSgFichCjhtsBfrUFsVAjZM",
+				ExternalValidators:    []string{"/dns4/validator.a.example.com/tcp/30333/p2p/QmQMTLWkNwGf7P5MQv7kUHCynMg7jje6h3vbvwd2ALPPhm"},
+				ExternalValidatorAddr: "/ip4/198.51.100.7/tcp/30333",
+				Resources:             resources,
+				NodeOptions:           nodeOptions,
+			},
+			Validator: v1alpha1.Validator{
+				ClientName:             "validator",
+				NodeKeySecretRef:       &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "node-key"}, Key: "key"},
+				ReservedSentryID:       "QmQMTLWkNwGf7P5MQv7kUHCynMg7jje6h3vbvwd2ALPPhm",
+				ExternalSentries:       []string{"/dns4/sentry.b.example.com/tcp/30333/p2p/QmQtR1cdEaJM11qBWQBd34FoSgFichCjhtsBfrUFsVAjZM"},
+				KeyRotation:            v1alpha1.KeyRotation{OnFirstStart: true, SecretName: "session-keys"},
+				Staking:                v1alpha1.Staking{Enabled: true, Stash: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", Bond: "1000000000000"},
+				SlashDetection:         v1alpha1.SlashDetection{Enabled: true, RecentEras: 28},
+				EraExporter:            v1alpha1.EraExporter{Enabled: true, Port: 9700},
+				Resources:              resources,
+				DataPersistenceSupport: v1alpha1.DataPersistenceSupport{Enabled: true},
+				HighAvailability:       v1alpha1.HighAvailability{Enabled: true},
+			},
+			Bootnode:                   v1alpha1.Bootnode{Replicas: 2, NodeKeys: []string{"01", "02"}},
+			RpcNode:                    v1alpha1.RpcNode{Replicas: 1, ClientName: "rpc"},
+			Collator:                   v1alpha1.Collator{Image: "parity/polkadot-collator:latest", RelayChainSpec: "relay.json"},
+			MetricsSupport:             v1alpha1.MetricsSupport{Enabled: true},
+			SecureCommunicationSupport: v1alpha1.SecureCommunicationSupport{Enabled: true},
+			Monitoring:                 v1alpha1.Monitoring{Enabled: true, Interval: "30s"},
+			CleanupPolicy:              v1alpha1.CleanupPolicy{DeleteSnapshots: true},
+			Paused:                     true,
+			HistoryLimit:               5,
+			Hardened:                   true,
+			Upgrade:                    v1alpha1.Upgrade{SyncGated: true, Canary: v1alpha1.Canary{Enabled: true}},
+			Payouts:                    v1alpha1.Payouts{Enabled: true, Stashes: []string{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}},
+			BootnodesFrom:              []v1alpha1.PolkadotReference{{Name: "bootnodes", Namespace: "polkadot"}},
+			Ports:                      v1alpha1.Ports{P2P: 30334, RPC: 9934},
+			Chain:                      "custom",
+			ChainspecBuilder:           v1alpha1.ChainspecBuilder{Enabled: true, SudoKey: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"},
+			ChainspecConfigMapRef:      &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "chainspec.json"},
+			NodePools:                  []v1alpha1.NodePool{{Role: "archive", Replicas: 2, ExtraArgs: []string{"--pruning", "archive"}}},
+		},
+		Status: v1alpha1.PolkadotStatus{Replicas: 3, Synced: true},
+	}
+
+	spoke := &Polkadot{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom: (%v)", err)
+	}
+	pools := spoke.Spec.NodePools
+	if pools.Sentry.Replicas != 2 || len(pools.Sentry.NodeKeys) != 1 || pools.Sentry.ReservedPeerID != hub.Spec.Sentry.ReservedValidatorID {
+		t.Fatalf("ConvertFrom: unexpected sentry pool (%v)", pools.Sentry)
+	}
+	if !pools.Validator.KeyRotation.OnFirstStart || !pools.Validator.Storage.Enabled || pools.Validator.SyncMode != "" {
+		t.Fatalf("ConvertFrom: unexpected validator pool (%v)", pools.Validator)
+	}
+	if !spoke.Spec.Metrics || !spoke.Spec.SecureCommunication || spoke.Status.Replicas != 3 {
+		t.Fatalf("ConvertFrom: unexpected spec (%v)", spoke.Spec)
+	}
+
+	roundTrip := &v1alpha1.Polkadot{}
+	if err := spoke.ConvertTo(roundTrip); err != nil {
+		t.Fatalf("ConvertTo: (%v)", err)
+	}
+	if !reflect.DeepEqual(hub, roundTrip) {
+		t.Fatalf("ConvertTo: the round trip changed the Polkadot\n(%v)\n(%v)", hub, roundTrip)
+	}
+}
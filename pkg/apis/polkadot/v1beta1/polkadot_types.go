@@ -0,0 +1,160 @@
+package v1beta1
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolkadotSpec defines the desired state of Polkadot.
+// The settings of the nodes are grouped by role in the nodePools, sharing the same structure
+type PolkadotSpec struct {
+	ClientVersion string `json:"clientVersion"`
+	// Kind is the preset of the roles deployed, it must be left empty with the Pools
+	Kind string `json:"kind,omitempty"`
+	// NodePools defines the nodes of every role, only the pools of the roles deployed by the Kind, or listed in the Pools, are used
+	NodePools NodePools `json:"nodePools,omitempty"`
+	// Metrics exposes the Prometheus metrics of the nodes
+	Metrics bool `json:"metrics,omitempty"`
+	// SecureCommunication isolates the validator behind its sentries (Kind SentryAndValidator)
+	SecureCommunication bool `json:"secureCommunication,omitempty"`
+	// Metadata is added to the StatefulSets, pods and Services generated by the operator
+	Metadata v1alpha1.ResourceMetadata `json:"metadata,omitempty"`
+	// ImagePullSecrets are the references to the secrets used to pull the client images from private registries
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Hardened generates pods satisfying the restricted Pod Security Standard
+	Hardened bool `json:"hardened,omitempty"`
+	// Backup defines the scheduled snapshots of the data volumes
+	Backup v1alpha1.Backup `json:"backup,omitempty"`
+	// Monitoring defines the Prometheus Operator resources generated for the nodes
+	Monitoring v1alpha1.Monitoring `json:"monitoring,omitempty"`
+	// NetworkHealth defines when the nodes are reported with too few peers in the status conditions
+	NetworkHealth v1alpha1.NetworkHealth `json:"networkHealth,omitempty"`
+	// CleanupPolicy defines the resources, not owned by the CR, deleted by the operator together with the CR
+	CleanupPolicy v1alpha1.CleanupPolicy `json:"cleanupPolicy,omitempty"`
+	// Paused suspends the creation and the update of the resources of the CR, while its status is still reported
+	Paused bool `json:"paused,omitempty"`
+	// HistoryLimit is the number of applied generations of the spec kept in the history of the status, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+	// Upgrade defines how the changes of the nodes (e.g. of the client version) are rolled out
+	Upgrade v1alpha1.Upgrade `json:"upgrade,omitempty"`
+	// Payouts defines the scheduled payout of the staking rewards of validators
+	Payouts v1alpha1.Payouts `json:"payouts,omitempty"`
+	// BootnodesFrom are the Polkadot CRs of Kind Bootnode whose bootnodes are passed to the nodes of this CR (--bootnodes)
+	BootnodesFrom []v1alpha1.PolkadotReference `json:"bootnodesFrom,omitempty"`
+	// Ports are the ports the nodes listen on, propagated to their arguments, their containers, their Services and their probes
+	Ports v1alpha1.Ports `json:"ports,omitempty"`
+	// Chain is the chain of the nodes, passed as --chain: a well-known chain, whose presets (image, ports and resource requests)
+	// apply to the settings not set, or "custom" for the chainspec of chainspecConfigMapRef. The default chain of the client if empty
+	// +kubebuilder:validation:Enum=polkadot;kusama;westend;rococo;custom
+	Chain string `json:"chain,omitempty"`
+	// ChainspecConfigMapRef selects the key of a ConfigMap holding the raw chainspec JSON of a custom chain (e.g. of a private
+	// network), mounted in the nodes and passed as --chain. For the collators, it is the chain of the embedded relay chain node
+	ChainspecConfigMapRef *corev1.ConfigMapKeySelector `json:"chainspecConfigMapRef,omitempty"`
+	// ChainspecBuilder builds the chainspec of a private network from its genesis parameters, run by the nodes as the custom chain
+	ChainspecBuilder v1alpha1.ChainspecBuilder `json:"chainspecBuilder,omitempty"`
+	// Pools are the pools of nodes deployed by the CR in place of the roles of the Kind, their settings override the ones
+	// of the nodePools of their role
+	Pools []v1alpha1.NodePool `json:"pools,omitempty"`
+}
+
+// NodePools defines the nodes of every role
+type NodePools struct {
+	Sentry    NodePool      `json:"sentry,omitempty"`
+	Validator ValidatorPool `json:"validator,omitempty"`
+	Bootnode  NodePool      `json:"bootnode,omitempty"`
+	Archive   NodePool      `json:"archive,omitempty"`
+	// RpcNode is deployed next to the other kinds whenever replicas is greater than zero
+	RpcNode  NodePool     `json:"rpcNode,omitempty"`
+	Collator CollatorPool `json:"collator,omitempty"`
+}
+
+// NodePool defines the nodes of a role
+type NodePool struct {
+	Replicas   int32  `json:"replicas,omitempty"`
+	ClientName string `json:"clientName,omitempty"`
+	// NodeKeys are the private identities of the nodes, one for each replica (ordered by pod ordinal).
+	// Only the bootnodes support more than one key
+	NodeKeys []string `json:"nodeKeys,omitempty"`
+	// NodeKeySecretRef selects the key of a Secret holding the node key, not supported by the bootnodes
+	NodeKeySecretRef *corev1.SecretKeySelector `json:"nodeKeySecretRef,omitempty"`
+	// ReservedPeerID is the peer id of the validator for the sentries, and of the sentry for the validator
+	ReservedPeerID string `json:"reservedPeerID,omitempty"`
+	// ExternalReservedPeers are the multiaddresses of the validators for the sentries, and of the sentries for the
+	// validator, running in other clusters
+	ExternalReservedPeers []string `json:"externalReservedPeers,omitempty"`
+	// ReservedPeerAddr is the multiaddress (without peer ID) of the validator running outside of Kubernetes, reserved by
+	// the sentries with the peer ID reservedPeerID. Only supported by the sentries
+	ReservedPeerAddr string                      `json:"reservedPeerAddr,omitempty"`
+	Resources        corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Storage defines the persistent volumes of the chain data
+	Storage              v1alpha1.DataPersistenceSupport `json:"storage,omitempty"`
+	v1alpha1.NodeOptions `json:",inline"`
+}
+
+// ValidatorPool defines the validator, its replicas are ignored as a single validator is deployed
+type ValidatorPool struct {
+	NodePool `json:",inline"`
+	// KeyRotation defines when the operator rotates the session keys of the validator
+	KeyRotation v1alpha1.KeyRotation `json:"keyRotation,omitempty"`
+	// Staking bonds the stash of the validator and registers its rotated session keys on chain
+	Staking v1alpha1.Staking `json:"staking,omitempty"`
+	// SlashDetection watches the offences and the slashes of the stash of the validator
+	SlashDetection v1alpha1.SlashDetection `json:"slashDetection,omitempty"`
+	// EraExporter injects a sidecar in the validator pod, exporting the era points and the session progress of the stash
+	EraExporter v1alpha1.EraExporter `json:"eraExporter,omitempty"`
+	// KeystoreSecretRef is the Secret holding the keystore files of the session keys, mounted as the keystore of the validator
+	KeystoreSecretRef *corev1.LocalObjectReference `json:"keystoreSecretRef,omitempty"`
+	// RemoteSigner makes the validator sign with keys held by a remote keystore service instead of its filesystem
+	RemoteSigner *v1alpha1.RemoteSigner `json:"remoteSigner,omitempty"`
+	// HighAvailability runs a standby validator, activated by the operator when the active one is down
+	HighAvailability v1alpha1.HighAvailability `json:"highAvailability,omitempty"`
+}
+
+// CollatorPool defines the parachain collators, each one embedding a relay chain node
+type CollatorPool struct {
+	NodePool `json:",inline"`
+	// Image of the parachain client, tag included (e.g. "parity/polkadot-collator:latest")
+	Image string `json:"image,omitempty"`
+	// Binary is the executable of the parachain client inside the image, "polkadot-collator" if not set
+	Binary string `json:"binary,omitempty"`
+	// ParachainChainSpec is the chain of the parachain, passed as --chain (built-in name or path of the raw chainspec)
+	ParachainChainSpec string `json:"parachainChainSpec,omitempty"`
+	// RelayChainSpec is the chain of the embedded relay chain node, passed as --chain after the "--" separator (e.g. relay.json)
+	RelayChainSpec string `json:"relayChainSpec,omitempty"`
+	// RelayChainEndpoint is the optional RPC endpoint of a relay chain node, used instead of the embedded relay chain node
+	RelayChainEndpoint string `json:"relayChainEndpoint,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Polkadot is the Schema for the polkadots API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=polkadots,scope=Namespaced
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.kind`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.clientVersion`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type Polkadot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolkadotSpec `json:"spec,omitempty"`
+	// Status is shared with the v1alpha1 API
+	Status v1alpha1.PolkadotStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PolkadotList contains a list of Polkadot
+type PolkadotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Polkadot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Polkadot{}, &PolkadotList{})
+}
@@ -0,0 +1,225 @@
+// +build !ignore_autogenerated
+
+// Code generated by operator-sdk. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollatorPool) DeepCopyInto(out *CollatorPool) {
+	*out = *in
+	in.NodePool.DeepCopyInto(&out.NodePool)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollatorPool.
+func (in *CollatorPool) DeepCopy() *CollatorPool {
+	if in == nil {
+		return nil
+	}
+	out := new(CollatorPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.NodeKeys != nil {
+		in, out := &in.NodeKeys, &out.NodeKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeKeySecretRef != nil {
+		in, out := &in.NodeKeySecretRef, &out.NodeKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalReservedPeers != nil {
+		in, out := &in.ExternalReservedPeers, &out.ExternalReservedPeers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Storage.DeepCopyInto(&out.Storage)
+	in.NodeOptions.DeepCopyInto(&out.NodeOptions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePools) DeepCopyInto(out *NodePools) {
+	*out = *in
+	in.Sentry.DeepCopyInto(&out.Sentry)
+	in.Validator.DeepCopyInto(&out.Validator)
+	in.Bootnode.DeepCopyInto(&out.Bootnode)
+	in.Archive.DeepCopyInto(&out.Archive)
+	in.RpcNode.DeepCopyInto(&out.RpcNode)
+	in.Collator.DeepCopyInto(&out.Collator)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePools.
+func (in *NodePools) DeepCopy() *NodePools {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePools)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Polkadot) DeepCopyInto(out *Polkadot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Polkadot.
+func (in *Polkadot) DeepCopy() *Polkadot {
+	if in == nil {
+		return nil
+	}
+	out := new(Polkadot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Polkadot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotList) DeepCopyInto(out *PolkadotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Polkadot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotList.
+func (in *PolkadotList) DeepCopy() *PolkadotList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolkadotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolkadotSpec) DeepCopyInto(out *PolkadotSpec) {
+	*out = *in
+	in.NodePools.DeepCopyInto(&out.NodePools)
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Backup = in.Backup
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	in.NetworkHealth.DeepCopyInto(&out.NetworkHealth)
+	out.CleanupPolicy = in.CleanupPolicy
+	in.Upgrade.DeepCopyInto(&out.Upgrade)
+	in.Payouts.DeepCopyInto(&out.Payouts)
+	if in.BootnodesFrom != nil {
+		in, out := &in.BootnodesFrom, &out.BootnodesFrom
+		*out = make([]v1alpha1.PolkadotReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Ports = in.Ports
+	if in.ChainspecConfigMapRef != nil {
+		in, out := &in.ChainspecConfigMapRef, &out.ChainspecConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ChainspecBuilder.DeepCopyInto(&out.ChainspecBuilder)
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]v1alpha1.NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolkadotSpec.
+func (in *PolkadotSpec) DeepCopy() *PolkadotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolkadotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidatorPool) DeepCopyInto(out *ValidatorPool) {
+	*out = *in
+	in.NodePool.DeepCopyInto(&out.NodePool)
+	out.KeyRotation = in.KeyRotation
+	in.Staking.DeepCopyInto(&out.Staking)
+	out.SlashDetection = in.SlashDetection
+	in.EraExporter.DeepCopyInto(&out.EraExporter)
+	if in.KeystoreSecretRef != nil {
+		in, out := &in.KeystoreSecretRef, &out.KeystoreSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RemoteSigner != nil {
+		in, out := &in.RemoteSigner, &out.RemoteSigner
+		*out = new(v1alpha1.RemoteSigner)
+		(*in).DeepCopyInto(*out)
+	}
+	in.HighAvailability.DeepCopyInto(&out.HighAvailability)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidatorPool.
+func (in *ValidatorPool) DeepCopy() *ValidatorPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidatorPool)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handleAutoscaling generates a HorizontalPodAutoscaler, or a KEDA ScaledObject, for each autoscaled role, and a
+// VerticalPodAutoscaler for each vertically autoscaled role. They are removed if the autoscaling is disabled
+func (r *ReconcilerPolkadot) handleAutoscaling(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	for _, rr := range getRoleResources(CRInstance) {
+		autoscaling := rr.options.Autoscaling
+		isHorizontalPodAutoscaler := autoscaling.Enabled == true && autoscaling.Keda == nil
+		isScaledObject := autoscaling.Enabled == true && autoscaling.Keda != nil
+
+		var isForcedRequeue bool
+		var err error
+		if isHorizontalPodAutoscaler != true {
+			isForcedRequeue, err = r.handleAutoscalingDisabled(CRInstance, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handleHorizontalPodAutoscalerGeneric(CRInstance, newHorizontalPodAutoscaler(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+
+		if isScaledObject != true {
+			isForcedRequeue, err = r.handleUnstructuredDisabled(CRInstance, scaledObjectGVK, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handleUnstructuredGeneric(CRInstance, newScaledObject(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+
+		if rr.options.VerticalAutoscaling.Enabled != true {
+			isForcedRequeue, err = r.handleUnstructuredDisabled(CRInstance, verticalPodAutoscalerGVK, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handleUnstructuredGeneric(CRInstance, newVerticalPodAutoscaler(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleAutoscalingDisabled deletes the HorizontalPodAutoscaler previously generated for a role, the replicas of the role then apply again
+func (r *ReconcilerPolkadot) handleAutoscalingDisabled(CRInstance *polkadotv1alpha1.Polkadot, name string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("HorizontalPodAutoscaler.Name", name)
+
+	foundResource := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the HorizontalPodAutoscaler...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Autoscaling disabled, deleting the HorizontalPodAutoscaler...")
+	err = r.deleteResourceWithEvent(CRInstance, foundResource, "HorizontalPodAutoscaler")
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleUnstructuredDisabled deletes a resource, not part of the scheme of the operator (e.g. a KEDA ScaledObject), previously
+// generated for the CR. It is skipped when the CRD of the resource is not installed in the cluster
+func (r *ReconcilerPolkadot) handleUnstructuredDisabled(CRInstance *polkadotv1alpha1.Polkadot, gvk schema.GroupVersionKind, name string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues(gvk.Kind+".Name", name)
+
+	foundResource := &unstructured.Unstructured{}
+	foundResource.SetGroupVersionKind(gvk)
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if meta.IsNoMatchError(err) {
+		return handleSkip()
+	}
+	if err != nil {
+		logger.Error(err, "Error on fetch the "+gvk.Kind+"...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Disabled, deleting the " + gvk.Kind + "...")
+	err = r.deleteResourceWithEvent(CRInstance, foundResource, gvk.Kind)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleUnstructuredGeneric creates or updates a resource not part of the scheme of the operator, its CRD must be installed in the cluster
+func (r *ReconcilerPolkadot) handleUnstructuredGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *unstructured.Unstructured) (bool, error) {
+
+	kind := desiredResource.GetKind()
+	logger := getLogger(CRInstance).WithValues(kind+".Name", desiredResource.GetName())
+
+	toBeFoundResource := &unstructured.Unstructured{}
+	toBeFoundResource.SetGroupVersionKind(desiredResource.GroupVersionKind())
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.GetName(), Namespace: desiredResource.GetNamespace()})
+	if err != nil {
+		logger.Error(err, "Error on fetch the "+kind+", its CRD must be installed in the cluster...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info(kind + " not found...")
+		logger.Info("Creating a new " + kind + "...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new "+kind+"...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the %s %s: %v", kind, desiredResource.GetName(), err)
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new " + kind)
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the %s %s", kind, desiredResource.GetName())
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update "+kind+" Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the %s %s: %v", kind, desiredResource.GetName(), err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the " + kind + "...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the %s %s", kind, desiredResource.GetName())
+	}
+
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleHorizontalPodAutoscalerGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *autoscalingv2beta2.HorizontalPodAutoscaler) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("HorizontalPodAutoscaler.Name", desiredResource.Name)
+
+	toBeFoundResource := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the HorizontalPodAutoscaler...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("HorizontalPodAutoscaler not found...")
+		logger.Info("Creating a new HorizontalPodAutoscaler...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new HorizontalPodAutoscaler...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the HorizontalPodAutoscaler %s: %v", desiredResource.Name, err)
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new HorizontalPodAutoscaler")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the HorizontalPodAutoscaler %s", desiredResource.Name)
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update HorizontalPodAutoscaler Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the HorizontalPodAutoscaler %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the HorizontalPodAutoscaler...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the HorizontalPodAutoscaler %s", desiredResource.Name)
+	}
+
+	return NotForcedRequeue, nil
+}
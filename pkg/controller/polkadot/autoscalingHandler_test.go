@@ -0,0 +1,176 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestHandleAutoscaling(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := autoscalingv2beta2.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 2
+	polkadot.Spec.Sentry.Autoscaling = polkadotv1alpha1.Autoscaling{Enabled: true, MaxReplicas: 5}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleAutoscaling(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleAutoscaling: (%v, %v)", isRequeueForced, err)
+	}
+	found := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	isNotFound, err := reconciler.fetchResource(found, types.NamespacedName{Name: SentrySSName})
+	if err != nil || isNotFound {
+		t.Fatalf("handleAutoscaling: HorizontalPodAutoscaler not created (%v)", err)
+	}
+	if *found.Spec.MinReplicas != 2 || found.Spec.MaxReplicas != 5 || *found.Spec.Metrics[0].Resource.Target.AverageUtilization != defaultTargetCPU {
+		t.Fatalf("handleAutoscaling: unexpected HorizontalPodAutoscaler (%v)", found.Spec)
+	}
+
+	// the replicas set by the autoscaler are not reverted
+	current := newStatefulSetSentry(polkadot)
+	autoscaledReplicas := int32(4)
+	current.Spec.Replicas = &autoscaledReplicas
+	client = newFakeClient(scheme, polkadot, found, current)
+	reconciler = ReconcilerPolkadot{client: client, scheme: scheme}
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	statefulSet := &appsv1.StatefulSet{}
+	if _, err := reconciler.fetchResource(statefulSet, types.NamespacedName{Name: SentrySSName}); err != nil || *statefulSet.Spec.Replicas != autoscaledReplicas {
+		t.Fatalf("handleStatefulSetGeneric: autoscaled replicas reverted (%v)", *statefulSet.Spec.Replicas)
+	}
+
+	// the autoscaler is deleted once the autoscaling is disabled
+	polkadot.Spec.Sentry.Autoscaling.Enabled = false
+	isRequeueForced, err = reconciler.handleAutoscaling(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleAutoscaling: (%v, %v)", isRequeueForced, err)
+	}
+	isNotFound, err = reconciler.fetchResource(&autoscalingv2beta2.HorizontalPodAutoscaler{}, types.NamespacedName{Name: SentrySSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("handleAutoscaling: HorizontalPodAutoscaler not deleted (%v)", err)
+	}
+}
+
+func TestHandleAutoscalingKeda(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := autoscalingv2beta2.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	scheme.AddKnownTypeWithName(scaledObjectGVK, &unstructured.Unstructured{})
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(RpcNode)
+	polkadot.Spec.RpcNode.Replicas = 2
+	polkadot.Spec.RpcNode.Autoscaling = polkadotv1alpha1.Autoscaling{
+		Enabled:     true,
+		MaxReplicas: 10,
+		Keda:        &polkadotv1alpha1.Keda{ServerAddress: "http://prometheus.monitoring:9090"},
+	}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleAutoscaling(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleAutoscaling: (%v, %v)", isRequeueForced, err)
+	}
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(scaledObjectGVK)
+	isNotFound, err := reconciler.fetchResource(found, types.NamespacedName{Name: RpcNodeSSName})
+	if err != nil || isNotFound {
+		t.Fatalf("handleAutoscaling: ScaledObject not created (%v)", err)
+	}
+	triggers, _, _ := unstructured.NestedSlice(found.Object, "spec", "triggers")
+	maxReplicas, _, _ := unstructured.NestedInt64(found.Object, "spec", "maxReplicaCount")
+	if len(triggers) != 2 || maxReplicas != 10 {
+		t.Fatalf("handleAutoscaling: unexpected ScaledObject (%v)", found.Object["spec"])
+	}
+	// KEDA creates its own HorizontalPodAutoscaler
+	isNotFound, err = reconciler.fetchResource(&autoscalingv2beta2.HorizontalPodAutoscaler{}, types.NamespacedName{Name: RpcNodeSSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("handleAutoscaling: unexpected HorizontalPodAutoscaler (%v)", err)
+	}
+
+	polkadot.Spec.RpcNode.Autoscaling.Enabled = false
+	if _, err := reconciler.handleAutoscaling(polkadot); err != nil {
+		t.Fatalf("handleAutoscaling: (%v)", err)
+	}
+	isNotFound, err = reconciler.fetchResource(found, types.NamespacedName{Name: RpcNodeSSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("handleAutoscaling: ScaledObject not deleted (%v)", err)
+	}
+}
+
+func TestHandleVerticalAutoscaling(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := autoscalingv2beta2.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	scheme.AddKnownTypeWithName(verticalPodAutoscalerGVK, &unstructured.Unstructured{})
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.VerticalAutoscaling = polkadotv1alpha1.VerticalAutoscaling{
+		Enabled:    true,
+		MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+	}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleAutoscaling(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleAutoscaling: (%v, %v)", isRequeueForced, err)
+	}
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	isNotFound, err := reconciler.fetchResource(found, types.NamespacedName{Name: ValidatorSSName})
+	if err != nil || isNotFound {
+		t.Fatalf("handleAutoscaling: VerticalPodAutoscaler not created (%v)", err)
+	}
+	updateMode, _, _ := unstructured.NestedString(found.Object, "spec", "updatePolicy", "updateMode")
+	policies, _, _ := unstructured.NestedSlice(found.Object, "spec", "resourcePolicy", "containerPolicies")
+	maxMemory, _, _ := unstructured.NestedString(policies[0].(map[string]interface{}), "maxAllowed", "memory")
+	if updateMode != vpaUpdateModeOff || maxMemory != "16Gi" {
+		t.Fatalf("handleAutoscaling: unexpected VerticalPodAutoscaler (%v)", found.Object["spec"])
+	}
+
+	polkadot.Spec.Validator.VerticalAutoscaling.Enabled = false
+	if _, err := reconciler.handleAutoscaling(polkadot); err != nil {
+		t.Fatalf("handleAutoscaling: (%v)", err)
+	}
+	isNotFound, err = reconciler.fetchResource(found, types.NamespacedName{Name: ValidatorSSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("handleAutoscaling: VerticalPodAutoscaler not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// the KEDA ScaledObject and the VerticalPodAutoscaler are not part of the scheme of the operator
+var (
+	scaledObjectGVK          = schema.GroupVersionKind{Group: kedaAPIGroup, Version: "v1alpha1", Kind: "ScaledObject"}
+	verticalPodAutoscalerGVK = schema.GroupVersionKind{Group: vpaAPIGroup, Version: "v1", Kind: "VerticalPodAutoscaler"}
+)
+
+// newHorizontalPodAutoscaler returns the autoscaler of the StatefulSet of the role, named after the StatefulSet
+func newHorizontalPodAutoscaler(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	autoscaling := rr.options.Autoscaling
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	minReplicas := rr.replicas
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	metrics := autoscaling.Metrics
+	if len(metrics) == 0 {
+		targetCPU := int32(defaultTargetCPU)
+		if autoscaling.TargetCPUUtilizationPercentage != nil {
+			targetCPU = *autoscaling.TargetCPUUtilizationPercentage
+		}
+		metrics = []autoscalingv2beta2.MetricSpec{{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name:   corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &targetCPU},
+			},
+		}}
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rr.statefulSetName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       rr.statefulSetName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// newScaledObject returns the KEDA ScaledObject of the StatefulSet of the role, named after the StatefulSet.
+// KEDA creates and drives its own HorizontalPodAutoscaler from the Prometheus triggers
+func newScaledObject(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *unstructured.Unstructured {
+	autoscaling := rr.options.Autoscaling
+	keda := autoscaling.Keda
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	minReplicas := rr.replicas
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	kedaTriggers := keda.Triggers
+	if len(kedaTriggers) == 0 {
+		kedaTriggers = getDefaultKedaTriggers(CRInstance, rr)
+	}
+	triggers := []interface{}{}
+	for _, trigger := range kedaTriggers {
+		triggers = append(triggers, map[string]interface{}{
+			"type": "prometheus",
+			"metadata": map[string]interface{}{
+				"serverAddress": keda.ServerAddress,
+				"metricName":    trigger.MetricName,
+				"query":         trigger.Query,
+				"threshold":     trigger.Threshold,
+			},
+		})
+	}
+
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"name":       rr.statefulSetName,
+		},
+		"minReplicaCount": int64(minReplicas),
+		"maxReplicaCount": int64(autoscaling.MaxReplicas),
+		"triggers":        triggers,
+	}
+	if keda.PollingInterval != nil {
+		spec["pollingInterval"] = int64(*keda.PollingInterval)
+	}
+	if keda.CooldownPeriod != nil {
+		spec["cooldownPeriod"] = int64(*keda.CooldownPeriod)
+	}
+
+	scaledObject := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	scaledObject.SetGroupVersionKind(scaledObjectGVK)
+	scaledObject.SetName(rr.statefulSetName)
+	scaledObject.SetNamespace(CRInstance.Namespace)
+	scaledObject.SetLabels(getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels))
+	scaledObject.SetAnnotations(getCopy(CRInstance.Spec.Metadata.Annotations))
+	return scaledObject
+}
+
+// getDefaultKedaTriggers scales the role on the RPC requests rate and on the open WebSocket connections of its pods
+func getDefaultKedaTriggers(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) []polkadotv1alpha1.KedaTrigger {
+	selector := getPodsSelector(CRInstance.Namespace, []string{rr.statefulSetName})
+	return []polkadotv1alpha1.KedaTrigger{
+		{
+			MetricName: rr.role + "_rpc_requests",
+			Query:      fmt.Sprintf(`sum(rate(%s_rpc_calls_started{%s}[2m]))`, metricsPrefix, selector),
+			Threshold:  kedaRPCThreshold,
+		},
+		{
+			MetricName: rr.role + "_ws_connections",
+			Query:      fmt.Sprintf(`sum(%s_rpc_sessions_opened{%s}) - sum(%s_rpc_sessions_closed{%s})`, metricsPrefix, selector, metricsPrefix, selector),
+			Threshold:  kedaWSThreshold,
+		},
+	}
+}
+
+// newVerticalPodAutoscaler returns the VerticalPodAutoscaler of the StatefulSet of the role, named after the StatefulSet.
+// Its recommendations are limited to the client container, the sidecars keep their resources
+func newVerticalPodAutoscaler(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *unstructured.Unstructured {
+	verticalAutoscaling := rr.options.VerticalAutoscaling
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	updateMode := verticalAutoscaling.UpdateMode
+	if updateMode == "" {
+		updateMode = vpaUpdateModeOff
+	}
+	clientPolicy := map[string]interface{}{"containerName": serviceName}
+	if len(verticalAutoscaling.MinAllowed) > 0 {
+		clientPolicy["minAllowed"] = getUnstructuredResourceList(verticalAutoscaling.MinAllowed)
+	}
+	if len(verticalAutoscaling.MaxAllowed) > 0 {
+		clientPolicy["maxAllowed"] = getUnstructuredResourceList(verticalAutoscaling.MaxAllowed)
+	}
+
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"name":       rr.statefulSetName,
+		},
+		"updatePolicy": map[string]interface{}{"updateMode": updateMode},
+		"resourcePolicy": map[string]interface{}{
+			"containerPolicies": []interface{}{
+				clientPolicy,
+				map[string]interface{}{"containerName": "*", "mode": "Off"},
+			},
+		},
+	}
+
+	verticalPodAutoscaler := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	verticalPodAutoscaler.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	verticalPodAutoscaler.SetName(rr.statefulSetName)
+	verticalPodAutoscaler.SetNamespace(CRInstance.Namespace)
+	verticalPodAutoscaler.SetLabels(getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels))
+	verticalPodAutoscaler.SetAnnotations(getCopy(CRInstance.Spec.Metadata.Annotations))
+	return verticalPodAutoscaler
+}
+
+func getUnstructuredResourceList(resources corev1.ResourceList) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, quantity := range resources {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r *ReconcilerPolkadot) handleBackup(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if CRInstance.Spec.Backup.Enabled != true {
+		return r.handleBackupDisabled(CRInstance)
+	}
+
+	isForcedRequeue, err := r.handleServiceAccountGeneric(CRInstance, newBackupServiceAccount(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	isForcedRequeue, err = r.handleRoleGeneric(CRInstance, newBackupRole(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	isForcedRequeue, err = r.handleRoleBindingGeneric(CRInstance, newBackupRoleBinding(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	return r.handleCronJobGeneric(CRInstance, newBackupCronJob(CRInstance))
+}
+
+// handleBackupDisabled deletes the backup CronJob, the snapshots already taken are kept
+func (r *ReconcilerPolkadot) handleBackupDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("CronJob.Name", BackupName)
+
+	foundResource := &batchv1beta1.CronJob{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: BackupName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the CronJob...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Backup disabled, deleting the CronJob...")
+	err = r.deleteResource(foundResource)
+	if err != nil {
+		logger.Error(err, "Delete CronJob Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the CronJob")
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleRoleGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *rbacv1.Role) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("Role.Name", desiredResource.Name)
+
+	toBeFoundResource := &rbacv1.Role{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Role...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("Role not found...")
+		logger.Info("Creating a new Role...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new Role...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new Role")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Role Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the Role...")
+	}
+
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleRoleBindingGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *rbacv1.RoleBinding) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("RoleBinding.Name", desiredResource.Name)
+
+	toBeFoundResource := &rbacv1.RoleBinding{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the RoleBinding...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("RoleBinding not found...")
+		logger.Info("Creating a new RoleBinding...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new RoleBinding...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new RoleBinding")
+		return ForcedRequeue, nil
+	}
+
+	// the role reference of a RoleBinding is immutable and the subjects are fixed, nothing to update
+
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleCronJobGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *batchv1beta1.CronJob) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("CronJob.Name", desiredResource.Name)
+
+	toBeFoundResource := &batchv1beta1.CronJob{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the CronJob...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("CronJob not found...")
+		logger.Info("Creating a new CronJob...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new CronJob...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new CronJob")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update CronJob Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the CronJob...")
+	}
+
+	return NotForcedRequeue, nil
+}
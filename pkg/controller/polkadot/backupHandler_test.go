@@ -0,0 +1,74 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestHandleBackup(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := batchv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Polkadot object with metadata and spec.
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Backup.Enabled = true
+	polkadot.Spec.Backup.Schedule = "0 3 * * *"
+	polkadot.Spec.Backup.SnapshotClass = "csi-snapclass"
+	polkadot.Spec.Backup.Retention = 3
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the ServiceAccount, the Role, the RoleBinding and the CronJob are created one per reconcile
+	for i := 0; i < 4; i++ {
+		isRequeueForced, err := reconciler.handleBackup(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleBackup: (%v, %v)", isRequeueForced, err)
+		}
+	}
+	isRequeueForced, err := reconciler.handleBackup(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleBackup: (%v, %v)", isRequeueForced, err)
+	}
+
+	found := &batchv1beta1.CronJob{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: BackupName}, found)
+	if err != nil {
+		t.Fatalf("handleBackup: (%v)", err)
+	}
+	script := strings.Join(found.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command, " ")
+	if found.Spec.Schedule != "0 3 * * *" || !strings.Contains(script, `"volumeSnapshotClassName":"csi-snapclass"`) || !strings.Contains(script, "head -n -3") {
+		t.Fatalf("handleBackup: unexpected CronJob (%v, %v)", found.Spec.Schedule, script)
+	}
+
+	polkadot.Spec.Backup.Enabled = false
+	isRequeueForced, err = reconciler.handleBackup(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleBackup: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: BackupName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleBackup: CronJob not deleted (%v)", err)
+	}
+}
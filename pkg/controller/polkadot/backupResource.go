@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getBackupLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "backup"
+	return labels
+}
+
+func newBackupServiceAccount(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: getBackupObjectMeta(CRInstance),
+	}
+}
+
+// newBackupRole allows the backup job to snapshot the data volumes and to prune the old snapshots
+func newBackupRole(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: getBackupObjectMeta(CRInstance),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumeclaims"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{volumeSnapshotAPIGroup},
+				Resources: []string{"volumesnapshots"},
+				Verbs:     []string{"create", "delete", "get", "list"},
+			},
+		},
+	}
+}
+
+func newBackupRoleBinding(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: getBackupObjectMeta(CRInstance),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      BackupName,
+			Namespace: CRInstance.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     BackupName,
+		},
+	}
+}
+
+func newBackupCronJob(CRInstance *polkadotv1alpha1.Polkadot) *batchv1beta1.CronJob {
+	backup := CRInstance.Spec.Backup
+	schedule := backup.Schedule
+	if schedule == "" {
+		schedule = backupSchedule
+	}
+	image := backup.Image
+	if image == "" {
+		image = backupImage
+	}
+	retention := backup.Retention
+	if retention <= 0 {
+		retention = backupRetention
+	}
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: getBackupObjectMeta(CRInstance),
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: getCopyLabelsWithCustom(getBackupLabels(), CRInstance.Spec.Metadata.Labels),
+						},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: BackupName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							ImagePullSecrets:   CRInstance.Spec.ImagePullSecrets,
+							Containers: []corev1.Container{{
+								Name:    BackupName,
+								Image:   image,
+								Command: getBackupCommands(backup.SnapshotClass, retention),
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if isHardened(CRInstance) {
+		hardenPodTemplate(&cronJob.Spec.JobTemplate.Spec.Template)
+	}
+	return cronJob
+}
+
+// getBackupCommands snapshots every data volume of the nodes, keeping the last snapshots of each volume
+func getBackupCommands(snapshotClass string, retention int32) []string {
+	snapshotSpec := `"source":{"persistentVolumeClaimName":"%s"}`
+	if snapshotClass != "" {
+		snapshotSpec = fmt.Sprintf(`"volumeSnapshotClassName":%q,`, snapshotClass) + snapshotSpec
+	}
+	snapshot := `{"apiVersion":"` + volumeSnapshotAPIGroup + `/v1beta1","kind":"VolumeSnapshot",` +
+		`"metadata":{"name":"%s","labels":{"app":"polkadot","pvc":"%s"}},"spec":{` + snapshotSpec + `}}`
+
+	script := "set -e; " +
+		"for pvc in $(kubectl get pvc -l app=polkadot -o jsonpath='{.items[*].metadata.name}'); do " +
+		`snapshot="${pvc}-$(date +%Y%m%d%H%M%S)"; ` +
+		"printf '" + snapshot + `' "$snapshot" "$pvc" "$pvc" | kubectl create -f -; ` +
+		`kubectl get volumesnapshot -l app=polkadot,pvc="$pvc" --sort-by=.metadata.creationTimestamp -o name | ` +
+		fmt.Sprintf("head -n -%d | xargs -r kubectl delete; ", retention) +
+		"done"
+	return []string{"/bin/sh", "-c", script}
+}
+
+func getBackupObjectMeta(CRInstance *polkadotv1alpha1.Polkadot) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        BackupName,
+		Namespace:   CRInstance.Namespace,
+		Labels:      getCopyLabelsWithCustom(getBackupLabels(), CRInstance.Spec.Metadata.Labels),
+		Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+	}
+}
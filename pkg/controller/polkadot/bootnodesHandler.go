@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// handleBootnodes resolves the addresses of the bootnodes of the CRs referenced by bootnodesFrom, and records them in the
+// status, from which the --bootnodes argument of the nodes is generated. The references not found, or not deploying
+// bootnodes (Kind Bootnode or a bootnode pool), are skipped with a BootnodesNotFound warning event, as are the references
+// to the namespaces not watched by a namespace-scoped operator
+func (r *ReconcilerPolkadot) handleBootnodes(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	var bootnodes []string
+	for _, reference := range CRInstance.Spec.BootnodesFrom {
+		namespace := getPolkadotReferenceNamespace(CRInstance, reference)
+		if !config.IsNamespaceWatched(namespace) {
+			logger.V(warnLevel).Info("Bootnodes CR in a namespace not watched...", "Reference.Namespace", namespace, "Reference.Name", reference.Name)
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "BootnodesNotFound", "The Polkadot %s/%s is in a namespace not watched by the operator", namespace, reference.Name)
+			continue
+		}
+		bootnodeInstance := &polkadotv1alpha1.Polkadot{}
+		isNotFound, err := r.fetchResource(bootnodeInstance, types.NamespacedName{Name: reference.Name, Namespace: namespace})
+		if err != nil {
+			logger.Error(err, "Error on fetch the bootnodes CR...", "Reference.Namespace", namespace, "Reference.Name", reference.Name)
+			return NotForcedRequeue, err
+		}
+		applyNodePools(bootnodeInstance)
+		if isNotFound || !isRoleDeployed(bootnodeInstance, "bootnode") {
+			logger.V(warnLevel).Info("Bootnodes CR not found...", "Reference.Namespace", namespace, "Reference.Name", reference.Name)
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "BootnodesNotFound", "The Polkadot %s/%s is not a CR deploying bootnodes", namespace, reference.Name)
+			continue
+		}
+		bootnodes = append(bootnodes, getBootnodeAddresses(bootnodeInstance)...)
+	}
+	if len(bootnodes) == 0 {
+		bootnodes = nil
+	}
+	if reflect.DeepEqual(bootnodes, CRInstance.Status.Bootnodes) {
+		return handleSkip()
+	}
+
+	CRInstance.Status.Bootnodes = bootnodes
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Updated the bootnodes...", "Bootnodes", bootnodes)
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "BootnodesUpdated", "Updated the bootnodes, %d addresses resolved", len(bootnodes))
+	return NotForcedRequeue, nil
+}
+
+// getBootnodesReferrers requeues the CRs referencing a changed CR in their bootnodesFrom, so that their bootnodes follow it
+func getBootnodesReferrers(c client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		polkadots := &polkadotv1alpha1.PolkadotList{}
+		err := c.List(context.TODO(), polkadots)
+		if err != nil {
+			log.Error(err, "Error on listing the CRs referencing the bootnodes...")
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for i := range polkadots.Items {
+			referrer := &polkadots.Items[i]
+			for _, reference := range referrer.Spec.BootnodesFrom {
+				if reference.Name == object.Meta.GetName() && getPolkadotReferenceNamespace(referrer, reference) == object.Meta.GetNamespace() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: referrer.Name, Namespace: referrer.Namespace}})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,72 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"strings"
+	"testing"
+)
+
+func TestHandleBootnodes(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	bootnodes := getFakePolkadot()
+	bootnodes.Name = "bootnodes"
+	bootnodes.Namespace = "network"
+	bootnodes.Spec.Kind = string(Bootnode)
+	bootnodes.Spec.Bootnode.Replicas = 2
+	bootnodes.Spec.Bootnode.NodeKeys = []string{fakeNodeKey, "invalid"}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.BootnodesFrom = []polkadotv1alpha1.PolkadotReference{{Name: "bootnodes", Namespace: "network"}, {Name: "not-found"}}
+
+	client := newFakeClient(scheme, polkadot, bootnodes)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the bootnode with an invalid node key and the CR not found are skipped
+	isRequeueForced, err := reconciler.handleBootnodes(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleBootnodes: (%v, %v)", isRequeueForced, err)
+	}
+	expected := []string{"/dns4/" + ServiceBootnodeName + "-0.network.svc/tcp/-1/p2p/" + fakePeerID}
+	if !reflect.DeepEqual(polkadot.Status.Bootnodes, expected) {
+		t.Fatalf("handleBootnodes: unexpected bootnodes (%v)", polkadot.Status.Bootnodes)
+	}
+	commands := strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(commands, "--bootnodes "+expected[0]) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", commands)
+	}
+
+	// the CRs referencing a changed bootnodes CR are requeued
+	requests := getBootnodesReferrers(client)(handler.MapObject{Meta: &metav1.ObjectMeta{Name: "bootnodes", Namespace: "network"}})
+	if len(requests) != 1 || requests[0].Name != polkadot.Name {
+		t.Fatalf("getBootnodesReferrers: unexpected requests (%v)", requests)
+	}
+
+	// the CRs of the namespaces not watched by a namespace-scoped operator are skipped
+	config.WatchNamespaces = []string{polkadot.Namespace}
+	defer func() { config.WatchNamespaces = nil }()
+	if _, err := reconciler.handleBootnodes(polkadot); err != nil || polkadot.Status.Bootnodes != nil {
+		t.Fatalf("handleBootnodes: (%v, %v)", polkadot.Status.Bootnodes, err)
+	}
+
+	// the bootnodes are cleared once no longer referenced
+	polkadot.Spec.BootnodesFrom = nil
+	if _, err := reconciler.handleBootnodes(polkadot); err != nil || polkadot.Status.Bootnodes != nil {
+		t.Fatalf("handleBootnodes: (%v, %v)", polkadot.Status.Bootnodes, err)
+	}
+}
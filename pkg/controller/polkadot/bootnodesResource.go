@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"encoding/hex"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"strings"
+)
+
+// checkBootnodeNodeKeys returns an error if a replica of the bootnodes has no node key, or if a node key is not a hex
+// encoded key of 32 bytes, as expected by the --node-key argument of the client
+func checkBootnodeNodeKeys(CRInstance *polkadotv1alpha1.Polkadot) error {
+	bootnode := CRInstance.Spec.Bootnode
+	if int32(len(bootnode.NodeKeys)) < bootnode.Replicas {
+		return fmt.Errorf("%d node keys for %d replicas", len(bootnode.NodeKeys), bootnode.Replicas)
+	}
+	for i, nodeKey := range bootnode.NodeKeys {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(nodeKey, "0x"))
+		if err != nil || len(decoded) != 32 {
+			return fmt.Errorf("invalid node key %d, a hex encoded key of 32 bytes is expected", i)
+		}
+	}
+	return nil
+}
+
+// getBootnodeAddresses returns the addresses of the bootnodes of a CR of Kind Bootnode, each one reached through its
+// dedicated Service. The bootnodes with an invalid node key are left out
+func getBootnodeAddresses(bootnodeInstance *polkadotv1alpha1.Polkadot) []string {
+	addresses := []string{}
+	for ordinal := int32(0); ordinal < bootnodeInstance.Spec.Bootnode.Replicas && int(ordinal) < len(bootnodeInstance.Spec.Bootnode.NodeKeys); ordinal++ {
+		peerID, err := getPeerID([]byte(bootnodeInstance.Spec.Bootnode.NodeKeys[ordinal]))
+		if err != nil {
+			log.V(warnLevel).Info("Not able to get the peer ID of the bootnode...", "Polkadot.Name", bootnodeInstance.Name, "Ordinal", ordinal, "error", err.Error())
+			continue
+		}
+		addresses = append(addresses, fmt.Sprintf("/dns4/%s-%d.%s.svc/tcp/%d/p2p/%s", ServiceBootnodeName, ordinal, bootnodeInstance.Namespace, getPorts(bootnodeInstance).P2P, peerID))
+	}
+	return addresses
+}
+
+// getBootnodesCommands returns the --bootnodes argument of the bootnodes resolved from the bootnodesFrom CRs, if any
+func getBootnodesCommands(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	if len(CRInstance.Status.Bootnodes) == 0 {
+		return []string{}
+	}
+	return append([]string{"--bootnodes"}, CRInstance.Status.Bootnodes...)
+}
+
+func getPolkadotReferenceNamespace(CRInstance *polkadotv1alpha1.Polkadot, reference polkadotv1alpha1.PolkadotReference) string {
+	if reference.Namespace != "" {
+		return reference.Namespace
+	}
+	return CRInstance.Namespace
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// getChain returns the chain passed as --chain: the name of a well-known chain, or the path of the mounted chainspec of a
+// custom chain. Empty if the nodes run the default chain of the client
+func getChain(CRInstance *polkadotv1alpha1.Polkadot) string {
+	chain := CRInstance.Spec.Chain
+	if chain == customChain || (chain == "" && getChainspecConfigMapRef(CRInstance) != nil) {
+		return getChainspecPath()
+	}
+	return chain
+}
+
+func getChainCommands(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	chain := getChain(CRInstance)
+	if chain == "" {
+		return []string{}
+	}
+	return []string{"--chain", chain}
+}
+
+// getChainPreset returns the preset of the chain of the CR, nil for the custom chains and the default one of the client
+func getChainPreset(CRInstance *polkadotv1alpha1.Polkadot) *config.ChainPreset {
+	preset, isFound := config.ChainPresets[CRInstance.Spec.Chain]
+	if !isFound {
+		return nil
+	}
+	return &preset
+}
+
+// getChainResources returns the resources of a role, with the requests of the preset of the chain if none is set
+func getChainResources(CRInstance *polkadotv1alpha1.Polkadot, resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	preset := getChainPreset(CRInstance)
+	if preset == nil || len(resources.Requests) != 0 {
+		return resources
+	}
+	resources = *resources.DeepCopy()
+	resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(preset.CPURequest),
+		corev1.ResourceMemory: resource.MustParse(preset.MemoryRequest),
+	}
+	return resources
+}
+
+// getChainspecConfigMapRef returns the ConfigMap key of the chainspec of the custom chain, the one of the chainspec builder if enabled
+func getChainspecConfigMapRef(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ConfigMapKeySelector {
+	if CRInstance.Spec.ChainspecBuilder.Enabled {
+		return &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: ChainspecName}, Key: chainspecFileName}
+	}
+	return CRInstance.Spec.ChainspecConfigMapRef
+}
+
+func getChainspecPath() string {
+	return chainspecMountPath + "/" + chainspecFileName
+}
+
+// getChainspecVolume projects the chainspec of the ConfigMap in the chainspec.json file
+func getChainspecVolume(chainspecConfigMapRef *corev1.ConfigMapKeySelector) corev1.Volume {
+	return corev1.Volume{
+		Name: chainspecVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: chainspecConfigMapRef.LocalObjectReference,
+				Items:                []corev1.KeyToPath{{Key: chainspecConfigMapRef.Key, Path: chainspecFileName}},
+			},
+		},
+	}
+}
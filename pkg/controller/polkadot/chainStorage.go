@@ -0,0 +1,205 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// The storage of the runtime is read with the state_getStorage RPC method, at the keys derived from the names of the pallet
+// and of the storage item (twox128) and from the keys of the maps (twox64concat), as done by Substrate
+
+// decodeSS58Address returns the 32 bytes account ID of a SS58 address, without checking the checksum
+func decodeSS58Address(address string) ([]byte, error) {
+	value := big.NewInt(0)
+	for _, c := range address {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid SS58 address %s", address)
+		}
+		value.Mul(value, big.NewInt(58))
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	decoded := value.Bytes()
+	// the leading zeros of the address (e.g. the prefix of Polkadot) are encoded as leading ones
+	for i := 0; i < len(address) && address[i] == base58Alphabet[0]; i++ {
+		decoded = append([]byte{0}, decoded...)
+	}
+	prefixLength := 1
+	if len(decoded) > 0 && decoded[0] >= 64 {
+		prefixLength = 2
+	}
+	// the address is the prefix, the account ID and a 2 bytes checksum
+	if len(decoded) != prefixLength+32+2 {
+		return nil, fmt.Errorf("invalid SS58 address %s", address)
+	}
+	return decoded[prefixLength : prefixLength+32], nil
+}
+
+// getStorageKey returns the hex key of a storage item of a pallet, for the given twox64concat hashed map keys
+func getStorageKey(pallet string, item string, mapKeys ...[]byte) string {
+	key := append(twox128([]byte(pallet)), twox128([]byte(item))...)
+	for _, mapKey := range mapKeys {
+		key = append(key, twox64Concat(mapKey)...)
+	}
+	return "0x" + hex.EncodeToString(key)
+}
+
+// getStorage reads a storage item of the runtime through the RPC endpoint of a node, nil if the item is not set
+func getStorage(endpoint string, key string) ([]byte, error) {
+	var value *string
+	err := callNodeRPC(endpoint, "state_getStorage", &value, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimPrefix(*value, "0x"))
+}
+
+// getStorageKeys returns the keys of the storage items starting with a prefix, at most count of them
+func getStorageKeys(endpoint string, prefix string, count int) ([]string, error) {
+	keys := []string{}
+	err := callNodeRPC(endpoint, "state_getKeysPaged", &keys, prefix, count)
+	return keys, err
+}
+
+// decodeUint32 returns the SCALE encoded little endian uint32 at an offset of a storage value
+func decodeUint32(value []byte, offset int) (uint32, error) {
+	if len(value) < offset+4 {
+		return 0, fmt.Errorf("storage value too short (%d bytes)", len(value))
+	}
+	return binary.LittleEndian.Uint32(value[offset:]), nil
+}
+
+// decodeCompact returns a SCALE compact encoded length at an offset of a storage value, and the offset following it
+func decodeCompact(value []byte, offset int) (int, int, error) {
+	if len(value) <= offset {
+		return 0, 0, fmt.Errorf("storage value too short (%d bytes)", len(value))
+	}
+	switch value[offset] & 3 {
+	case 0:
+		return int(value[offset] >> 2), offset + 1, nil
+	case 1:
+		if len(value) < offset+2 {
+			return 0, 0, fmt.Errorf("storage value too short (%d bytes)", len(value))
+		}
+		return int(binary.LittleEndian.Uint16(value[offset:]) >> 2), offset + 2, nil
+	case 2:
+		length, err := decodeUint32(value, offset)
+		return int(length >> 2), offset + 4, err
+	}
+	return 0, 0, fmt.Errorf("compact length too big at offset %d", offset)
+}
+
+// countUnappliedSlashes returns the number of slashes of a validator in a SCALE encoded Vec<UnappliedSlash>, each slash
+// being the validator account, its own slashed balance, the slashed nominators, the reporters and the payout
+func countUnappliedSlashes(value []byte, accountID []byte) (int32, error) {
+	const accountLength, balanceLength = 32, 16
+	count := int32(0)
+	slashes, offset, err := decodeCompact(value, 0)
+	for i := 0; i < slashes && err == nil; i++ {
+		if len(value) < offset+accountLength+balanceLength {
+			return 0, fmt.Errorf("storage value too short (%d bytes)", len(value))
+		}
+		if bytes.Equal(value[offset:offset+accountLength], accountID) {
+			count++
+		}
+		offset += accountLength + balanceLength
+		var others, reporters int
+		others, offset, err = decodeCompact(value, offset)
+		if err != nil {
+			break
+		}
+		offset += others * (accountLength + balanceLength)
+		reporters, offset, err = decodeCompact(value, offset)
+		offset += reporters*accountLength + balanceLength
+	}
+	if err == nil && len(value) < offset {
+		err = fmt.Errorf("storage value too short (%d bytes)", len(value))
+	}
+	return count, err
+}
+
+func twox128(data []byte) []byte {
+	key := make([]byte, 16)
+	binary.LittleEndian.PutUint64(key, xxhash64(data, 0))
+	binary.LittleEndian.PutUint64(key[8:], xxhash64(data, 1))
+	return key
+}
+
+func twox64Concat(data []byte) []byte {
+	key := make([]byte, 8, 8+len(data))
+	binary.LittleEndian.PutUint64(key, xxhash64(data, 0))
+	return append(key, data...)
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxhash64 is the seeded 64 bits xxHash, the hash of the twox hashers of Substrate
+func xxhash64(data []byte, seed uint64) uint64 {
+	length := uint64(len(data))
+	var h uint64
+	if len(data) >= 32 {
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for ; len(data) >= 32; data = data[32:] {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(data))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(data[8:]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(data[16:]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(data[24:]))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+	h += length
+
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		data = data[4:]
+	}
+	for ; len(data) > 0; data = data[1:] {
+		h ^= uint64(data[0]) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc uint64, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc uint64, value uint64) uint64 {
+	acc ^= xxRound(0, value)
+	return acc*xxPrime1 + xxPrime4
+}
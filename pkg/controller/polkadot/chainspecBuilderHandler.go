@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// handleChainspecBuilder runs the Job building the chainspec of the private network of the CR. The pods of the nodes wait
+// for the chainspec ConfigMap to be created by the Job before they start
+func (r *ReconcilerPolkadot) handleChainspecBuilder(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if !CRInstance.Spec.ChainspecBuilder.Enabled {
+		return r.handleChainspecBuilderDisabled(CRInstance)
+	}
+
+	isForcedRequeue, err := r.handleServiceAccountGeneric(CRInstance, newChainspecBuilderServiceAccount(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	isForcedRequeue, err = r.handleRoleGeneric(CRInstance, newChainspecBuilderRole(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	isForcedRequeue, err = r.handleRoleBindingGeneric(CRInstance, newChainspecBuilderRoleBinding(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	desiredResource, err := newChainspecBuilderJob(CRInstance)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return r.handleChainspecBuilderJob(CRInstance, desiredResource)
+}
+
+// handleChainspecBuilderJob creates the Job, and replaces it once its pod template changes (e.g. a new genesis), as the
+// template of a Job is immutable. A completed Job is kept, so that the chainspec is only built again on a change
+func (r *ReconcilerPolkadot) handleChainspecBuilderJob(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *batchv1.Job) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Job.Name", desiredResource.Name)
+
+	err := setJobHash(desiredResource)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+
+	foundResource := &batchv1.Job{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Job...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("Job not found...")
+		logger.Info("Creating a new Job...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new Job...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new Job")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "ChainspecBuildStarted", "Building the chainspec of the private network in the ConfigMap %s", ChainspecName)
+		return ForcedRequeue, nil
+	}
+	if foundResource.Annotations[templateHashAnnotation] == desiredResource.Annotations[templateHashAnnotation] {
+		return handleSkip()
+	}
+	if !metav1.IsControlledBy(foundResource, CRInstance) {
+		logger.V(warnLevel).Info("Job not controlled by the CR, not able to replace it...")
+		return handleSkip()
+	}
+
+	logger.Info("Genesis changed, replacing the Job...")
+	err = r.deleteResource(foundResource, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		logger.Error(err, "Delete Job Error...")
+		return NotForcedRequeue, err
+	}
+	return ForcedRequeue, nil
+}
+
+// handleChainspecBuilderDisabled deletes the Job, the chainspec already built is kept
+func (r *ReconcilerPolkadot) handleChainspecBuilderDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Job.Name", ChainspecBuilderName)
+
+	foundResource := &batchv1.Job{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: ChainspecBuilderName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Job...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Chainspec builder disabled, deleting the Job...")
+	err = r.deleteResource(foundResource, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		logger.Error(err, "Delete Job Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the Job")
+	return NotForcedRequeue, nil
+}
+
+// setJobHash annotates the Job with the hash of its pod template, so that a change of the template is detected
+// comparing the annotation of the found Job
+func setJobHash(job *batchv1.Job) error {
+	data, err := json.Marshal(job.Spec.Template)
+	if err != nil {
+		return err
+	}
+	annotations := getCopy(job.Annotations)
+	annotations[templateHashAnnotation] = fmt.Sprintf("%x", sha256.Sum256(data))
+	job.Annotations = annotations
+	return nil
+}
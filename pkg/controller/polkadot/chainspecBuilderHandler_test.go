@@ -0,0 +1,104 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestHandleChainspecBuilder(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.ChainspecBuilder = polkadotv1alpha1.ChainspecBuilder{
+		Enabled:   true,
+		BaseChain: "dev",
+		Balances:  []polkadotv1alpha1.GenesisBalance{{Address: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", Amount: "1152921504606846976"}},
+		SudoKey:   "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+	}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the ServiceAccount, the Role, the RoleBinding and the Job are created one per reconcile
+	for i := 0; i < 4; i++ {
+		isRequeueForced, err := reconciler.handleChainspecBuilder(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleChainspecBuilder: (%v, %v)", isRequeueForced, err)
+		}
+	}
+	isRequeueForced, err := reconciler.handleChainspecBuilder(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleChainspecBuilder: (%v, %v)", isRequeueForced, err)
+	}
+
+	found := &batchv1.Job{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ChainspecBuilderName}, found)
+	if err != nil {
+		t.Fatalf("handleChainspecBuilder: (%v)", err)
+	}
+	podSpec := found.Spec.Template.Spec
+	if command := strings.Join(podSpec.InitContainers[0].Command, " "); !strings.Contains(command, "'build-spec' '--chain' 'dev'") {
+		t.Fatalf("handleChainspecBuilder: unexpected build-spec command (%v)", command)
+	}
+	// the balances are not rounded by the JSON encoding
+	genesis := podSpec.InitContainers[1].Env[0].Value
+	if genesis != `{"balances":{"balances":[["5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",1152921504606846976]]},"sudo":{"key":"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}}` {
+		t.Fatalf("handleChainspecBuilder: unexpected genesis (%v)", genesis)
+	}
+
+	// a new genesis replaces the Job
+	polkadot.Spec.ChainspecBuilder.SudoKey = "5FHneW46xGXgs5mUiveU4sbTyGBzmstUspZC92UhjJM694ty"
+	isRequeueForced, err = reconciler.handleChainspecBuilder(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleChainspecBuilder: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ChainspecBuilderName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleChainspecBuilder: Job not replaced (%v)", err)
+	}
+	isRequeueForced, err = reconciler.handleChainspecBuilder(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleChainspecBuilder: (%v, %v)", isRequeueForced, err)
+	}
+
+	// the nodes run the built chainspec
+	podSpec = newStatefulSetSentry(polkadot).Spec.Template.Spec
+	if command := strings.Join(podSpec.Containers[0].Command, " "); !strings.Contains(command, "--chain /config/chainspec.json") {
+		t.Fatalf("newStatefulSetSentry: unexpected command (%v)", command)
+	}
+	if volume := podSpec.Volumes[len(podSpec.Volumes)-1]; volume.ConfigMap == nil || volume.ConfigMap.Name != ChainspecName {
+		t.Fatalf("newStatefulSetSentry: unexpected chainspec volume (%v)", volume)
+	}
+
+	polkadot.Spec.ChainspecBuilder.Enabled = false
+	isRequeueForced, err = reconciler.handleChainspecBuilder(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleChainspecBuilder: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ChainspecBuilderName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleChainspecBuilder: Job not deleted (%v)", err)
+	}
+}
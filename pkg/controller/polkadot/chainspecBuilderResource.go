@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"encoding/json"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getChainspecBuilderLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "chainspec-builder"
+	return labels
+}
+
+func newChainspecBuilderServiceAccount(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: getChainspecBuilderObjectMeta(CRInstance),
+	}
+}
+
+// newChainspecBuilderRole allows the chainspec builder job to store the built chainspec in its ConfigMap
+func newChainspecBuilderRole(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: getChainspecBuilderObjectMeta(CRInstance),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"configmaps"},
+				ResourceNames: []string{ChainspecName},
+				Verbs:         []string{"get", "update"},
+			},
+		},
+	}
+}
+
+func newChainspecBuilderRoleBinding(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: getChainspecBuilderObjectMeta(CRInstance),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ChainspecBuilderName,
+			Namespace: CRInstance.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     ChainspecBuilderName,
+		},
+	}
+}
+
+// newChainspecBuilderJob builds the raw chainspec of the private network: the client exports the plain chainspec of the
+// base chain, its genesis is patched with the one of the CR, the client turns it into the raw chainspec, which is finally
+// stored in the chainspec ConfigMap, owned by the CR
+func newChainspecBuilderJob(CRInstance *polkadotv1alpha1.Polkadot) (*batchv1.Job, error) {
+	builder := CRInstance.Spec.ChainspecBuilder
+	baseChain := builder.BaseChain
+	if baseChain == "" {
+		baseChain = chainspecBaseChain
+	}
+	image := builder.Image
+	if image == "" {
+		image = chainspecBuilderImage
+	}
+	clientImage := getImage(Parameters{version: CRInstance.Spec.ClientVersion})
+
+	genesis, err := getGenesisPatch(builder)
+	if err != nil {
+		return nil, err
+	}
+	isController := true
+	owner, err := json.Marshal(metav1.OwnerReference{
+		APIVersion: polkadotv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Polkadot",
+		Name:       CRInstance.Name,
+		UID:        CRInstance.UID,
+		Controller: &isController,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plainChainspec := buildMountPath + "/plain.json"
+	customChainspec := buildMountPath + "/custom.json"
+	rawChainspec := buildMountPath + "/" + chainspecFileName
+	configMap := buildMountPath + "/configmap.json"
+	volumeMounts := []corev1.VolumeMount{{Name: buildVolumeName, MountPath: buildMountPath}}
+
+	job := &batchv1.Job{
+		ObjectMeta: getChainspecBuilderObjectMeta(CRInstance),
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: getCopyLabelsWithCustom(getChainspecBuilderLabels(), CRInstance.Spec.Metadata.Labels),
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ChainspecBuilderName,
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					ImagePullSecrets:   CRInstance.Spec.ImagePullSecrets,
+					InitContainers: []corev1.Container{
+						{
+							Name:         "build-spec",
+							Image:        clientImage,
+							Command:      []string{"/bin/sh", "-c", getShellQuoted([]string{"polkadot", "build-spec", "--chain", baseChain, "--disable-default-bootnode"}) + " > " + plainChainspec},
+							VolumeMounts: volumeMounts,
+						},
+						{
+							Name:         "genesis",
+							Image:        image,
+							Command:      []string{"/bin/sh", "-c", `jq --argjson genesis "$GENESIS" '.genesis.runtime *= $genesis' ` + plainChainspec + " > " + customChainspec},
+							Env:          []corev1.EnvVar{{Name: "GENESIS", Value: genesis}},
+							VolumeMounts: volumeMounts,
+						},
+						{
+							Name:         "build-raw-spec",
+							Image:        clientImage,
+							Command:      []string{"/bin/sh", "-c", getShellQuoted([]string{"polkadot", "build-spec", "--chain", customChainspec, "--raw", "--disable-default-bootnode"}) + " > " + rawChainspec},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Containers: []corev1.Container{{
+						Name:  ChainspecBuilderName,
+						Image: image,
+						Command: []string{"/bin/sh", "-c", "set -e; " +
+							`jq -n --rawfile chainspec ` + rawChainspec + ` --argjson owner "$OWNER" ` +
+							`'{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + ChainspecName + `","ownerReferences":[$owner]},"data":{"` + chainspecFileName + `":$chainspec}}' > ` + configMap + "; " +
+							"kubectl replace -f " + configMap + " || kubectl create -f " + configMap},
+						Env:          []corev1.EnvVar{{Name: "OWNER", Value: string(owner)}},
+						VolumeMounts: volumeMounts,
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         buildVolumeName,
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}
+	if isHardened(CRInstance) {
+		hardenPodTemplate(&job.Spec.Template)
+	}
+	return job, nil
+}
+
+// getGenesisPatch returns the JSON merged into the runtime genesis of the base chain, with the pallets of the substrate node
+// template. The amounts are kept as JSON numbers of arbitrary precision
+func getGenesisPatch(builder polkadotv1alpha1.ChainspecBuilder) (string, error) {
+	genesis := map[string]interface{}{}
+	if len(builder.Authorities) > 0 {
+		aura := []string{}
+		grandpa := [][]interface{}{}
+		for _, authority := range builder.Authorities {
+			aura = append(aura, authority.Aura)
+			grandpa = append(grandpa, []interface{}{authority.Grandpa, 1})
+		}
+		genesis["aura"] = map[string]interface{}{"authorities": aura}
+		genesis["grandpa"] = map[string]interface{}{"authorities": grandpa}
+	}
+	if len(builder.Balances) > 0 {
+		balances := [][]interface{}{}
+		for _, balance := range builder.Balances {
+			balances = append(balances, []interface{}{balance.Address, json.Number(balance.Amount)})
+		}
+		genesis["balances"] = map[string]interface{}{"balances": balances}
+	}
+	if builder.SudoKey != "" {
+		genesis["sudo"] = map[string]interface{}{"key": builder.SudoKey}
+	}
+	data, err := json.Marshal(genesis)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func getChainspecBuilderObjectMeta(CRInstance *polkadotv1alpha1.Polkadot) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        ChainspecBuilderName,
+		Namespace:   CRInstance.Namespace,
+		Labels:      getCopyLabelsWithCustom(getChainspecBuilderLabels(), CRInstance.Spec.Metadata.Labels),
+		Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+	}
+}
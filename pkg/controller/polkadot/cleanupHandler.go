@@ -0,0 +1,162 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// handleFinalizer adds the cleanup finalizer to the CustomResource, so that it is not removed before the operator
+// deletes the resources not garbage collected by the ownership
+func (r *ReconcilerPolkadot) handleFinalizer(CRInstance *polkadotv1alpha1.Polkadot) error {
+	if hasFinalizer(CRInstance, cleanupFinalizer) {
+		return nil
+	}
+	logger := getLogger(CRInstance)
+
+	logger.Info("Adding the cleanup finalizer to the Custom Resource...")
+	controllerutil.AddFinalizer(CRInstance, cleanupFinalizer)
+	err := r.updateResource(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Custom Resource Error...")
+		return err
+	}
+	return nil
+}
+
+// handleCleanup deletes the resources selected by the cleanup policy, then removes the finalizer releasing the CustomResource
+func (r *ReconcilerPolkadot) handleCleanup(CRInstance *polkadotv1alpha1.Polkadot) error {
+	if !hasFinalizer(CRInstance, cleanupFinalizer) {
+		return nil
+	}
+	logger := getLogger(CRInstance)
+
+	policy := CRInstance.Spec.CleanupPolicy
+	if policy.DeleteSnapshots || policy.DeleteVolumes {
+		isVolumeClaimOwned, err := r.getVolumeClaimMatcher(CRInstance)
+		if err != nil {
+			return err
+		}
+		if policy.DeleteSnapshots {
+			err := r.deleteVolumeSnapshots(CRInstance, isVolumeClaimOwned)
+			if err != nil {
+				return err
+			}
+		}
+		if policy.DeleteVolumes {
+			err := r.deleteVolumes(CRInstance, isVolumeClaimOwned)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info("Removing the cleanup finalizer from the Custom Resource...")
+	controllerutil.RemoveFinalizer(CRInstance, cleanupFinalizer)
+	err := r.updateResource(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Custom Resource Error...")
+		return err
+	}
+	return nil
+}
+
+// getVolumeClaimMatcher tells if a PersistentVolumeClaim was created by a StatefulSet controlled by the CR, its name being
+// "<claim>-<StatefulSet>-<ordinal>", so that the volumes of the other CRs of the namespace are left alone
+func (r *ReconcilerPolkadot) getVolumeClaimMatcher(CRInstance *polkadotv1alpha1.Polkadot) (func(string) bool, error) {
+	logger := getLogger(CRInstance)
+
+	statefulSets := &appsv1.StatefulSetList{}
+	err := r.client.List(context.TODO(), statefulSets, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(getAppLabels()))
+	if err != nil {
+		logger.Error(err, "Error on fetch the StatefulSets...")
+		return nil, err
+	}
+	patterns := []*regexp.Regexp{}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		if !metav1.IsControlledBy(statefulSet, CRInstance) {
+			continue
+		}
+		for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+			patterns = append(patterns, regexp.MustCompile("^"+regexp.QuoteMeta(claim.Name+"-"+statefulSet.Name)+"-[0-9]+$"))
+		}
+	}
+	return func(name string) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// deleteVolumes deletes the PersistentVolumeClaims created by the StatefulSets of the nodes of the CR
+func (r *ReconcilerPolkadot) deleteVolumes(CRInstance *polkadotv1alpha1.Polkadot, isVolumeClaimOwned func(string) bool) error {
+	logger := getLogger(CRInstance)
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := r.client.List(context.TODO(), claims, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(getAppLabels()))
+	if err != nil {
+		logger.Error(err, "Error on fetch the PersistentVolumeClaims...")
+		return err
+	}
+	for i := range claims.Items {
+		if !isVolumeClaimOwned(claims.Items[i].Name) {
+			continue
+		}
+		err = r.deleteResourceWithEvent(CRInstance, &claims.Items[i], "PersistentVolumeClaim")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteVolumeSnapshots deletes the VolumeSnapshots taken by the backup job of the volumes of the CR, labelled with the name
+// of their PersistentVolumeClaim. Skipped when the snapshot CRDs are not installed
+func (r *ReconcilerPolkadot) deleteVolumeSnapshots(CRInstance *polkadotv1alpha1.Polkadot, isVolumeClaimOwned func(string) bool) error {
+	logger := getLogger(CRInstance)
+
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(schema.GroupVersionKind{Group: volumeSnapshotAPIGroup, Version: "v1beta1", Kind: "VolumeSnapshotList"})
+	err := r.client.List(context.TODO(), snapshots, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(getAppLabels()))
+	if meta.IsNoMatchError(err) {
+		logger.Info("VolumeSnapshots not supported by the cluster, skipping their cleanup...")
+		return nil
+	}
+	if err != nil {
+		logger.Error(err, "Error on fetch the VolumeSnapshots...")
+		return err
+	}
+	for i := range snapshots.Items {
+		if !isVolumeClaimOwned(snapshots.Items[i].GetLabels()["pvc"]) {
+			continue
+		}
+		err = r.deleteResourceWithEvent(CRInstance, &snapshots.Items[i], "VolumeSnapshot")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasFinalizer(CRInstance *polkadotv1alpha1.Polkadot, finalizer string) bool {
+	for _, f := range CRInstance.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
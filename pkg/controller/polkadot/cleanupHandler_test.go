@@ -0,0 +1,214 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"testing"
+)
+
+func TestHandleCleanup(t *testing.T) {
+
+	snapshotGVK := volumeSnapshotGVK
+
+	tests := []struct {
+		name              string
+		policy            polkadotv1alpha1.CleanupPolicy
+		expectedVolumes   int
+		expectedSnapshots int
+	}{
+		{
+			name:              "Cleanup nothing by default",
+			policy:            polkadotv1alpha1.CleanupPolicy{},
+			expectedVolumes:   2,
+			expectedSnapshots: 1,
+		},
+		{
+			name:              "Cleanup volumes",
+			policy:            polkadotv1alpha1.CleanupPolicy{DeleteVolumes: true},
+			expectedVolumes:   1,
+			expectedSnapshots: 1,
+		},
+		{
+			name:              "Cleanup volumes and snapshots",
+			policy:            polkadotv1alpha1.CleanupPolicy{DeleteVolumes: true, DeleteSnapshots: true},
+			expectedVolumes:   1,
+			expectedSnapshots: 0,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	scheme.AddKnownTypeWithName(snapshotGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(snapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"), &unstructured.UnstructuredList{})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := metav1.Now()
+			polkadot := getFakePolkadot()
+			polkadot.UID = "polkadot-uid"
+			polkadot.Spec.CleanupPolicy = test.policy
+			polkadot.Finalizers = []string{cleanupFinalizer}
+			polkadot.DeletionTimestamp = &now
+
+			statefulSet := getFakeCleanupStatefulSet(t, scheme, polkadot, SentrySSName, "data")
+			snapshot := getFakeVolumeSnapshot("data-sentry-sset-0")
+
+			// Objects to track in the fake client.
+			objs := []runtime.Object{
+				polkadot,
+				statefulSet,
+				snapshot,
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-sentry-sset-0", Labels: getSentrylabels()}},
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "other-volume"}},
+			}
+
+			// Create a fake client to mock API calls.
+			client := newFakeClient(scheme, objs...)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+			found, err := reconciler.handleCustomResource(*getFakeRequest())
+			if found != nil || err != nil {
+				t.Fatalf("handleCustomResource: (%v)", err)
+			}
+
+			claims := &corev1.PersistentVolumeClaimList{}
+			if err := client.List(context.TODO(), claims); err != nil || len(claims.Items) != test.expectedVolumes {
+				t.Fatalf("handleCleanup: unexpected volumes (%v)", claims.Items)
+			}
+			snapshots := &unstructured.UnstructuredList{}
+			snapshots.SetGroupVersionKind(snapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"))
+			if err := client.List(context.TODO(), snapshots); err != nil || len(snapshots.Items) != test.expectedSnapshots {
+				t.Fatalf("handleCleanup: unexpected snapshots (%v)", snapshots.Items)
+			}
+			cr := &polkadotv1alpha1.Polkadot{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, cr); err != nil || len(cr.Finalizers) != 0 {
+				t.Fatalf("handleCleanup: finalizer not removed (%v)", cr.Finalizers)
+			}
+		})
+	}
+}
+
+func TestHandleCleanupOtherCR(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	scheme.AddKnownTypeWithName(volumeSnapshotGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(volumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"), &unstructured.UnstructuredList{})
+
+	// two CRs in the same namespace, the first one being deleted with its volumes and snapshots
+	now := metav1.Now()
+	polkadot := getFakePolkadot()
+	polkadot.UID = "polkadot-uid"
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.CleanupPolicy = polkadotv1alpha1.CleanupPolicy{DeleteVolumes: true, DeleteSnapshots: true}
+	polkadot.Finalizers = []string{cleanupFinalizer}
+	polkadot.DeletionTimestamp = &now
+	other := getFakePolkadot()
+	other.Name = "other-cr"
+	other.UID = "other-uid"
+	other.Spec.Kind = string(Archive)
+
+	objs := []runtime.Object{
+		polkadot,
+		other,
+		getFakeCleanupStatefulSet(t, scheme, polkadot, ValidatorSSName, "data"),
+		getFakeCleanupStatefulSet(t, scheme, other, ArchiveSSName, archiveVolumeName),
+		getFakeVolumeSnapshot("data-validator-sset-0"),
+		getFakeVolumeSnapshot(archiveVolumeName + "-archive-sset-0"),
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-validator-sset-0", Labels: getValidatorLabels()}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: archiveVolumeName + "-archive-sset-0", Labels: getArchiveLabels()}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: archiveVolumeName + "-archive-sset-1", Labels: getArchiveLabels()}},
+	}
+	client := newFakeClient(scheme, objs...)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	if err := reconciler.handleCleanup(polkadot); err != nil {
+		t.Fatalf("handleCleanup: (%v)", err)
+	}
+
+	// the volumes and the snapshots of the other CR are kept
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := client.List(context.TODO(), claims); err != nil || len(claims.Items) != 2 {
+		t.Fatalf("handleCleanup: unexpected volumes (%v)", claims.Items)
+	}
+	for _, claim := range claims.Items {
+		if claim.Labels["role"] != "archive" {
+			t.Fatalf("handleCleanup: volume of the other CR deleted (%v)", claims.Items)
+		}
+	}
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"))
+	if err := client.List(context.TODO(), snapshots); err != nil || len(snapshots.Items) != 1 || snapshots.Items[0].GetLabels()["pvc"] != archiveVolumeName+"-archive-sset-0" {
+		t.Fatalf("handleCleanup: unexpected snapshots (%v)", snapshots.Items)
+	}
+}
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: volumeSnapshotAPIGroup, Version: "v1beta1", Kind: "VolumeSnapshot"}
+
+// getFakeCleanupStatefulSet returns a StatefulSet of the CR, with a volume claim template of the given name
+func getFakeCleanupStatefulSet(t *testing.T, scheme *runtime.Scheme, CRInstance *polkadotv1alpha1.Polkadot, name string, claimName string) *appsv1.StatefulSet {
+	statefulSet := getFakeStatefulSet(name, 2)
+	statefulSet.Labels = getAppLabels()
+	statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: claimName}}}
+	if err := controllerutil.SetControllerReference(CRInstance, statefulSet, scheme); err != nil {
+		t.Fatalf("SetControllerReference: (%v)", err)
+	}
+	return statefulSet
+}
+
+// getFakeVolumeSnapshot returns a VolumeSnapshot of the claim, labelled like the ones of the backup job
+func getFakeVolumeSnapshot(claimName string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(claimName + "-20200101000000")
+	labels := getAppLabels()
+	labels["pvc"] = claimName
+	snapshot.SetLabels(labels)
+	return snapshot
+}
+
+func TestHandleFinalizer(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	client := newFakeClient(scheme, getFakePolkadot())
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	found, err := reconciler.handleCustomResource(*getFakeRequest())
+	if found == nil || err != nil {
+		t.Fatalf("handleCustomResource: (%v)", err)
+	}
+
+	cr := &polkadotv1alpha1.Polkadot{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, cr); err != nil || !hasFinalizer(cr, cleanupFinalizer) {
+		t.Fatalf("handleFinalizer: finalizer not added (%v)", cr.Finalizers)
+	}
+}
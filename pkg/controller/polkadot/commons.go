@@ -5,10 +5,13 @@ package polkadot
 import (
 	"context"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -17,6 +20,10 @@ const (
 	Sentry CRKind = "Sentry"
 	Validator CRKind = "Validator"
 	SentryAndValidator CRKind = "SentryAndValidator"
+	Bootnode CRKind = "Bootnode"
+	Archive CRKind = "Archive"
+	RpcNode CRKind = "RpcNode"
+	Collator CRKind = "Collator"
 )
 
 const(
@@ -28,16 +35,36 @@ func handleSkip() (bool,error){
 	return NotForcedRequeue,nil
 }
 
+// isRpcNodeAddOn tells if the RPC nodes have to be deployed next to the nodes of the CR Kind, the node pools declaring
+// their RPC nodes themselves
+func isRpcNodeAddOn(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return len(CRInstance.Spec.NodePools) == 0 && CRKind(CRInstance.Spec.Kind) != RpcNode && CRInstance.Spec.RpcNode.Replicas > 0
+}
+
 func (r *ReconcilerPolkadot) setOwnership(owner metav1.Object, owned metav1.Object) error {
 	return controllerutil.SetControllerReference(owner, owned, r.scheme)
 }
 
-func (r *ReconcilerPolkadot) createResource(resource interface{}, CRInstance *polkadotv1alpha1.Polkadot) error {
+// applyResource creates or updates the resource with a server-side apply, the operator owning only the fields it sets:
+// the fields set by other controllers are preserved, the ones conflicting with the desired state are taken over
+func (r *ReconcilerPolkadot) applyResource(resource interface{}, CRInstance *polkadotv1alpha1.Polkadot) error {
 	err := r.setOwnership(CRInstance, resource.(metav1.Object))
 	if err != nil {
 		return err
 	}
-	return r.client.Create(context.TODO(), resource.(runtime.Object))
+	object := resource.(runtime.Object)
+	// the apply request is the serialized resource, the kind is required
+	gvk, err := apiutil.GVKForObject(object, r.scheme)
+	if err != nil {
+		return err
+	}
+	object.GetObjectKind().SetGroupVersionKind(gvk)
+	return r.client.Patch(context.TODO(), object, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// isResourceChanged tells if the apply changed the found resource, the resourceVersion of an unchanged resource is left untouched
+func isResourceChanged(found metav1.Object, applied metav1.Object) bool {
+	return found.GetResourceVersion() != applied.GetResourceVersion()
 }
 
 func (r *ReconcilerPolkadot) fetchResource(resource interface{}, key types.NamespacedName) (isNotFound bool,e error) {
@@ -50,4 +77,45 @@ func (r *ReconcilerPolkadot) fetchResource(resource interface{}, key types.Names
 
 func (r *ReconcilerPolkadot) updateResource(resource interface{}) error {
 	return r.client.Update(context.TODO(), resource.(runtime.Object))
+}
+
+// updateStatus writes the status of the CR from a copy, the response carrying the stored spec without the node pools
+// applied in memory. Only the status and the resourceVersion are taken back, so that the next handlers still generate
+// the resources of the roles from the settings of their pools
+func (r *ReconcilerPolkadot) updateStatus(CRInstance *polkadotv1alpha1.Polkadot) error {
+	updated := CRInstance.DeepCopy()
+	err := r.client.Status().Update(context.TODO(), updated)
+	if err != nil {
+		return err
+	}
+	CRInstance.ResourceVersion = updated.ResourceVersion
+	CRInstance.Status = updated.Status
+	return nil
+}
+
+func (r *ReconcilerPolkadot) deleteResource(resource interface{}, opts ...client.DeleteOption) error {
+	return r.client.Delete(context.TODO(), resource.(runtime.Object), opts...)
+}
+
+// deleteResourceWithEvent deletes a resource, recording the result on the CustomResource
+func (r *ReconcilerPolkadot) deleteResourceWithEvent(CRInstance *polkadotv1alpha1.Polkadot, resource metav1.Object, kind string) error {
+	logger := getLogger(CRInstance).WithValues(kind+".Name", resource.GetName())
+
+	logger.Info("Deleting the " + kind + "...")
+	err := r.deleteResource(resource)
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Delete "+kind+" Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedDelete", "Failed to delete the %s %s: %v", kind, resource.GetName(), err)
+		return err
+	}
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "Deleted", "Deleted the %s %s", kind, resource.GetName())
+	return nil
+}
+
+// recordEvent emits an event on the CustomResource, the unit tests may leave the recorder unset
+func (r *ReconcilerPolkadot) recordEvent(CRInstance *polkadotv1alpha1.Polkadot, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Eventf(CRInstance, eventType, reason, messageFmt, args...)
 }
\ No newline at end of file
@@ -2,21 +2,26 @@ package polkadot
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	v12 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"testing"
 )
 
-func TestCreateResource(t *testing.T) {
+func TestApplyResource(t *testing.T) {
 
 	tests := []struct {
 		name        string
@@ -57,19 +62,68 @@ func TestCreateResource(t *testing.T) {
 	objs := []runtime.Object{polkadot}
 
 	// Create a fake client to mock API calls.
-	client := fake.NewFakeClientWithScheme(scheme, objs...)
+	client := newFakeClient(scheme, objs...)
 	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := reconciler.createResource(test.newResource, polkadot)
+			err := reconciler.applyResource(test.newResource, polkadot)
 			if err != nil {
-				t.Fatalf("createRsource: (%v)", err)
+				t.Fatalf("applyResource: (%v)", err)
+			}
+			created := test.newResource.(runtime.Object).DeepCopyObject()
+			err = reconciler.applyResource(test.newResource, polkadot)
+			if err != nil || isResourceChanged(created.(metav1.Object), test.newResource.(metav1.Object)) {
+				t.Fatalf("applyResource: unexpected change of an unchanged resource (%v)", err)
 			}
 		})
 	}
 }
 
+func TestApplyResourcePreservesFields(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Service with the fields allocated by the cluster and an annotation of another controller
+	current := newServiceSentry(polkadot)
+	current.Spec.ClusterIP = "10.0.0.10"
+	current.Spec.Ports[0].NodePort = 30001
+	current.Annotations["cloud-provider"] = "value"
+
+	client := newFakeClient(scheme, polkadot, current)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	modified := polkadot.DeepCopy()
+	modified.Spec.Metadata.Labels = map[string]string{"team": "blockchain"}
+	err := reconciler.applyResource(newServiceSentry(modified), polkadot)
+	if err != nil {
+		t.Fatalf("applyResource: (%v)", err)
+	}
+
+	updated := &corev1.Service{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: ServiceSentryName}, updated); err != nil {
+		t.Fatalf("applyResource: (%v)", err)
+	}
+	if updated.Spec.ClusterIP != current.Spec.ClusterIP || updated.Spec.Ports[0].NodePort != current.Spec.Ports[0].NodePort {
+		t.Fatalf("applyResource: allocated fields not preserved (%v)", updated.Spec)
+	}
+	if updated.Labels["team"] != "blockchain" || updated.Labels["role"] != "sentry" {
+		t.Fatalf("applyResource: unexpected labels (%v)", updated.Labels)
+	}
+	if updated.Annotations["cloud-provider"] != "value" {
+		t.Fatalf("applyResource: unexpected annotations (%v)", updated.Annotations)
+	}
+}
+
 func TestFetchResource(t *testing.T) {
 
 	testsFound := []struct {
@@ -141,7 +195,7 @@ func TestFetchResource(t *testing.T) {
 			objs := []runtime.Object{polkadot,test.resource.(runtime.Object)}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isNotFound,err := reconciler.fetchResource(test.resource,types.NamespacedName{Name: test.resourceName})
@@ -159,7 +213,7 @@ func TestFetchResource(t *testing.T) {
 			objs := []runtime.Object{polkadot}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isNotFound,err := reconciler.fetchResource(test.resource,types.NamespacedName{Name: test.resourceName})
@@ -221,7 +275,7 @@ func TestUpdateResource(t *testing.T) {
 			// Objects to track in the fake client.
 			objs := []runtime.Object{polkadot, test.resource.obj.(runtime.Object)}
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			err := reconciler.client.Get(context.TODO(), types.NamespacedName{Name: test.resourceName, Namespace: corev1.NamespaceAll}, test.resource.obj.(runtime.Object))
@@ -246,6 +300,106 @@ func TestUpdateResource(t *testing.T) {
 	}
 }
 
+// newFakeClient returns a fake client supporting the server-side apply of the operator
+func newFakeClient(scheme *runtime.Scheme, objs ...runtime.Object) client.Client {
+	return &fakeApplyClient{Client: fake.NewFakeClientWithScheme(scheme, objs...)}
+}
+
+// fakeApplyClient emulates the server-side apply, not supported by the fake client: the applied resource is
+// created if not found, otherwise merged into the found one (the lists by their merge key, e.g. the ports).
+// A dry-run apply returns the merged resource without persisting it
+type fakeApplyClient struct {
+	client.Client
+}
+
+func (c *fakeApplyClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	patchOptions := &client.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	isDryRun := len(patchOptions.DryRun) > 0
+
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+	current := obj.DeepCopyObject()
+	err = c.Client.Get(ctx, key, current)
+	if errors.IsNotFound(err) {
+		if isDryRun {
+			return nil
+		}
+		return c.Client.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	appliedJSON, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	mergedJSON, err := strategicpatch.StrategicMergePatch(currentJSON, appliedJSON, obj)
+	if err != nil {
+		return err
+	}
+	merged := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	if err := json.Unmarshal(mergedJSON, merged); err != nil {
+		return err
+	}
+
+	// like the API server, an unchanged resource is not updated
+	if equality.Semantic.DeepEqual(current, merged) {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(current).Elem())
+		return nil
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(merged).Elem())
+	if isDryRun {
+		return nil
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+// Status emulates the status subresource, not supported by the fake client: only the status of the written resource is
+// stored, and the resource is given back as stored, e.g. without the changes made in memory to its spec
+func (c *fakeApplyClient) Status() client.StatusWriter {
+	return &fakeStatusClient{client: c}
+}
+
+type fakeStatusClient struct {
+	client *fakeApplyClient
+}
+
+func (s *fakeStatusClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+	stored := obj.DeepCopyObject()
+	if err := s.client.Get(ctx, key, stored); err != nil {
+		return err
+	}
+	status := reflect.ValueOf(stored).Elem().FieldByName("Status")
+	if !status.IsValid() {
+		return s.client.Client.Status().Update(ctx, obj, opts...)
+	}
+	status.Set(reflect.ValueOf(obj).Elem().FieldByName("Status"))
+	if err := s.client.Client.Update(ctx, stored, opts...); err != nil {
+		return err
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored).Elem())
+	return nil
+}
+
+func (s *fakeStatusClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return s.client.Client.Status().Patch(ctx, obj, patch, opts...)
+}
+
 func getFakePolkadot() *polkadotv1alpha1.Polkadot{
 	// A Polkadot object with metadata and spec.
 	return &polkadotv1alpha1.Polkadot{
@@ -2,18 +2,152 @@
 // Licensed under MIT License
 package polkadot
 
+import "time"
+
 const (
 	ServiceSentryName    = "sentry-service"
 	ServiceValidatorName = "validator-service"
+	ServiceBootnodeName  = "bootnode-service"
+	ServiceArchiveName   = "archive-service"
+	ServiceRpcNodeName   = "rpcnode-service"
+	ServiceCollatorName  = "collator-service"
 	metricsPortName        = "http-metrics"
 	P2PPortName            = "p2p"
 	RPCPortName            = "http-rpc"
 	WSPortName             = "websocket-rpc"
 	ValidatorSSName        = "validator-sset"
 	SentrySSName           = "sentry-sset"
+	BootnodeSSName         = "bootnode-sset"
+	ArchiveSSName          = "archive-sset"
+	RpcNodeSSName          = "rpcnode-sset"
+	CollatorSSName         = "collator-sset"
 	ValidatorNetworkPolicy = "validator-networkpolicy"
+	operatorName           = "polkadot-operator"
+	dnsPort                = 53
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+	ingressWSPath          = "/ws"
+	ingressTLSSuffix       = "-tls"
+	externalDNSHostname    = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSTTL         = "external-dns.alpha.kubernetes.io/ttl"
+	nodeIPEnv              = "NODE_IP"
+	certManagerAPIGroup    = "cert-manager.io"
+	issuerKind             = "Issuer"
+	ValidatorHAName        = "validator-ha"
+	validatorHAReplicas    = 2
+	failoverDelay          = 2 * time.Minute
+	haActivePodEnv         = "HA_ACTIVE_POD"
+	haFencedPodEnv         = "HA_FENCED_POD"
+	ValidatorLeaseName     = "validator-leader"
+	leaderElectionName     = "validator-leader-election"
+	leaderElectionImage    = "bitnami/kubectl"
+	leaseDuration          = 30 * time.Second
+	leaderVolumeName       = "leader"
+	leaderMountPath        = "/leader"
+	authorityRole          = "Authority"
 	volumeMountPath        = "/data"
 	serviceName            = "polkadot"
+	archiveVolumeName      = "polkadot-archive-volume"
+	archiveVolumeSize      = "1Ti"
+	collatorBinary         = "polkadot-collator"
+	BackupName             = "polkadot-backup"
+	backupImage            = "bitnami/kubectl"
+	backupSchedule         = "0 0 * * *"
+	backupRetention        = 7
+	PayoutsName            = "polkadot-payouts"
+	polkadotJsImage        = "jacogr/polkadot-js-tools"
+	payoutsSchedule        = "0 1 * * *"
+	payoutsEras            = 4
+	payoutsSeedEnv         = "SEED"
+	StakingName            = "polkadot-staking"
+	stakingBackoffLimit    = 3
+	RegistrationPending    = "Pending"
+	RegistrationRegistered = "Registered"
+	RegistrationFailed     = "Failed"
+	volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	bootstrapImage         = "alpine"
+	nodeKeyVolumeName      = "node-key"
+	nodeKeyMountPath       = "/keys"
+	nodeKeyFileName        = "node-key"
+	nodeKeyInitName        = "node-key"
+	nodeKeyImage           = "busybox"
+	nodeKeysVolumeName     = "node-keys"
+	nodeKeysMountPath      = "/node-keys"
+	nodeKeysProjectionStep = 32
+	keystoreVolumeName     = "keystore"
+	keystoreMountPath      = "/keystore"
+	chainspecVolumeName    = "chainspec"
+	chainspecMountPath     = "/config"
+	chainspecFileName      = "chainspec.json"
+	hardenedDataVolumeName = "data"
+	tmpVolumeName          = "tmp"
+	tmpMountPath           = "/tmp"
+	restrictedCapability   = "NET_BIND_SERVICE"
+	customChain            = "custom"
+	ChainspecName          = "chainspec"
+	ChainspecBuilderName   = "chainspec-builder"
+	chainspecBuilderImage  = "bitnami/kubectl"
+	chainspecBaseChain     = "local"
+	buildVolumeName        = "build"
+	buildMountPath         = "/build"
+	healthProbePath        = "/health"
+	healthProbeDelay       = 10
+	healthProbePeriod      = 10
+	startupProbePeriod     = 60
+	startupProbeFailures   = 10080
+	stallDetectorName      = "stall-detector"
+	stallDetectorImage     = "alpine"
+	stallVolumeName        = "stall"
+	stallMountPath         = "/stall"
+	stallTimeout           = 10 * time.Minute
+	stallPollPeriod        = 30
+	syncReadinessName      = "sync-readiness"
+	syncReadinessImage     = "alpine"
+	syncMaxBlocksBehind    = 5
+	syncPollPeriod         = 10
+	eraExporterName        = "era-exporter"
+	eraExporterPortName    = "era-exporter"
+	eraExporterPort        = 9618
+	eraExporterPollPeriod  = 30
+	PodMonitorName         = "polkadot-pod-monitor"
+	PrometheusRuleName     = "polkadot-prometheus-rule"
+	metricsPrefix          = "polkadot"
+	defaultMinPeers        = 3
+	networkHealthPeriod    = 5 * time.Minute
+	networkHealthPollPeriod = time.Minute
+	NetworkHealthyType     = "NetworkHealthy"
+	DegradedType           = "Degraded"
+	PausedType             = "Paused"
+	OffenceDetectedType    = "OffenceDetected"
+	SlashedType            = "Slashed"
+	slashRecentEras        = 7
+	unappliedSlashesCount  = 100
+	slashDetectionPeriod   = time.Minute
+	syncStatusTimeout      = 5 * time.Second
+	MaintenanceScaleDown   = "ScaleDown"
+	MaintenanceFreeze      = "Freeze"
+	AntiAffinityRequired   = "Required"
+	zoneTopologyKey        = "topology.kubernetes.io/zone"
+	dashboardLabel         = "grafana_dashboard"
+	cleanupFinalizer       = "polkadot.swisscomblockchain.com/cleanup"
+	fieldManager           = "polkadot-operator"
+	specHashAnnotation     = "polkadot.swisscomblockchain.com/spec-hash"
+	templateHashAnnotation = "polkadot.swisscomblockchain.com/template-hash"
+	canaryAnnotation       = "polkadot.swisscomblockchain.com/canary-healthy-since"
+	DryRunAnnotation       = "polkadot.swisscomblockchain.com/dry-run"
+	appliedSpecAnnotation  = "polkadot.swisscomblockchain.com/applied-spec"
+	checksumAnnotation     = "polkadot.swisscomblockchain.com/config-checksum"
+	defaultHistoryLimit    = 10
+	historyMaxActions      = 20
+	canarySoakTime         = 10 * time.Minute
+	imageRefreshPeriod     = time.Hour
+	defaultRegistry        = "registry-1.docker.io"
+	revisionLabel          = "controller-revision-hash"
+	defaultTargetCPU       = 80
+	kedaAPIGroup           = "keda.sh"
+	vpaAPIGroup            = "autoscaling.k8s.io"
+	vpaUpdateModeOff       = "Off"
+	kedaRPCThreshold       = "100"
+	kedaWSThreshold        = "500"
 )
 
 func getAppLabels() map[string]string {
@@ -33,12 +167,45 @@ func getValidatorLabels() map[string]string {
 	return labels
 }
 
+func getBootnodeLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "bootnode"
+	return labels
+}
+
+func getArchiveLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "archive"
+	return labels
+}
+
+func getRpcNodeLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "rpcnode"
+	return labels
+}
+
+func getCollatorLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "collator"
+	return labels
+}
+
 func getCopyLabelsWithVersion(labels map[string]string, version string) map[string]string {
 	newLabels := getCopy(labels)
 	newLabels["version"] = version
 	return newLabels
 }
 
+// getCopyLabelsWithCustom merges the custom labels of the user, the operator labels take precedence as they are used by the selectors
+func getCopyLabelsWithCustom(labels map[string]string, customLabels map[string]string) map[string]string {
+	newLabels := getCopy(customLabels)
+	for key, value := range labels {
+		newLabels[key] = value
+	}
+	return newLabels
+}
+
 func getCopy(originalMap map[string]string) map[string]string {
 	newMap := make(map[string]string)
 	for key, value := range originalMap {
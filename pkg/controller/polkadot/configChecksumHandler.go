@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mountedData is the content of a ConfigMap or a Secret mounted by the pods, as hashed in the config checksum
+type mountedData struct {
+	Kind string
+	Name string
+	Data map[string][]byte
+}
+
+// setConfigChecksum annotates the pod template of the StatefulSet with the checksum of the ConfigMaps and the Secrets
+// mounted by its pods (e.g. the chainspec, the keystore, the node key), so that a change of their content rolls the
+// pods like a change of the template. A template without such volumes is left unannotated
+func (r *ReconcilerPolkadot) setConfigChecksum(statefulSet *appsv1.StatefulSet) error {
+	var mounted []mountedData
+	for _, volume := range statefulSet.Spec.Template.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			data, err := r.getConfigMapData(statefulSet.Namespace, volume.ConfigMap)
+			if err != nil {
+				return err
+			}
+			mounted = append(mounted, mountedData{Kind: "ConfigMap", Name: volume.ConfigMap.Name, Data: data})
+		}
+		if volume.Secret != nil {
+			data, err := r.getSecretData(statefulSet.Namespace, volume.Secret)
+			if err != nil {
+				return err
+			}
+			mounted = append(mounted, mountedData{Kind: "Secret", Name: volume.Secret.SecretName, Data: data})
+		}
+	}
+	if len(mounted) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(mounted)
+	if err != nil {
+		return err
+	}
+	annotations := getCopy(statefulSet.Spec.Template.Annotations)
+	annotations[checksumAnnotation] = fmt.Sprintf("%x", sha256.Sum256(data))
+	statefulSet.Spec.Template.Annotations = annotations
+	return nil
+}
+
+// getConfigMapData returns the keys of the ConfigMap projected in the volume, nil if the ConfigMap is not found yet
+func (r *ReconcilerPolkadot) getConfigMapData(namespace string, source *corev1.ConfigMapVolumeSource) (map[string][]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	isNotFound, err := r.fetchResource(configMap, types.NamespacedName{Name: source.Name, Namespace: namespace})
+	if err != nil || isNotFound {
+		return nil, err
+	}
+	data := map[string][]byte{}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	return getProjectedData(data, source.Items), nil
+}
+
+// getSecretData returns the keys of the Secret projected in the volume, nil if the Secret is not found yet
+func (r *ReconcilerPolkadot) getSecretData(namespace string, source *corev1.SecretVolumeSource) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	isNotFound, err := r.fetchResource(secret, types.NamespacedName{Name: source.SecretName, Namespace: namespace})
+	if err != nil || isNotFound {
+		return nil, err
+	}
+	return getProjectedData(secret.Data, source.Items), nil
+}
+
+// getConfigReferrers requeues the CRs referencing a changed ConfigMap or Secret of their namespace, so that a new
+// chainspec or a rotation of the keys is rolled out without waiting for the next resync
+func getConfigReferrers(c client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		polkadots := &polkadotv1alpha1.PolkadotList{}
+		err := c.List(context.TODO(), polkadots, client.InNamespace(object.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "Error on listing the CRs referencing the ConfigMaps and Secrets...")
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for i := range polkadots.Items {
+			referrer := &polkadots.Items[i]
+			references := getReferencedSecrets(referrer)
+			if _, isConfigMap := object.Object.(*corev1.ConfigMap); isConfigMap {
+				references = getReferencedConfigMaps(referrer)
+			}
+			for _, reference := range references {
+				if reference == object.Meta.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: referrer.Name, Namespace: referrer.Namespace}})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
+
+// getReferencedConfigMaps returns the ConfigMaps referenced by the CR, not managed by the operator
+func getReferencedConfigMaps(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	if ref := CRInstance.Spec.ChainspecConfigMapRef; ref != nil {
+		return []string{ref.Name}
+	}
+	return nil
+}
+
+// getReferencedSecrets returns the Secrets referenced by the CR, not managed by the operator: the node keys of the roles,
+// the keystore of the validator and the seeds of the staking and payouts accounts
+func getReferencedSecrets(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	spec := CRInstance.Spec
+	secrets := []string{}
+	for _, ref := range []*corev1.SecretKeySelector{spec.Sentry.NodeKeySecretRef, spec.Validator.NodeKeySecretRef, spec.Archive.NodeKeySecretRef,
+		spec.RpcNode.NodeKeySecretRef, spec.Collator.NodeKeySecretRef, spec.Validator.Staking.StashSecretRef,
+		spec.Validator.Staking.ControllerSecretRef, spec.Payouts.AccountSecretRef} {
+		if ref != nil {
+			secrets = append(secrets, ref.Name)
+		}
+	}
+	if ref := spec.Validator.KeystoreSecretRef; ref != nil {
+		secrets = append(secrets, ref.Name)
+	}
+	return secrets
+}
+
+// getProjectedData returns the keys of the items of the volume, all the keys if no item is set
+func getProjectedData(data map[string][]byte, items []corev1.KeyToPath) map[string][]byte {
+	if len(items) == 0 {
+		return data
+	}
+	projected := map[string][]byte{}
+	for _, item := range items {
+		if value, isFound := data[item.Key]; isFound {
+			projected[item.Key] = value
+		}
+	}
+	return projected
+}
@@ -0,0 +1,49 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"testing"
+)
+
+func TestGetConfigReferrers(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "spec.json"}
+	polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+	polkadot.Spec.Sentry.NodeKeySecretRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "sentry-node-key"}, Key: "key"}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	getRequests := getConfigReferrers(client)
+
+	// the CRs referencing a changed ConfigMap or Secret are requeued
+	for _, object := range []runtime.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "chainspec"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "keystore"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sentry-node-key"}},
+	} {
+		requests := getRequests(handler.MapObject{Meta: object.(metav1.Object), Object: object})
+		if len(requests) != 1 || requests[0].Name != polkadot.Name {
+			t.Fatalf("getConfigReferrers: unexpected requests for %s (%v)", object.(metav1.Object).GetName(), requests)
+		}
+	}
+
+	// a Secret named like a referenced ConfigMap, or not referenced at all, does not requeue the CRs
+	for _, object := range []runtime.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "chainspec"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	} {
+		if requests := getRequests(handler.MapObject{Meta: object.(metav1.Object), Object: object}); len(requests) != 0 {
+			t.Fatalf("getConfigReferrers: unexpected requests (%v)", requests)
+		}
+	}
+}
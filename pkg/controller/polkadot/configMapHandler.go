@@ -0,0 +1,44 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r *ReconcilerPolkadot) handleConfigMapGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *corev1.ConfigMap) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("ConfigMap.Name", desiredResource.Name)
+
+	toBeFoundResource := &corev1.ConfigMap{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the ConfigMap...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("ConfigMap not found...")
+		logger.Info("Creating a new ConfigMap...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new ConfigMap...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new ConfigMap")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update ConfigMap Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the ConfigMap...")
+	}
+
+	return NotForcedRequeue, nil
+}
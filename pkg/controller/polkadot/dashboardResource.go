@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"encoding/json"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dashboardPanel is a Grafana graph panel, it only holds the fields the dashboards of the operator need
+type dashboardPanel struct {
+	ID      int                    `json:"id"`
+	Title   string                 `json:"title"`
+	Type    string                 `json:"type"`
+	GridPos map[string]int         `json:"gridPos"`
+	Targets []dashboardPanelTarget `json:"targets"`
+}
+
+type dashboardPanelTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	RefID        string `json:"refId"`
+}
+
+type dashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Editable      bool              `json:"editable"`
+	Refresh       string            `json:"refresh"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Time          map[string]string `json:"time"`
+	Panels        []dashboardPanel  `json:"panels"`
+}
+
+func getDashboardName(role string) string {
+	return fmt.Sprintf("polkadot-%s-dashboard", role)
+}
+
+func getDashboardPanel(id int, title string, targets ...dashboardPanelTarget) dashboardPanel {
+	return dashboardPanel{
+		ID:      id,
+		Title:   title,
+		Type:    "graph",
+		GridPos: map[string]int{"h": 8, "w": 12, "x": ((id - 1) % 2) * 12, "y": ((id - 1) / 2) * 8},
+		Targets: targets,
+	}
+}
+
+// getDashboard returns the dashboard of a role, its queries are filtered on the pods of the role in the namespace of the CR
+func getDashboard(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) dashboard {
+	selector := getPodsSelector(CRInstance.Namespace, []string{rr.statefulSetName})
+
+	return dashboard{
+		UID:           fmt.Sprintf("%s-%s-%s", CRInstance.Namespace, CRInstance.Name, rr.role),
+		Title:         fmt.Sprintf("Polkadot %s (%s/%s)", rr.role, CRInstance.Namespace, CRInstance.Name),
+		Tags:          []string{"polkadot", rr.role},
+		Editable:      true,
+		Refresh:       "30s",
+		SchemaVersion: 22,
+		Time:          map[string]string{"from": "now-6h", "to": "now"},
+		Panels: []dashboardPanel{
+			getDashboardPanel(1, "Block height",
+				dashboardPanelTarget{Expr: fmt.Sprintf(`%s_block_height_number{status="best",%s}`, metricsPrefix, selector), LegendFormat: "{{pod}} best", RefID: "A"},
+				dashboardPanelTarget{Expr: fmt.Sprintf(`%s_block_height_number{status="finalized",%s}`, metricsPrefix, selector), LegendFormat: "{{pod}} finalized", RefID: "B"},
+			),
+			getDashboardPanel(2, "Peers",
+				dashboardPanelTarget{Expr: fmt.Sprintf(`%s_sync_peers{%s}`, metricsPrefix, selector), LegendFormat: "{{pod}}", RefID: "A"},
+			),
+			getDashboardPanel(3, "Block import time (p90)",
+				dashboardPanelTarget{Expr: fmt.Sprintf(`histogram_quantile(0.9, rate(%s_block_verification_and_import_time_bucket{%s}[5m]))`, metricsPrefix, selector), LegendFormat: "{{pod}}", RefID: "A"},
+			),
+			getDashboardPanel(4, "Node up",
+				dashboardPanelTarget{Expr: fmt.Sprintf(`up{%s}`, selector), LegendFormat: "{{pod}}", RefID: "A"},
+			),
+		},
+	}
+}
+
+// newDashboardConfigMap returns the ConfigMap of the dashboard of a role, labelled to be loaded by the Grafana sidecar
+func newDashboardConfigMap(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *corev1.ConfigMap {
+	labels := getCopyLabelsWithCustom(getCopyLabelsWithCustom(getAppLabels(), CRInstance.Spec.Metadata.Labels), CRInstance.Spec.Monitoring.Labels)
+	labels["role"] = rr.role
+	labels[dashboardLabel] = "1"
+
+	// the dashboard only holds strings, ints and maps: the marshalling can not fail
+	content, _ := json.MarshalIndent(getDashboard(CRInstance, rr), "", "  ")
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getDashboardName(rr.role),
+			Namespace:   CRInstance.Namespace,
+			Labels:      labels,
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Data: map[string]string{
+			fmt.Sprintf("%s.json", getDashboardName(rr.role)): string(content),
+		},
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sort"
+	"strings"
+)
+
+// dryRunClient is the client of the dry-run reconciliations: the reads are served by the client of the operator, the
+// creations, updates and patches are sent in dry-run mode, so that the API server validates them without persisting
+// them, and the deletions are not sent at all. Every write changing a resource is recorded as a change
+type dryRunClient struct {
+	client.Client
+	scheme  *runtime.Scheme
+	changes []string
+}
+
+func newDryRunClient(c client.Client, scheme *runtime.Scheme) *dryRunClient {
+	return &dryRunClient{Client: c, scheme: scheme}
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	c.recordChange("create", obj, nil)
+	return nil
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	found, err := c.fetchFound(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	return c.recordUpdate(found, obj)
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	found, err := c.fetchFound(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	return c.recordUpdate(found, obj)
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	found, err := c.fetchFound(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		// like the API server, the deletion of a resource not found fails
+		gvk, err := apiutil.GVKForObject(obj, c.scheme)
+		if err != nil {
+			return err
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		return errors.NewNotFound(gvr.GroupResource(), getObjectName(obj))
+	}
+	c.recordChange("delete", obj, nil)
+	return nil
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	c.recordChange("delete all", obj, nil)
+	return nil
+}
+
+// Status returns a writer sending the updates of the status in dry-run mode, they are not recorded as changes
+func (c *dryRunClient) Status() client.StatusWriter {
+	return &dryRunStatusWriter{StatusWriter: c.Client.Status()}
+}
+
+// fetchFound returns the current state of the written resource, nil if it does not exist
+func (c *dryRunClient) fetchFound(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	found := obj.DeepCopyObject()
+	err = c.Client.Get(ctx, key, found)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// recordUpdate records the fields of the resource the write would change, or its creation if it does not exist yet
+func (c *dryRunClient) recordUpdate(found runtime.Object, written runtime.Object) error {
+	if found == nil {
+		c.recordChange("create", written, nil)
+		return nil
+	}
+	foundFields, err := getComparedFields(found)
+	if err != nil {
+		return err
+	}
+	writtenFields, err := getComparedFields(written)
+	if err != nil {
+		return err
+	}
+	changedFields := getChangedFields(foundFields, writtenFields, "")
+	if len(changedFields) > 0 {
+		c.recordChange("update", written, changedFields)
+	}
+	return nil
+}
+
+// recordChange records a change of the resource, e.g. "update StatefulSet polkadot-sentry: spec.replicas"
+func (c *dryRunClient) recordChange(action string, obj runtime.Object, changedFields []string) {
+	kind := reflect.TypeOf(obj).Elem().Name()
+	if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+		kind = gvk.Kind
+	}
+	change := fmt.Sprintf("%s %s %s", action, kind, getObjectName(obj))
+	if len(changedFields) > 0 {
+		change += ": " + strings.Join(changedFields, ", ")
+	}
+	c.changes = append(c.changes, change)
+}
+
+// dryRunStatusWriter sends the updates and the patches of the status in dry-run mode
+type dryRunStatusWriter struct {
+	client.StatusWriter
+}
+
+func (w *dryRunStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return w.StatusWriter.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (w *dryRunStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.StatusWriter.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// getComparedFields returns the fields of the resource compared by the dry run, without its status and the metadata
+// maintained by the API server
+func getComparedFields(obj runtime.Object) (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"apiVersion", "kind", "status"} {
+		delete(fields, field)
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "uid", "selfLink", "generation", "creationTimestamp", "managedFields"} {
+			delete(metadata, field)
+		}
+	}
+	return fields, nil
+}
+
+// getChangedFields returns the paths of the fields differing between the found and the written resource, e.g.
+// "spec.template.spec.containers[0].image". The lists of different lengths are reported as a whole
+func getChangedFields(found, written interface{}, path string) []string {
+	foundMap, isFoundMap := found.(map[string]interface{})
+	writtenMap, isWrittenMap := written.(map[string]interface{})
+	if isFoundMap && isWrittenMap {
+		keys := map[string]bool{}
+		for key := range foundMap {
+			keys[key] = true
+		}
+		for key := range writtenMap {
+			keys[key] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		var changedFields []string
+		for _, key := range sortedKeys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			changedFields = append(changedFields, getChangedFields(foundMap[key], writtenMap[key], keyPath)...)
+		}
+		return changedFields
+	}
+
+	foundList, isFoundList := found.([]interface{})
+	writtenList, isWrittenList := written.([]interface{})
+	if isFoundList && isWrittenList && len(foundList) == len(writtenList) {
+		var changedFields []string
+		for i := range foundList {
+			changedFields = append(changedFields, getChangedFields(foundList[i], writtenList[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return changedFields
+	}
+
+	if reflect.DeepEqual(found, written) {
+		return nil
+	}
+	return []string{path}
+}
+
+func getObjectName(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+// isDryRun tells if the CR is reconciled in dry-run mode, for all the CRs with the --dry-run flag of the operator or
+// for a single one with the dry-run annotation
+func isDryRun(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return config.DryRun || CRInstance.Annotations[DryRunAnnotation] == "true"
+}
+
+// handleDryRun runs the handlers of the reconciliation with a client recording their writes instead of applying them,
+// and reports the changes they would make in the status and the events of the CR. The forced requeues are ignored so
+// that every handler runs, the resources which would be created being then not found by the next handlers. The changes
+// are reported only when they differ from the last report, the periodic reconciliations leaving the status untouched
+func (r *ReconcilerPolkadot) handleDryRun(CRInstance *polkadotv1alpha1.Polkadot) error {
+	logger := getLogger(CRInstance)
+
+	reported := CRInstance.DeepCopy()
+	dryRunClient := newDryRunClient(r.client, r.scheme)
+	// the events of the handlers would report changes which are not made, they are left out
+	dryRunReconciler := &ReconcilerPolkadot{client: dryRunClient, scheme: r.scheme}
+	handlers := []func(*polkadotv1alpha1.Polkadot) (bool, error){
+		dryRunReconciler.handleServiceAccount,
+		dryRunReconciler.handleValidatorFailover,
+		dryRunReconciler.handleBootnodes,
+		dryRunReconciler.handleReservedPeers,
+		dryRunReconciler.handlePublicAddresses,
+		dryRunReconciler.handleSentryAddresses,
+		dryRunReconciler.handleChainspecBuilder,
+		dryRunReconciler.handleImagePinning,
+		dryRunReconciler.handleNodeKeys,
+		dryRunReconciler.handleStatefulSet,
+		dryRunReconciler.handleAutoscaling,
+		dryRunReconciler.handlePodDisruptionBudget,
+		dryRunReconciler.handleService,
+		dryRunReconciler.handleIngress,
+		dryRunReconciler.handleNetworkPolicy,
+		dryRunReconciler.handleStaleResources,
+		dryRunReconciler.handleBackup,
+		dryRunReconciler.handlePayouts,
+		dryRunReconciler.handleMonitoring,
+		dryRunReconciler.handleStaking,
+		dryRunReconciler.handleSlashDetection,
+	}
+	for _, handler := range handlers {
+		if _, err := handler(CRInstance); err != nil {
+			logger.Error(err, "Dry Run Error...")
+			r.recordEvent(reported, corev1.EventTypeWarning, "DryRunFailed", "Failed to compute the changes of the resources: %v", err)
+			return err
+		}
+	}
+	changes := dryRunClient.changes
+	// the rotation of the session keys is an RPC call to the validator, it is reported without being run
+	if isKeyRotationRequested(CRInstance) {
+		changes = append(changes, "rotate the session keys of the validator")
+	}
+	if len(changes) == 0 {
+		changes = nil
+	}
+	if reported.Status.DryRun != nil && reflect.DeepEqual(changes, reported.Status.DryRun.Changes) {
+		logger.V(debugLevel).Info("Dry run changes already reported...")
+		return nil
+	}
+
+	for _, change := range changes {
+		r.recordEvent(reported, corev1.EventTypeNormal, "DryRun", "Would %s", change)
+	}
+	reported.Status.DryRun = &polkadotv1alpha1.DryRunStatus{Time: metav1.Now(), Changes: changes}
+	err := r.client.Status().Update(context.TODO(), reported)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// eraExporterScript serves the metrics file with the http module of node, and rewrites it every $POLL seconds from the
+// staking state of the local node. The outputs of polkadot-js-api are flattened and their number separators removed
+// (e.g. "1,234"), so that the fields are extracted by sed
+const eraExporterScript = `set -u
+api() { polkadot-js-api --ws "$ENDPOINT" "$@" 2>/dev/null | tr -d '\n ,'; }
+field() { sed -n "s/.*\"$1\":\"\{0,1\}\([0-9]*\).*/\1/p"; }
+node -e "require('http').createServer((req, res) => { try { res.end(require('fs').readFileSync('/tmp/metrics')) } catch (e) { res.statusCode = 503; res.end() } }).listen($PORT)" &
+while true; do
+  era=$(api query.staking.activeEra | field index)
+  if [ -n "$era" ]; then
+    points=$(api query.staking.erasRewardPoints "$era")
+    active=0; if api query.session.validators | grep -q "\"$STASH\""; then active=1; fi
+    progress=$(api derive.session.progress)
+    {
+      echo "polkadot_exporter_active_era $era"
+      echo "polkadot_exporter_era_points{stash=\"$STASH\"} $(echo "$points" | field "$STASH" | grep . || echo 0)"
+      echo "polkadot_exporter_era_points_total $(echo "$points" | field total | grep . || echo 0)"
+      echo "polkadot_exporter_active_validator{stash=\"$STASH\"} $active"
+      for metric in currentIndex:session_index sessionProgress:session_progress sessionLength:session_length eraProgress:era_progress eraLength:era_length; do
+        value=$(echo "$progress" | field "${metric%%:*}")
+        if [ -n "$value" ]; then echo "polkadot_exporter_${metric#*:} $value"; fi
+      done
+    } > /tmp/metrics.new && mv /tmp/metrics.new /tmp/metrics
+  fi
+  sleep "$POLL"
+done`
+
+func isEraExporterEnabled(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return CRInstance.Spec.Validator.EraExporter.Enabled
+}
+
+// getEraExporterStash returns the exported stash, the one of the staking bootstrap by default
+func getEraExporterStash(CRInstance *polkadotv1alpha1.Polkadot) string {
+	if CRInstance.Spec.Validator.EraExporter.Stash != "" {
+		return CRInstance.Spec.Validator.EraExporter.Stash
+	}
+	return CRInstance.Spec.Validator.Staking.Stash
+}
+
+func getEraExporterPort(CRInstance *polkadotv1alpha1.Polkadot) int32 {
+	if CRInstance.Spec.Validator.EraExporter.Port == 0 {
+		return eraExporterPort
+	}
+	return CRInstance.Spec.Validator.EraExporter.Port
+}
+
+// getEraExporterContainer returns the sidecar of the validator exporting the staking state of the stash, read through the
+// WebSocket port of the client of the pod, so that the standby pod of the high availability exports it as well
+func getEraExporterContainer(CRInstance *polkadotv1alpha1.Polkadot) corev1.Container {
+	eraExporter := CRInstance.Spec.Validator.EraExporter
+	image := eraExporter.Image
+	if image == "" {
+		image = polkadotJsImage
+	}
+	port := getEraExporterPort(CRInstance)
+
+	return corev1.Container{
+		Name:    eraExporterName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", eraExporterScript},
+		Env: []corev1.EnvVar{
+			{Name: "ENDPOINT", Value: fmt.Sprintf("ws://localhost:%d", getPorts(CRInstance).WS)},
+			{Name: "STASH", Value: getEraExporterStash(CRInstance)},
+			{Name: "PORT", Value: fmt.Sprint(port)},
+			{Name: "POLL", Value: fmt.Sprint(eraExporterPollPeriod)},
+		},
+		Ports: []corev1.ContainerPort{{
+			Name:          eraExporterPortName,
+			ContainerPort: port,
+			Protocol:      corev1.ProtocolTCP,
+		}},
+		Resources: eraExporter.Resources,
+	}
+}
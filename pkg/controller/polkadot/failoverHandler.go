@@ -0,0 +1,248 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handleValidatorFailover runs the active/passive validators of the high availability mode: it keeps the ConfigMap naming
+// the active pod, and fails over to the standby pod once the active one has not been ready for the failover delay.
+// With the leader election, the active pod is elected by the pods themselves instead
+func (r *ReconcilerPolkadot) handleValidatorFailover(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if !isHighAvailabilityEnabled(CRInstance) {
+		return r.handleValidatorFailoverDisabled(CRInstance)
+	}
+
+	if CRInstance.Status.Failover == nil {
+		CRInstance.Status.Failover = &polkadotv1alpha1.FailoverStatus{ActivePod: getValidatorPodName(0)}
+		err := r.updateFailoverStatus(CRInstance)
+		if err != nil {
+			return NotForcedRequeue, err
+		}
+	}
+	if isLeaderElectionEnabled(CRInstance) {
+		return r.handleValidatorLeaderElection(CRInstance)
+	}
+	isForcedRequeue, err := r.handleConfigMapGeneric(CRInstance, newValidatorHAConfigMap(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	if getMaintenanceMode(CRInstance.Spec.Validator.NodeOptions) != "" {
+		return handleSkip()
+	}
+
+	if CRInstance.Status.Failover.FencedPod != "" {
+		return r.completeFailover(CRInstance)
+	}
+	return r.checkActiveValidator(CRInstance)
+}
+
+// handleValidatorFailoverDisabled deletes the high availability ConfigMap and clears the failover status
+func (r *ReconcilerPolkadot) handleValidatorFailoverDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("ConfigMap.Name", ValidatorHAName)
+
+	if CRInstance.Status.Failover != nil {
+		CRInstance.Status.Failover = nil
+		err := r.updateFailoverStatus(CRInstance)
+		if err != nil {
+			return NotForcedRequeue, err
+		}
+	}
+
+	foundResource := &corev1.ConfigMap{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: ValidatorHAName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the ConfigMap...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("High availability disabled, deleting the ConfigMap...")
+	err = r.deleteResourceWithEvent(CRInstance, foundResource, "ConfigMap")
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+// checkActiveValidator starts a failover when the active pod has not been ready for the failover delay and the standby pod is ready.
+// The active pod is fenced first: it is deleted, and the standby pod is only activated once it is terminated (see completeFailover)
+func (r *ReconcilerPolkadot) checkActiveValidator(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+	failover := CRInstance.Status.Failover
+
+	active, isActiveReady, err := r.getValidatorPod(CRInstance, failover.ActivePod)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	if isActiveReady {
+		if failover.UnhealthySince == nil {
+			return handleSkip()
+		}
+		logger.Info("Active validator ready again...", "Pod.Name", failover.ActivePod)
+		failover.UnhealthySince = nil
+		return NotForcedRequeue, r.updateFailoverStatus(CRInstance)
+	}
+
+	now := metav1.Now()
+	if failover.UnhealthySince == nil {
+		logger.V(warnLevel).Info("Active validator not ready, starting the failover delay...", "Pod.Name", failover.ActivePod, "FailoverDelay", getFailoverDelay(CRInstance).String())
+		failover.UnhealthySince = &now
+		return NotForcedRequeue, r.updateFailoverStatus(CRInstance)
+	}
+	if now.Sub(failover.UnhealthySince.Time) < getFailoverDelay(CRInstance) {
+		return handleSkip()
+	}
+
+	standbyPod := getStandbyValidatorPodName(failover.ActivePod)
+	_, isStandbyReady, err := r.getValidatorPod(CRInstance, standbyPod)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	if !isStandbyReady {
+		logger.V(warnLevel).Info("Standby validator not ready, not able to fail over...", "Pod.Name", standbyPod)
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailoverBlocked", "The active validator %s is down, but the standby validator %s is not ready", failover.ActivePod, standbyPod)
+		return handleSkip()
+	}
+
+	logger.Info("Failing over to the standby validator, fencing the active one...", "Pod.Name", failover.ActivePod)
+	failover.FencedPod = failover.ActivePod
+	failover.FencedPodUID = ""
+	if active != nil {
+		failover.FencedPodUID = active.UID
+	}
+	failover.ActivePod = standbyPod
+	failover.UnhealthySince = nil
+	err = r.applyFailover(CRInstance)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	if active != nil {
+		err = r.deleteResource(active)
+		if err != nil {
+			logger.Error(err, "Error on deleting the fenced validator...")
+			return NotForcedRequeue, err
+		}
+	}
+	r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailoverStarted", "The validator %s has not been ready for %s, fencing it before activating %s", failover.FencedPod, getFailoverDelay(CRInstance).String(), standbyPod)
+	return ForcedRequeue, nil
+}
+
+// completeFailover activates the standby pod once the fenced pod is terminated, the pod recreated by the StatefulSet
+// with the same name reads the new active pod at its start and runs as the standby one
+func (r *ReconcilerPolkadot) completeFailover(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+	failover := CRInstance.Status.Failover
+
+	fenced := &corev1.Pod{}
+	isNotFound, err := r.fetchResource(fenced, types.NamespacedName{Name: failover.FencedPod, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Pod...", "Pod.Name", failover.FencedPod)
+		return NotForcedRequeue, err
+	}
+	if !isNotFound && fenced.UID == failover.FencedPodUID {
+		// e.g. the node of the pod is unreachable, the pod has to be force deleted once the node is known to be down
+		logger.Info("Waiting for the termination of the fenced validator...", "Pod.Name", failover.FencedPod)
+		return handleSkip()
+	}
+
+	now := metav1.Now()
+	failover.FencedPod = ""
+	failover.FencedPodUID = ""
+	failover.LastFailoverTime = &now
+	err = r.applyFailover(CRInstance)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+
+	// the standby pod is restarted, to start with the validator role and the session keys
+	active, _, err := r.getValidatorPod(CRInstance, failover.ActivePod)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	if active != nil {
+		err = r.deleteResource(active)
+		if err != nil {
+			logger.Error(err, "Error on restarting the activated validator...", "Pod.Name", failover.ActivePod)
+			return NotForcedRequeue, err
+		}
+	}
+	logger.Info("Failed over to the standby validator", "Pod.Name", failover.ActivePod)
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "FailedOver", "The validator %s is now the active one", failover.ActivePod)
+	return ForcedRequeue, nil
+}
+
+// applyFailover records the failover in the status, then in the ConfigMap read by the pods, before any pod is restarted
+func (r *ReconcilerPolkadot) applyFailover(CRInstance *polkadotv1alpha1.Polkadot) error {
+	err := r.updateFailoverStatus(CRInstance)
+	if err != nil {
+		return err
+	}
+	_, err = r.handleConfigMapGeneric(CRInstance, newValidatorHAConfigMap(CRInstance))
+	return err
+}
+
+func (r *ReconcilerPolkadot) updateFailoverStatus(CRInstance *polkadotv1alpha1.Polkadot) error {
+	logger := getLogger(CRInstance)
+
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return err
+	}
+	return nil
+}
+
+// getValidatorPod returns the validator pod with the given name, nil if not found, and if it is running and ready
+func (r *ReconcilerPolkadot) getValidatorPod(CRInstance *polkadotv1alpha1.Polkadot, name string) (*corev1.Pod, bool, error) {
+	pod := &corev1.Pod{}
+	isNotFound, err := r.fetchResource(pod, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		getLogger(CRInstance).Error(err, "Error on fetch the Pod...", "Pod.Name", name)
+		return nil, false, err
+	}
+	if isNotFound {
+		return nil, false, nil
+	}
+	return pod, isPodReady(pod), nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isFailoverInProgress tells if the active validator is not ready or a failover is waiting for the fenced pod.
+// The Lease of the leader election is not watched, so it is always polled
+func isFailoverInProgress(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	if isLeaderElectionEnabled(CRInstance) {
+		return true
+	}
+	failover := CRInstance.Status.Failover
+	return failover != nil && (failover.FencedPod != "" || failover.UnhealthySince != nil)
+}
+
+func getValidatorPodName(ordinal int32) string {
+	return fmt.Sprintf("%s-%d", ValidatorSSName, ordinal)
+}
+
+func getStandbyValidatorPodName(activePod string) string {
+	if activePod == getValidatorPodName(0) {
+		return getValidatorPodName(1)
+	}
+	return getValidatorPodName(0)
+}
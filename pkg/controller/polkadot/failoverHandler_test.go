@@ -0,0 +1,116 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleValidatorFailover(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+	polkadot.Spec.Validator.HighAvailability.Enabled = true
+
+	getPod := func(name string, isReady bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")}}
+		pod.Status.Phase = corev1.PodRunning
+		if isReady {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	active := getValidatorPodName(0)
+	standby := getValidatorPodName(1)
+
+	client := newFakeClient(scheme, polkadot, getPod(active, false), getPod(standby, true))
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+	getConfigMap := func() *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		if _, err := reconciler.fetchResource(configMap, types.NamespacedName{Name: ValidatorHAName}); err != nil {
+			t.Fatalf("handleValidatorFailover: (%v)", err)
+		}
+		return configMap
+	}
+
+	// the first pod is the active one
+	isRequeueForced, err := reconciler.handleValidatorFailover(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleValidatorFailover: (%v, %v)", isRequeueForced, err)
+	}
+	if configMap := getConfigMap(); configMap.Data["activePod"] != active || configMap.Data["fencedPod"] != "" {
+		t.Fatalf("handleValidatorFailover: unexpected ConfigMap (%v)", configMap.Data)
+	}
+
+	// the active pod is not ready, the failover delay starts
+	isRequeueForced, err = reconciler.handleValidatorFailover(polkadot)
+	if isRequeueForced || err != nil || polkadot.Status.Failover.UnhealthySince == nil || !isFailoverInProgress(polkadot) {
+		t.Fatalf("handleValidatorFailover: failover delay not started (%v, %v)", polkadot.Status.Failover, err)
+	}
+
+	// after the failover delay the active pod is fenced
+	unhealthySince := metav1.NewTime(time.Now().Add(-failoverDelay))
+	polkadot.Status.Failover.UnhealthySince = &unhealthySince
+	isRequeueForced, err = reconciler.handleValidatorFailover(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleValidatorFailover: (%v, %v)", isRequeueForced, err)
+	}
+	failover := polkadot.Status.Failover
+	if failover.ActivePod != standby || failover.FencedPod != active || failover.FencedPodUID != types.UID(active+"-uid") {
+		t.Fatalf("handleValidatorFailover: unexpected failover status (%v)", failover)
+	}
+	if configMap := getConfigMap(); configMap.Data["activePod"] != standby || configMap.Data["fencedPod"] != active {
+		t.Fatalf("handleValidatorFailover: unexpected ConfigMap (%v)", configMap.Data)
+	}
+	if isNotFound, err := reconciler.fetchResource(&corev1.Pod{}, types.NamespacedName{Name: active}); err != nil || !isNotFound {
+		t.Fatalf("handleValidatorFailover: fenced pod not deleted (%v)", err)
+	}
+
+	// once the fenced pod is terminated, the standby pod is restarted as the active one
+	isRequeueForced, err = reconciler.handleValidatorFailover(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleValidatorFailover: (%v, %v)", isRequeueForced, err)
+	}
+	if failover.FencedPod != "" || failover.LastFailoverTime == nil || isFailoverInProgress(polkadot) {
+		t.Fatalf("handleValidatorFailover: failover not completed (%v)", failover)
+	}
+	if configMap := getConfigMap(); configMap.Data["activePod"] != standby || configMap.Data["fencedPod"] != "" {
+		t.Fatalf("handleValidatorFailover: unexpected ConfigMap (%v)", configMap.Data)
+	}
+	if isNotFound, err := reconciler.fetchResource(&corev1.Pod{}, types.NamespacedName{Name: standby}); err != nil || !isNotFound {
+		t.Fatalf("handleValidatorFailover: activated pod not restarted (%v)", err)
+	}
+
+	// the sentries only reach the active validator
+	statefulSet := newStatefulSetValidator(polkadot)
+	command := statefulSet.Spec.Template.Spec.Containers[0].Command
+	if *statefulSet.Spec.Replicas != validatorHAReplicas || len(command) != 3 || !strings.Contains(command[2], `"$HA_ACTIVE_POD" = "$HOSTNAME"`) {
+		t.Fatalf("newStatefulSetValidator: unexpected StatefulSet (%v)", command)
+	}
+	if selector := newServiceValidator(polkadot).Spec.Selector; selector["statefulset.kubernetes.io/pod-name"] != standby {
+		t.Fatalf("newServiceValidator: unexpected selector (%v)", selector)
+	}
+
+	// the ConfigMap is deleted once the high availability is disabled
+	polkadot.Spec.Validator.HighAvailability.Enabled = false
+	if _, err := reconciler.handleValidatorFailover(polkadot); err != nil || polkadot.Status.Failover != nil {
+		t.Fatalf("handleValidatorFailover: (%v)", err)
+	}
+	if isNotFound, err := reconciler.fetchResource(&corev1.ConfigMap{}, types.NamespacedName{Name: ValidatorHAName}); err != nil || !isNotFound {
+		t.Fatalf("handleValidatorFailover: ConfigMap not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// newValidatorHAConfigMap returns the ConfigMap naming the active and the fenced validator pods, read by the validator
+// pods at their start. It is derived from the failover status, which is the state of the failover
+func newValidatorHAConfigMap(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ConfigMap {
+	failover := CRInstance.Status.Failover
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ValidatorHAName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(getValidatorLabels(), CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Data: map[string]string{
+			"activePod": failover.ActivePod,
+			"fencedPod": failover.FencedPod,
+		},
+	}
+}
+
+// getHighAvailabilityEnv returns the environment variables exposing the ConfigMap to the validator pods. Unlike a mounted
+// volume, they are resolved at every start of the container, so a restarted pod never runs with a stale active pod
+func getHighAvailabilityEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		getConfigMapEnvVar(haActivePodEnv, "activePod"),
+		getConfigMapEnvVar(haFencedPodEnv, "fencedPod"),
+	}
+}
+
+func getConfigMapEnvVar(name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ValidatorHAName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func isHighAvailabilityEnabled(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return isRoleDeployed(CRInstance, "validator") && CRInstance.Spec.Validator.HighAvailability.Enabled
+}
+
+func getFailoverDelay(CRInstance *polkadotv1alpha1.Polkadot) time.Duration {
+	if CRInstance.Spec.Validator.HighAvailability.FailoverDelay != nil {
+		return CRInstance.Spec.Validator.HighAvailability.FailoverDelay.Duration
+	}
+	return failoverDelay
+}
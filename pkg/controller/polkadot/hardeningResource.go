@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func isHardened(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return CRInstance.Spec.Hardened
+}
+
+// hardenPodTemplate makes the pods satisfy the restricted Pod Security Standard: they run as a non-root user with the
+// RuntimeDefault seccomp profile, and their containers drop all the capabilities, can't escalate their privileges and
+// have a read-only root filesystem. The data directory, an emptyDir without data volume, and /tmp are the only writable paths
+func hardenPodTemplate(template *corev1.PodTemplateSpec) {
+	// the seccompProfile field is not known by the API version of the operator, the annotation is its equivalent
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[corev1.SeccompPodAnnotationKey] = corev1.SeccompProfileRuntimeDefault
+
+	spec := &template.Spec
+	spec.SecurityContext = getHardenedPodSecurityContext(spec.SecurityContext)
+	spec.Volumes = append(spec.Volumes, getEmptyDirVolume(tmpVolumeName))
+	for i := range spec.Containers {
+		hardenContainer(&spec.Containers[i])
+		if spec.Containers[i].Name == serviceName && !isMountPath(spec.Containers[i].VolumeMounts, volumeMountPath) {
+			spec.Volumes = append(spec.Volumes, getEmptyDirVolume(hardenedDataVolumeName))
+			spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, corev1.VolumeMount{Name: hardenedDataVolumeName, MountPath: volumeMountPath})
+		}
+	}
+	for i := range spec.InitContainers {
+		hardenContainer(&spec.InitContainers[i])
+	}
+}
+
+// getHardenedPodSecurityContext returns the pod security context of the CR, or the default one, running as a non-root user.
+// The root user of the CR is replaced by the default user, the kubelet would not start the containers otherwise
+func getHardenedPodSecurityContext(podSecurityContext *corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	hardened := getPodSecurityContext(podSecurityContext).DeepCopy()
+	runAsNonRoot := true
+	hardened.RunAsNonRoot = &runAsNonRoot
+	if hardened.RunAsUser == nil || *hardened.RunAsUser == 0 {
+		hardened.RunAsUser = getPodSecurityContext(nil).RunAsUser
+	}
+	return hardened
+}
+
+// hardenContainer restricts the security context of the container, overriding the one of the CR except the capabilities
+// it adds which are allowed by the restricted profile (NET_BIND_SERVICE only). The security context and the volume mounts
+// are copied, as they may be shared with the CR
+func hardenContainer(container *corev1.Container) {
+	securityContext := &corev1.SecurityContext{}
+	if container.SecurityContext != nil {
+		securityContext = container.SecurityContext.DeepCopy()
+	}
+	container.SecurityContext = securityContext
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := true
+	runAsNonRoot := true
+	securityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+	securityContext.ReadOnlyRootFilesystem = &readOnlyRootFilesystem
+	securityContext.RunAsNonRoot = &runAsNonRoot
+	securityContext.Privileged = nil
+	if securityContext.RunAsUser != nil && *securityContext.RunAsUser == 0 {
+		securityContext.RunAsUser = nil
+	}
+	if securityContext.Capabilities == nil {
+		securityContext.Capabilities = &corev1.Capabilities{}
+	}
+	securityContext.Capabilities.Drop = []corev1.Capability{"ALL"}
+	securityContext.Capabilities.Add = getRestrictedCapabilities(securityContext.Capabilities.Add)
+
+	if !isMountPath(container.VolumeMounts, tmpMountPath) {
+		volumeMounts := append([]corev1.VolumeMount{}, container.VolumeMounts...)
+		container.VolumeMounts = append(volumeMounts, corev1.VolumeMount{Name: tmpVolumeName, MountPath: tmpMountPath})
+	}
+}
+
+// getRestrictedCapabilities filters out the added capabilities not allowed by the restricted profile
+func getRestrictedCapabilities(capabilities []corev1.Capability) []corev1.Capability {
+	var restricted []corev1.Capability
+	for _, capability := range capabilities {
+		if capability == restrictedCapability {
+			restricted = append(restricted, capability)
+		}
+	}
+	return restricted
+}
+
+func isMountPath(volumeMounts []corev1.VolumeMount, mountPath string) bool {
+	for _, volumeMount := range volumeMounts {
+		if volumeMount.MountPath == mountPath {
+			return true
+		}
+	}
+	return false
+}
+
+func getEmptyDirVolume(name string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+)
+
+// pendingActions holds, for each CR, the actions of its reconciliations since its last applied generation
+var pendingActions = struct {
+	sync.Mutex
+	actions map[types.NamespacedName][]string
+}{actions: map[types.NamespacedName][]string{}}
+
+// historyRecorder records the creations, the updates and the deletions of the resources reported by the events of the
+// CRs, so that they are listed as the actions of the generation they apply
+type historyRecorder struct {
+	record.EventRecorder
+}
+
+func newHistoryRecorder(recorder record.EventRecorder) *historyRecorder {
+	return &historyRecorder{EventRecorder: recorder}
+}
+
+func (r *historyRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventType, reason, messageFmt, args...)
+	if reason != "Created" && reason != "Updated" && reason != "Deleted" {
+		return
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return
+	}
+	key := types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+	pendingActions.Lock()
+	defer pendingActions.Unlock()
+	if len(pendingActions.actions[key]) < historyMaxActions {
+		pendingActions.actions[key] = append(pendingActions.actions[key], fmt.Sprintf(messageFmt, args...))
+	}
+}
+
+// takePendingActions returns the actions of the CR since its last applied generation, and clears them
+func takePendingActions(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	key := types.NamespacedName{Namespace: CRInstance.Namespace, Name: CRInstance.Name}
+	pendingActions.Lock()
+	defer pendingActions.Unlock()
+	actions := pendingActions.actions[key]
+	delete(pendingActions.actions, key)
+	return actions
+}
+
+// isNewGenerationApplied tells if the generation of the CR is applied for the first time by the current reconciliation
+func isNewGenerationApplied(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return !CRInstance.Spec.Paused && CRInstance.Generation != CRInstance.Status.ObservedGeneration
+}
+
+// setHistory adds the generation applied by the reconciliation to the history of the status, with the fields of the
+// spec changed since the applied spec annotation and the actions of the reconciliations
+func setHistory(CRInstance *polkadotv1alpha1.Polkadot, now metav1.Time) {
+	appliedGeneration := polkadotv1alpha1.AppliedGeneration{
+		Generation: CRInstance.Generation,
+		Time:       now,
+		Actions:    takePendingActions(CRInstance),
+	}
+	if appliedSpec, isFound := CRInstance.Annotations[appliedSpecAnnotation]; isFound {
+		changedFields, err := getChangedSpecFields(appliedSpec, CRInstance.Spec)
+		if err != nil {
+			// e.g. an annotation edited by hand, the next generation is compared with the annotation stored for this one
+			getLogger(CRInstance).V(warnLevel).Info("Not able to compare the spec with the applied one...", "error", err.Error())
+		}
+		appliedGeneration.ChangedFields = changedFields
+	}
+
+	history := append([]polkadotv1alpha1.AppliedGeneration{appliedGeneration}, CRInstance.Status.History...)
+	if limit := getHistoryLimit(CRInstance); len(history) > limit {
+		history = history[:limit]
+	}
+	CRInstance.Status.History = history
+}
+
+// getChangedSpecFields returns the paths of the fields of the spec differing from the applied one, e.g. "spec.sentry.replicas"
+func getChangedSpecFields(appliedSpec string, spec polkadotv1alpha1.PolkadotSpec) ([]string, error) {
+	var applied, current interface{}
+	if err := json.Unmarshal([]byte(appliedSpec), &applied); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, err
+	}
+	return getChangedFields(applied, current, "spec"), nil
+}
+
+// handleAppliedSpec stores the spec of the applied generation in the annotation of the CR, the reference of the changed
+// fields of the next generation. The role sections of the spec are the ones resolved from the node pools
+func (r *ReconcilerPolkadot) handleAppliedSpec(CRInstance *polkadotv1alpha1.Polkadot) error {
+	logger := getLogger(CRInstance)
+
+	appliedSpec, err := json.Marshal(CRInstance.Spec)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{appliedSpecAnnotation: string(appliedSpec)}},
+	})
+	if err != nil {
+		return err
+	}
+	// only the annotation is patched, the role sections resolved in memory are not written back to the spec
+	patched := &polkadotv1alpha1.Polkadot{ObjectMeta: metav1.ObjectMeta{Name: CRInstance.Name, Namespace: CRInstance.Namespace}}
+	err = r.client.Patch(context.TODO(), patched, client.ConstantPatch(types.MergePatchType, patch))
+	if err != nil {
+		logger.Error(err, "Update Custom Resource Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to store the applied spec: %v", err)
+		return err
+	}
+	return nil
+}
+
+func getHistoryLimit(CRInstance *polkadotv1alpha1.Polkadot) int {
+	if CRInstance.Spec.HistoryLimit == 0 {
+		return defaultHistoryLimit
+	}
+	return int(CRInstance.Spec.HistoryLimit)
+}
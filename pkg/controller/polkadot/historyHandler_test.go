@@ -0,0 +1,76 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"reflect"
+	"testing"
+)
+
+func TestHandleStatusHistory(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// the generation 2 scales the sentries up, the history only keeps the last generation
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.HistoryLimit = 1
+	polkadot.Generation = 2
+	polkadot.Status.ObservedGeneration = 1
+	polkadot.Status.History = []polkadotv1alpha1.AppliedGeneration{{Generation: 1}}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: newHistoryRecorder(record.NewFakeRecorder(10))}
+	if err := reconciler.handleAppliedSpec(polkadot); err != nil {
+		t.Fatalf("handleAppliedSpec: (%v)", err)
+	}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, polkadot); err != nil {
+		t.Fatalf("handleAppliedSpec: (%v)", err)
+	}
+	polkadot.Spec.Sentry.Replicas = 2
+	if err := client.Update(context.TODO(), polkadot); err != nil {
+		t.Fatalf("Update: (%v)", err)
+	}
+	reconciler.recordEvent(polkadot, corev1.EventTypeNormal, "Updated", "Updated the StatefulSet %s", SentrySSName)
+	reconciler.recordEvent(polkadot, corev1.EventTypeNormal, "ReservedPeersUpdated", "Reserved peers updated")
+
+	if err := reconciler.handleStatus(polkadot); err != nil {
+		t.Fatalf("handleStatus: (%v)", err)
+	}
+	expected := polkadotv1alpha1.AppliedGeneration{
+		Generation:    2,
+		Time:          polkadot.Status.History[0].Time,
+		ChangedFields: []string{"spec.sentry.replicas"},
+		Actions:       []string{"Updated the StatefulSet " + SentrySSName},
+	}
+	if len(polkadot.Status.History) != 1 || !reflect.DeepEqual(polkadot.Status.History[0], expected) {
+		t.Fatalf("handleStatus: unexpected history (%v)", polkadot.Status.History)
+	}
+
+	// the applied spec is the reference of the next generation, which has no action pending
+	found := &polkadotv1alpha1.Polkadot{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, found); err != nil {
+		t.Fatalf("handleStatus: (%v)", err)
+	}
+	found.Generation = 3
+	setHistory(found, polkadot.Status.History[0].Time)
+	if found.Status.History[0].ChangedFields != nil || found.Status.History[0].Actions != nil {
+		t.Fatalf("setHistory: unexpected history (%v)", found.Status.History)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"strings"
+)
+
+// handleImagePinning resolves the client images of the roles to their digests when the image pinning is enabled, and
+// records them in the status, from which the StatefulSets are pinned. The tags are resolved again every refresh period:
+// a new digest is rolled out with autoUpdate, and only reported as availableDigest otherwise. An image which can not be
+// resolved fails the reconciliation, so that the nodes never run an unpinned image
+func (r *ReconcilerPolkadot) handleImagePinning(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+	imagePinning := CRInstance.Spec.Upgrade.ImagePinning
+	if !imagePinning.Enabled {
+		if len(CRInstance.Status.PinnedImages) == 0 {
+			return handleSkip()
+		}
+		return r.updatePinnedImages(CRInstance, nil)
+	}
+
+	refreshPeriod := imageRefreshPeriod
+	if imagePinning.RefreshPeriod != nil {
+		refreshPeriod = imagePinning.RefreshPeriod.Duration
+	}
+	now := metav1.Now()
+	pinnedImages := []polkadotv1alpha1.PinnedImage{}
+	for _, image := range getClientImages(CRInstance) {
+		pinnedImage, isFound := getPinnedImage(CRInstance.Status.PinnedImages, image)
+		if isFound && now.Sub(pinnedImage.ResolvedTime.Time) < refreshPeriod {
+			pinnedImages = append(pinnedImages, pinnedImage)
+			continue
+		}
+		digest, err := resolveImageDigest(image)
+		if err != nil {
+			logger.Error(err, "Error on resolving the digest of the image...", "Image", image)
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "ImageResolutionFailed", "Failed to resolve the digest of the image %s: %v", image, err)
+			if isFound {
+				// the image stays pinned to its previous digest until the registry is reachable again
+				pinnedImages = append(pinnedImages, pinnedImage)
+				continue
+			}
+			return NotForcedRequeue, err
+		}
+		if !isFound {
+			logger.Info("Pinned the image...", "Image", image, "Digest", digest)
+			pinnedImage = polkadotv1alpha1.PinnedImage{Image: image, Digest: digest}
+		} else if digest != pinnedImage.Digest && imagePinning.AutoUpdate {
+			logger.Info("New digest of the image, rolling it out...", "Image", image, "Digest", digest)
+			r.recordEvent(CRInstance, corev1.EventTypeNormal, "ImageUpdated", "The image %s moved to %s, rolling it out", image, digest)
+			pinnedImage.Digest = digest
+			pinnedImage.AvailableDigest = ""
+		} else if digest != pinnedImage.Digest && digest != pinnedImage.AvailableDigest {
+			logger.Info("New digest of the image available...", "Image", image, "Digest", digest)
+			r.recordEvent(CRInstance, corev1.EventTypeNormal, "ImageUpdateAvailable", "The image %s moved to %s, the nodes stay pinned to %s", image, digest, pinnedImage.Digest)
+			pinnedImage.AvailableDigest = digest
+		} else if digest == pinnedImage.Digest {
+			pinnedImage.AvailableDigest = ""
+		}
+		pinnedImage.ResolvedTime = now
+		pinnedImages = append(pinnedImages, pinnedImage)
+	}
+	return r.updatePinnedImages(CRInstance, pinnedImages)
+}
+
+func (r *ReconcilerPolkadot) updatePinnedImages(CRInstance *polkadotv1alpha1.Polkadot, pinnedImages []polkadotv1alpha1.PinnedImage) (bool, error) {
+	if len(pinnedImages) == 0 {
+		pinnedImages = nil
+	}
+	if reflect.DeepEqual(pinnedImages, CRInstance.Status.PinnedImages) {
+		return handleSkip()
+	}
+	logger := getLogger(CRInstance)
+	CRInstance.Status.PinnedImages = pinnedImages
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Updated the pinned images...", "PinnedImages", len(pinnedImages))
+	return NotForcedRequeue, nil
+}
+
+// getClientImages returns the client images of the roles deployed by the CR, without duplicates
+func getClientImages(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	images := []string{}
+	isAdded := map[string]bool{}
+	for _, rr := range getRoleResources(CRInstance) {
+		image := getClientImage(CRInstance, rr.role, rr.options)
+		if isAdded[image] || strings.Contains(image, "@") {
+			continue
+		}
+		isAdded[image] = true
+		images = append(images, image)
+	}
+	return images
+}
+
+func getPinnedImage(pinnedImages []polkadotv1alpha1.PinnedImage, image string) (polkadotv1alpha1.PinnedImage, bool) {
+	for _, pinnedImage := range pinnedImages {
+		if pinnedImage.Image == image {
+			return pinnedImage, true
+		}
+	}
+	return polkadotv1alpha1.PinnedImage{}, false
+}
+
+// pinStatefulSetImages pins the client container of a desired StatefulSet to the digest of its image, the tag is kept
+// in the reference (e.g. "parity/polkadot:latest@sha256:0123...") so that the version stays readable
+func pinStatefulSetImages(CRInstance *polkadotv1alpha1.Polkadot, statefulSet *appsv1.StatefulSet) {
+	if !CRInstance.Spec.Upgrade.ImagePinning.Enabled {
+		return
+	}
+	container := getContainerClientFromStatefulSet(statefulSet)
+	if container == nil {
+		return
+	}
+	if pinnedImage, isFound := getPinnedImage(CRInstance.Status.PinnedImages, container.Image); isFound {
+		container.Image = pinnedImage.Image + "@" + pinnedImage.Digest
+	}
+}
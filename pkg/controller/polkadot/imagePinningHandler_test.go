@@ -0,0 +1,101 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+	"time"
+)
+
+func TestParseImageReference(t *testing.T) {
+
+	tests := []struct {
+		image      string
+		registry   string
+		repository string
+		tag        string
+	}{
+		{"parity/polkadot:v0.8.2", defaultRegistry, "parity/polkadot", "v0.8.2"},
+		{"alpine", defaultRegistry, "library/alpine", "latest"},
+		{"registry.example.com:5000/polkadot", "registry.example.com:5000", "polkadot", "latest"},
+		{"ghcr.io/org/polkadot:v0.8.3", "ghcr.io", "org/polkadot", "v0.8.3"},
+	}
+	for _, test := range tests {
+		registry, repository, tag := parseImageReference(test.image)
+		if registry != test.registry || repository != test.repository || tag != test.tag {
+			t.Errorf("parseImageReference(%s): unexpected reference (%s, %s, %s)", test.image, registry, repository, tag)
+		}
+	}
+}
+
+func TestHandleImagePinning(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	digest := "sha256:0001"
+	defaultResolveImageDigest := resolveImageDigest
+	defer func() { resolveImageDigest = defaultResolveImageDigest }()
+	resolveImageDigest = func(image string) (string, error) {
+		return digest, nil
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.ClientVersion = "latest"
+	polkadot.Spec.Sentry.ClientVersion = "v0.8.3"
+	polkadot.Spec.Upgrade.ImagePinning.Enabled = true
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	if _, err := reconciler.handleImagePinning(polkadot); err != nil {
+		t.Fatalf("handleImagePinning: (%v)", err)
+	}
+	if len(polkadot.Status.PinnedImages) != 2 || polkadot.Status.PinnedImages[0].Digest != digest {
+		t.Fatalf("handleImagePinning: unexpected pinned images (%v)", polkadot.Status.PinnedImages)
+	}
+	validator := newStatefulSetValidator(polkadot)
+	pinStatefulSetImages(polkadot, validator)
+	if image := validator.Spec.Template.Spec.Containers[0].Image; image != polkadot.Status.PinnedImages[1].Image+"@"+digest {
+		t.Fatalf("pinStatefulSetImages: image not pinned (%s)", image)
+	}
+
+	// the tag moves: the new digest is only reported until the autoUpdate is enabled
+	digest = "sha256:0002"
+	for i := range polkadot.Status.PinnedImages {
+		polkadot.Status.PinnedImages[i].ResolvedTime = metav1.NewTime(time.Now().Add(-2 * imageRefreshPeriod))
+	}
+	if _, err := reconciler.handleImagePinning(polkadot); err != nil {
+		t.Fatalf("handleImagePinning: (%v)", err)
+	}
+	pinned := polkadot.Status.PinnedImages[1]
+	if pinned.Digest != "sha256:0001" || pinned.AvailableDigest != digest {
+		t.Fatalf("handleImagePinning: unexpected pinned image (%v)", pinned)
+	}
+
+	polkadot.Spec.Upgrade.ImagePinning.AutoUpdate = true
+	polkadot.Status.PinnedImages[1].ResolvedTime = metav1.NewTime(time.Now().Add(-2 * imageRefreshPeriod))
+	if _, err := reconciler.handleImagePinning(polkadot); err != nil {
+		t.Fatalf("handleImagePinning: (%v)", err)
+	}
+	pinned = polkadot.Status.PinnedImages[1]
+	if pinned.Digest != digest || pinned.AvailableDigest != "" {
+		t.Fatalf("handleImagePinning: new digest not rolled out (%v)", pinned)
+	}
+
+	polkadot.Spec.Upgrade.ImagePinning.Enabled = false
+	if _, err := reconciler.handleImagePinning(polkadot); err != nil {
+		t.Fatalf("handleImagePinning: (%v)", err)
+	}
+	if polkadot.Status.PinnedImages != nil {
+		t.Fatalf("handleImagePinning: pinned images not cleared (%v)", polkadot.Status.PinnedImages)
+	}
+}
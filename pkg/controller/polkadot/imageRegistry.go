@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const registryTimeout = 10 * time.Second
+
+// manifestMediaTypes are the manifests accepted from the registries, the multi-arch indexes first so that the digest of
+// a tag is the one pulled by the nodes of any architecture
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// parseImageReference splits an image (e.g. "parity/polkadot:v0.8.2") into its registry, repository and tag, the
+// images without registry coming from Docker Hub
+func parseImageReference(image string) (string, string, string) {
+	name, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+	registry := defaultRegistry
+	if i := strings.Index(name, "/"); i > 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry, name = host, name[i+1:]
+		}
+	}
+	if registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	return registry, name, tag
+}
+
+// resolveImageDigest returns the digest a tag of an image points to, asking the registry for the manifest anonymously.
+// It is a variable so that the tests can mock the registries
+var resolveImageDigest = func(image string) (string, error) {
+	registry, repository, tag := parseImageReference(image)
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+
+	client := http.Client{Timeout: registryTimeout}
+	response, err := headManifest(client, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode == http.StatusUnauthorized {
+		token, err := getRegistryToken(client, response.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		response, err = headManifest(client, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: unexpected HTTP status %s", image, response.Status)
+	}
+	digest := response.Header.Get("Docker-Content-Digest")
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("%s: no digest returned by the registry", image)
+	}
+	return digest, nil
+}
+
+func headManifest(client http.Client, manifestURL string, token string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	return response, nil
+}
+
+// getRegistryToken gets an anonymous pull token from the authorization service of a registry, as challenged by the
+// WWW-Authenticate header (e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="...")
+func getRegistryToken(client http.Client, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		parts := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(parts) == 2 {
+			params[parts[0]] = strings.Trim(parts[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm in the registry authentication %q", challenge)
+	}
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+
+	response, err := client.Get(params["realm"] + "?" + query.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token: unexpected HTTP status %s", response.Status)
+	}
+	decoded := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&decoded)
+	if err != nil {
+		return "", err
+	}
+	if decoded.Token != "" {
+		return decoded.Token, nil
+	}
+	return decoded.AccessToken, nil
+}
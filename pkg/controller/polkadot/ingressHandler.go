@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handleIngress generates an Ingress for each role enabling it, along with the cert-manager Certificate of its hosts if it
+// references an Issuer. They are removed if it is disabled
+func (r *ReconcilerPolkadot) handleIngress(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	for _, rr := range getRoleResources(CRInstance) {
+		var isForcedRequeue bool
+		var err error
+		if rr.options.Ingress.Enabled != true || rr.options.Ingress.IssuerRef == nil {
+			isForcedRequeue, err = r.handleUnstructuredDisabled(CRInstance, certificateGVK, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handleUnstructuredGeneric(CRInstance, newCertificate(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+
+		if rr.options.Ingress.Enabled != true {
+			isForcedRequeue, err = r.handleIngressDisabled(CRInstance, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handleIngressGeneric(CRInstance, newIngress(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleIngressDisabled deletes the Ingress previously generated for a role
+func (r *ReconcilerPolkadot) handleIngressDisabled(CRInstance *polkadotv1alpha1.Polkadot, name string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Ingress.Name", name)
+
+	foundResource := &networkingv1beta1.Ingress{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Ingress...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Ingress disabled, deleting it...")
+	err = r.deleteResourceWithEvent(CRInstance, foundResource, "Ingress")
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleIngressGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *networkingv1beta1.Ingress) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("Ingress.Name", desiredResource.Name)
+
+	toBeFoundResource := &networkingv1beta1.Ingress{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Ingress...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("Ingress not found...")
+		logger.Info("Creating a new Ingress...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new Ingress...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the Ingress %s: %v", desiredResource.Name, err)
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new Ingress")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the Ingress %s", desiredResource.Name)
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Ingress Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the Ingress %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the Ingress...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the Ingress %s", desiredResource.Name)
+	}
+
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,115 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestHandleIngress(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := networkingv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(RpcNode)
+	polkadot.Spec.RpcNode.Replicas = 2
+	polkadot.Spec.RpcNode.Ingress = polkadotv1alpha1.Ingress{Enabled: true, Host: "rpc.example.com", TLSSecretName: "rpc-tls", ClassName: "nginx"}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleIngress(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleIngress: (%v, %v)", isRequeueForced, err)
+	}
+	ingress := &networkingv1beta1.Ingress{}
+	if _, err := reconciler.fetchResource(ingress, types.NamespacedName{Name: RpcNodeSSName}); err != nil {
+		t.Fatalf("handleIngress: (%v)", err)
+	}
+	if ingress.Annotations[ingressClassAnnotation] != "nginx" || len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "rpc-tls" {
+		t.Fatalf("handleIngress: unexpected Ingress (%v)", ingress)
+	}
+
+	// the WebSocket traffic is routed from the /ws path of the RPC host
+	paths := ingress.Spec.Rules[0].HTTP.Paths
+	if len(ingress.Spec.Rules) != 1 || len(paths) != 2 || paths[0].Path != ingressWSPath || paths[0].Backend.ServicePort.StrVal != WSPortName || paths[1].Backend.ServiceName != ServiceRpcNodeName {
+		t.Fatalf("handleIngress: unexpected rules (%v)", ingress.Spec.Rules)
+	}
+
+	// or from its own host
+	polkadot.Spec.RpcNode.Ingress.WSHost = "ws.example.com"
+	rules := newIngress(polkadot, getRoleResources(polkadot)[0]).Spec.Rules
+	if len(rules) != 2 || rules[1].Host != "ws.example.com" || rules[1].HTTP.Paths[0].Backend.ServicePort.StrVal != WSPortName {
+		t.Fatalf("newIngress: unexpected rules (%v)", rules)
+	}
+
+	// the Ingress is deleted once disabled
+	polkadot.Spec.RpcNode.Ingress.Enabled = false
+	if _, err := reconciler.handleIngress(polkadot); err != nil {
+		t.Fatalf("handleIngress: (%v)", err)
+	}
+	if isNotFound, err := reconciler.fetchResource(&networkingv1beta1.Ingress{}, types.NamespacedName{Name: RpcNodeSSName}); err != nil || !isNotFound {
+		t.Fatalf("handleIngress: Ingress not deleted (%v)", err)
+	}
+}
+
+func TestHandleIngressCertificate(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := networkingv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	scheme.AddKnownTypeWithName(certificateGVK, &unstructured.Unstructured{})
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(RpcNode)
+	polkadot.Spec.RpcNode.Replicas = 2
+	polkadot.Spec.RpcNode.Ingress = polkadotv1alpha1.Ingress{Enabled: true, Host: "rpc.example.com", WSHost: "ws.example.com", IssuerRef: &polkadotv1alpha1.IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"}}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleIngress(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleIngress: (%v, %v)", isRequeueForced, err)
+	}
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if _, err := reconciler.fetchResource(certificate, types.NamespacedName{Name: RpcNodeSSName}); err != nil {
+		t.Fatalf("handleIngress: (%v)", err)
+	}
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+	if secretName != RpcNodeSSName+ingressTLSSuffix || len(dnsNames) != 2 || dnsNames[1] != "ws.example.com" || issuerKind != "ClusterIssuer" {
+		t.Fatalf("handleIngress: unexpected Certificate (%v)", certificate.Object)
+	}
+
+	// the issued Secret terminates the TLS of the Ingress
+	tls := newIngress(polkadot, getRoleResources(polkadot)[0]).Spec.TLS
+	if len(tls) != 1 || tls[0].SecretName != secretName || len(tls[0].Hosts) != 2 {
+		t.Fatalf("newIngress: unexpected TLS (%v)", tls)
+	}
+
+	// the Certificate is deleted once the Issuer is removed
+	polkadot.Spec.RpcNode.Ingress.IssuerRef = nil
+	if _, err := reconciler.handleIngress(polkadot); err != nil {
+		t.Fatalf("handleIngress: (%v)", err)
+	}
+	if isNotFound, err := reconciler.fetchResource(certificate, types.NamespacedName{Name: RpcNodeSSName}); err != nil || !isNotFound {
+		t.Fatalf("handleIngress: Certificate not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// the cert-manager Certificate is not part of the scheme of the operator
+var certificateGVK = schema.GroupVersionKind{Group: certManagerAPIGroup, Version: "v1", Kind: "Certificate"}
+
+// newIngress returns the Ingress of the role, named after its StatefulSet, routing the RPC and the WebSocket traffic to
+// the ports of its Service. The WebSocket endpoint is served on its own host if set, or on the /ws path of the RPC host
+func newIngress(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *networkingv1beta1.Ingress {
+	ingress := rr.options.Ingress
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	annotations := getCopyLabelsWithCustom(ingress.Annotations, CRInstance.Spec.Metadata.Annotations)
+	if ingress.ClassName != "" {
+		annotations[ingressClassAnnotation] = ingress.ClassName
+	}
+	// external-dns publishes the hosts of the rules of the Ingress
+	annotations = getExternalDNSAnnotations(annotations, rr.options.ExternalDNS, "")
+
+	rules := []networkingv1beta1.IngressRule{}
+	if ingress.WSHost == "" {
+		rules = append(rules, getIngressRule(ingress.Host, rr.serviceName, map[string]string{ingressWSPath: WSPortName, "/": RPCPortName}))
+	} else {
+		rules = append(rules,
+			getIngressRule(ingress.Host, rr.serviceName, map[string]string{"/": RPCPortName}),
+			getIngressRule(ingress.WSHost, rr.serviceName, map[string]string{"/": WSPortName}))
+	}
+
+	result := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rr.statefulSetName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: rules,
+		},
+	}
+	if secretName := getIngressTLSSecretName(rr); secretName != "" {
+		result.Spec.TLS = []networkingv1beta1.IngressTLS{{Hosts: getIngressHosts(ingress), SecretName: secretName}}
+	}
+	return result
+}
+
+// newCertificate returns the cert-manager Certificate of the hosts of the Ingress of the role, named after its StatefulSet.
+// cert-manager issues it into the Secret terminating the TLS of the Ingress, and renews it before its expiry
+func newCertificate(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *unstructured.Unstructured {
+	ingress := rr.options.Ingress
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	kind := ingress.IssuerRef.Kind
+	if kind == "" {
+		kind = issuerKind
+	}
+	dnsNames := []interface{}{}
+	for _, host := range getIngressHosts(ingress) {
+		dnsNames = append(dnsNames, host)
+	}
+
+	spec := map[string]interface{}{
+		"secretName": getIngressTLSSecretName(rr),
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]interface{}{
+			"name":  ingress.IssuerRef.Name,
+			"kind":  kind,
+			"group": certManagerAPIGroup,
+		},
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(rr.statefulSetName)
+	certificate.SetNamespace(CRInstance.Namespace)
+	certificate.SetLabels(getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels))
+	certificate.SetAnnotations(getCopy(CRInstance.Spec.Metadata.Annotations))
+	return certificate
+}
+
+// getIngressTLSSecretName returns the Secret terminating the TLS of the Ingress, the one of the Certificate defaults to
+// <StatefulSet name>-tls. It is empty if the TLS is not terminated by the ingress controller
+func getIngressTLSSecretName(rr roleResources) string {
+	ingress := rr.options.Ingress
+	if ingress.TLSSecretName == "" && ingress.IssuerRef != nil {
+		return rr.statefulSetName + ingressTLSSuffix
+	}
+	return ingress.TLSSecretName
+}
+
+func getIngressHosts(ingress polkadotv1alpha1.Ingress) []string {
+	hosts := []string{ingress.Host}
+	if ingress.WSHost != "" {
+		hosts = append(hosts, ingress.WSHost)
+	}
+	return hosts
+}
+
+// getIngressRule routes the paths of the host to the named ports of the Service, the longest paths first
+func getIngressRule(host, serviceName string, portNames map[string]string) networkingv1beta1.IngressRule {
+	paths := []networkingv1beta1.HTTPIngressPath{}
+	for _, path := range []string{ingressWSPath, "/"} {
+		portName, isFound := portNames[path]
+		if !isFound {
+			continue
+		}
+		paths = append(paths, networkingv1beta1.HTTPIngressPath{
+			Path: path,
+			Backend: networkingv1beta1.IngressBackend{
+				ServiceName: serviceName,
+				ServicePort: intstr.FromString(portName),
+			},
+		})
+	}
+	return networkingv1beta1.IngressRule{
+		Host: host,
+		IngressRuleValue: networkingv1beta1.IngressRuleValue{
+			HTTP: &networkingv1beta1.HTTPIngressRuleValue{Paths: paths},
+		},
+	}
+}
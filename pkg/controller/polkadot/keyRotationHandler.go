@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+// handleKeyRotation rotates the session keys of the validator when requested by the CR and,
+// if configured, keeps the managed Secret in sync with the result of the last rotation
+func (r *ReconcilerPolkadot) handleKeyRotation(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if isKeyRotationRequested(CRInstance) {
+		isForcedRequeue, err := r.rotateSessionKeys(CRInstance)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	if CRInstance.Spec.Validator.KeyRotation.SecretName == "" || CRInstance.Status.SessionKeys == nil {
+		return handleSkip()
+	}
+	return r.handleSessionKeysSecretGeneric(CRInstance, newSessionKeysSecret(CRInstance))
+}
+
+// rotateSessionKeys rotates the session keys of the validator through its RPC endpoint.
+// The new public keys are recorded in the status and announced by an event, as they still have to be registered on chain with session.setKeys
+func (r *ReconcilerPolkadot) rotateSessionKeys(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	foundResource := &appsv1.StatefulSet{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: ValidatorSSName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the StatefulSet...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || foundResource.Status.ReadyReplicas < 1 {
+		logger.Info("Validator not ready, postponing the session keys rotation...")
+		return handleSkip()
+	}
+
+	logger.Info("Rotating the session keys of the validator...")
+	publicKeys := ""
+	err = callNodeRPC(getServiceRPCEndpoint(ServiceValidatorName, CRInstance.Namespace, getPorts(CRInstance).RPC), "author_rotateKeys", &publicKeys)
+	if err != nil {
+		logger.Error(err, "Error on rotating the session keys...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "SessionKeysRotationFailed", "%v", err)
+		return NotForcedRequeue, err
+	}
+
+	now := metav1.Now()
+	CRInstance.Status.SessionKeys = &polkadotv1alpha1.SessionKeysStatus{
+		PublicKeys:   publicKeys,
+		Trigger:      CRInstance.Spec.Validator.KeyRotation.Trigger,
+		RotationTime: &now,
+	}
+	err = r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Rotated the session keys of the validator", "SessionKeys", publicKeys)
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "SessionKeysRotated", "New session keys of the validator, submit session.setKeys with %s", publicKeys)
+
+	return NotForcedRequeue, nil
+}
+
+func isKeyRotationRequested(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	if !isRoleDeployed(CRInstance, "validator") {
+		return false
+	}
+	keyRotation := CRInstance.Spec.Validator.KeyRotation
+	sessionKeys := CRInstance.Status.SessionKeys
+	if sessionKeys == nil {
+		return keyRotation.OnFirstStart || keyRotation.Trigger != ""
+	}
+	return keyRotation.Trigger != "" && keyRotation.Trigger != sessionKeys.Trigger
+}
+
+func (r *ReconcilerPolkadot) handleSessionKeysSecretGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *corev1.Secret) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("Secret.Name", desiredResource.Name)
+
+	toBeFoundResource := &corev1.Secret{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Secret...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("Secret not found...")
+		logger.Info("Creating a new Secret...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new Secret...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new Secret")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Secret Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the Secret...")
+	}
+
+	return NotForcedRequeue, nil
+}
+
+// newSessionKeysSecret returns the Secret holding the result of the last session keys rotation, for the automation submitting session.setKeys
+func newSessionKeysSecret(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Secret {
+	sessionKeys := CRInstance.Status.SessionKeys
+	rotationTime := ""
+	if sessionKeys.RotationTime != nil {
+		rotationTime = sessionKeys.RotationTime.UTC().Format(time.RFC3339)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        CRInstance.Spec.Validator.KeyRotation.SecretName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(getValidatorLabels(), CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Data: map[string][]byte{
+			"publicKeys":   []byte(sessionKeys.PublicKeys),
+			"trigger":      []byte(sessionKeys.Trigger),
+			"rotationTime": []byte(rotationTime),
+		},
+	}
+}
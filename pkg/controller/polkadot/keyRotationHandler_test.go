@@ -0,0 +1,181 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"testing"
+)
+
+func TestHandleKeyRotation(t *testing.T) {
+
+	const publicKeys = "0x9257c7a88f94f858a6f477743b4180f0c9a0630a1cea85c3f47dc6ca78e2f40e"
+
+	tests := []struct {
+		name          string
+		keyRotation   polkadotv1alpha1.KeyRotation
+		sessionKeys   *polkadotv1alpha1.SessionKeysStatus
+		readyReplicas int32
+		isRotated     bool
+	}{
+		{
+			name:          "Rotation not requested",
+			keyRotation:   polkadotv1alpha1.KeyRotation{},
+			readyReplicas: 1,
+			isRotated:     false,
+		},
+		{
+			name:          "Rotation on first start",
+			keyRotation:   polkadotv1alpha1.KeyRotation{OnFirstStart: true},
+			readyReplicas: 1,
+			isRotated:     true,
+		},
+		{
+			name:          "Rotation on first start, validator not ready",
+			keyRotation:   polkadotv1alpha1.KeyRotation{OnFirstStart: true},
+			readyReplicas: 0,
+			isRotated:     false,
+		},
+		{
+			name:          "Rotation already done",
+			keyRotation:   polkadotv1alpha1.KeyRotation{OnFirstStart: true, Trigger: "2020-06-01"},
+			sessionKeys:   &polkadotv1alpha1.SessionKeysStatus{PublicKeys: "0x00", Trigger: "2020-06-01"},
+			readyReplicas: 1,
+			isRotated:     false,
+		},
+		{
+			name:          "Rotation on demand",
+			keyRotation:   polkadotv1alpha1.KeyRotation{Trigger: "2020-07-01"},
+			sessionKeys:   &polkadotv1alpha1.SessionKeysStatus{PublicKeys: "0x00", Trigger: "2020-06-01"},
+			readyReplicas: 1,
+			isRotated:     true,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		if method != "author_rotateKeys" {
+			t.Fatalf("handleKeyRotation: unexpected RPC method (%v)", method)
+		}
+		return json.Unmarshal([]byte(`"`+publicKeys+`"`), result)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A Polkadot object with metadata and spec.
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(Validator)
+			polkadot.Spec.Validator.KeyRotation = test.keyRotation
+			polkadot.Status.SessionKeys = test.sessionKeys
+
+			validator := getFakeStatefulSet(ValidatorSSName, 1)
+			validator.Status.ReadyReplicas = test.readyReplicas
+
+			// Create a fake client to mock API calls.
+			client := newFakeClient(scheme, polkadot, validator)
+			recorder := record.NewFakeRecorder(1)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+			isRequeueForced, err := reconciler.handleKeyRotation(polkadot)
+			if isRequeueForced || err != nil {
+				t.Fatalf("handleKeyRotation: (%v, %v)", isRequeueForced, err)
+			}
+
+			found := &polkadotv1alpha1.Polkadot{}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: CRName}, found)
+			if err != nil {
+				t.Fatalf("handleKeyRotation: (%v)", err)
+			}
+			isRotated := found.Status.SessionKeys != nil && found.Status.SessionKeys.PublicKeys == publicKeys
+			if isRotated != test.isRotated {
+				t.Fatalf("handleKeyRotation: unexpected rotation (%v)", found.Status.SessionKeys)
+			}
+			if isRotated && len(recorder.Events) != 1 {
+				t.Fatalf("handleKeyRotation: rotation event not emitted")
+			}
+		})
+	}
+}
+
+func TestHandleSessionKeysSecret(t *testing.T) {
+
+	const secretName = "polkadot-session-keys"
+
+	tests := []struct {
+		name               string
+		objs               []runtime.Object
+		expectedPublicKeys string
+		isRequeueForced    bool
+	}{
+		{
+			name:               "Secret not yet created",
+			objs:               []runtime.Object{},
+			expectedPublicKeys: "0x01",
+			isRequeueForced:    true,
+		},
+		{
+			name: "Secret outdated",
+			objs: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretName},
+				Data:       map[string][]byte{"publicKeys": []byte("0x00")},
+			}},
+			expectedPublicKeys: "0x01",
+			isRequeueForced:    false,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A Polkadot object with metadata and spec.
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(Validator)
+			polkadot.Spec.Validator.KeyRotation = polkadotv1alpha1.KeyRotation{Trigger: "2020-07-01", SecretName: secretName}
+			polkadot.Status.SessionKeys = &polkadotv1alpha1.SessionKeysStatus{PublicKeys: "0x01", Trigger: "2020-07-01"}
+
+			// Objects to track in the fake client.
+			objs := append([]runtime.Object{polkadot}, test.objs...)
+
+			// Create a fake client to mock API calls.
+			client := newFakeClient(scheme, objs...)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+			isRequeueForced, err := reconciler.handleKeyRotation(polkadot)
+			if isRequeueForced != test.isRequeueForced || err != nil {
+				t.Fatalf("handleKeyRotation: (%v, %v)", isRequeueForced, err)
+			}
+
+			found := &corev1.Secret{}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: secretName}, found)
+			if err != nil {
+				t.Fatalf("handleKeyRotation: (%v)", err)
+			}
+			if string(found.Data["publicKeys"]) != test.expectedPublicKeys {
+				t.Fatalf("handleKeyRotation: unexpected session keys Secret (%v)", found.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handleValidatorLeaderElection generates the RBAC resources of the elector sidecars, reports the holder of the Lease as the
+// active validator, and enforces that at most one validator pod runs with the validator role at a time
+func (r *ReconcilerPolkadot) handleValidatorLeaderElection(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if CRInstance.Spec.Validator.ServiceAccountName == "" {
+		isForcedRequeue, err := r.handleServiceAccountGeneric(CRInstance, newLeaderElectionServiceAccount(CRInstance))
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	isForcedRequeue, err := r.handleRoleGeneric(CRInstance, newLeaderElectionRole(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	isForcedRequeue, err = r.handleRoleBindingGeneric(CRInstance, newLeaderElectionRoleBinding(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+
+	leader, err := r.getValidatorLeader(CRInstance)
+	if err != nil || leader == "" {
+		return NotForcedRequeue, err
+	}
+	if failover := CRInstance.Status.Failover; failover.ActivePod != leader {
+		getLogger(CRInstance).Info("New leader elected among the validators...", "Pod.Name", leader)
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "LeaderElected", "The validator %s holds the Lease %s, it is now the active one", leader, ValidatorLeaseName)
+		failover.ActivePod = leader
+		err = r.updateFailoverStatus(CRInstance)
+		if err != nil {
+			return NotForcedRequeue, err
+		}
+	}
+	return r.fenceValidators(CRInstance, leader)
+}
+
+// getValidatorLeader returns the holder of the Lease of the validators, empty if no pod has acquired it yet
+func (r *ReconcilerPolkadot) getValidatorLeader(CRInstance *polkadotv1alpha1.Polkadot) (string, error) {
+	logger := getLogger(CRInstance).WithValues("Lease.Name", ValidatorLeaseName)
+
+	lease := &coordinationv1.Lease{}
+	isNotFound, err := r.fetchResource(lease, types.NamespacedName{Name: ValidatorLeaseName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Lease...")
+		return "", err
+	}
+	if isNotFound || lease.Spec.HolderIdentity == nil {
+		logger.Info("Lease not acquired yet by the validators...")
+		return "", nil
+	}
+	return *lease.Spec.HolderIdentity, nil
+}
+
+// fenceValidators restarts the validator pods running with the validator role without holding the Lease, when another
+// pod also runs with it (e.g. a partitioned pod not aware yet of the loss of its leadership). The roles are queried
+// through the system_nodeRoles RPC method, the pods not reachable by the operator are ignored
+func (r *ReconcilerPolkadot) fenceValidators(CRInstance *polkadotv1alpha1.Polkadot, leader string) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	authorities := []*corev1.Pod{}
+	for ordinal := int32(0); ordinal < validatorHAReplicas; ordinal++ {
+		pod, _, err := r.getValidatorPod(CRInstance, getValidatorPodName(ordinal))
+		if err != nil {
+			return NotForcedRequeue, err
+		}
+		if pod == nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		roles := []string{}
+		err = callNodeRPC(getPodRPCEndpoint(pod), "system_nodeRoles", &roles)
+		if err != nil {
+			logger.V(warnLevel).Info("Not able to get the roles of the validator...", "Pod.Name", pod.Name, "error", err.Error())
+			continue
+		}
+		for _, role := range roles {
+			if role == authorityRole {
+				authorities = append(authorities, pod)
+			}
+		}
+	}
+	if len(authorities) < 2 {
+		return NotForcedRequeue, nil
+	}
+
+	for _, pod := range authorities {
+		if pod.Name == leader {
+			continue
+		}
+		logger.Info("Validator running with the validator role without the leadership, fencing it...", "Pod.Name", pod.Name)
+		err := r.deleteResource(pod)
+		if err != nil {
+			logger.Error(err, "Error on deleting the fenced validator...", "Pod.Name", pod.Name)
+			return NotForcedRequeue, err
+		}
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "ValidatorFenced", "The validator %s was running with the validator role while %s holds the Lease %s, restarting it", pod.Name, leader, ValidatorLeaseName)
+	}
+	return ForcedRequeue, nil
+}
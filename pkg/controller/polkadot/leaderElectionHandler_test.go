@@ -0,0 +1,90 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestHandleValidatorLeaderElection(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := coordinationv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// both pods run with the validator role, e.g. the previous leader is partitioned
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		return json.Unmarshal([]byte(`["Authority"]`), result)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "keystore"}
+	polkadot.Spec.Validator.HighAvailability.Enabled = true
+	polkadot.Spec.Validator.HighAvailability.LeaderElection.Enabled = true
+
+	leader := getValidatorPodName(1)
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: ValidatorLeaseName}, Spec: coordinationv1.LeaseSpec{HolderIdentity: &leader}}
+	objs := []runtime.Object{polkadot, lease}
+	for _, name := range []string{getValidatorPodName(0), leader} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.PodIP = "10.0.0.1"
+		objs = append(objs, pod)
+	}
+
+	client := newFakeClient(scheme, objs...)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the RBAC resources of the elector sidecars are created first
+	for i := 0; i < 3; i++ {
+		isRequeueForced, err := reconciler.handleValidatorFailover(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleValidatorFailover: (%v, %v)", isRequeueForced, err)
+		}
+	}
+	binding := &rbacv1.RoleBinding{}
+	if _, err := reconciler.fetchResource(binding, types.NamespacedName{Name: leaderElectionName}); err != nil || binding.Subjects[0].Name != leaderElectionName {
+		t.Fatalf("handleValidatorFailover: unexpected RoleBinding (%v)", binding.Subjects)
+	}
+
+	// the holder of the Lease is the active validator, the other one is fenced
+	isRequeueForced, err := reconciler.handleValidatorFailover(polkadot)
+	if !isRequeueForced || err != nil || polkadot.Status.Failover.ActivePod != leader {
+		t.Fatalf("handleValidatorFailover: (%v, %v, %v)", isRequeueForced, err, polkadot.Status.Failover)
+	}
+	if isNotFound, err := reconciler.fetchResource(&corev1.Pod{}, types.NamespacedName{Name: getValidatorPodName(0)}); err != nil || !isNotFound {
+		t.Fatalf("handleValidatorFailover: validator not fenced (%v)", err)
+	}
+	if isNotFound, err := reconciler.fetchResource(&corev1.Pod{}, types.NamespacedName{Name: leader}); err != nil || isNotFound {
+		t.Fatalf("handleValidatorFailover: leader fenced (%v)", err)
+	}
+
+	// the client swaps with the leadership flag of the elector sidecar
+	statefulSet := newStatefulSetValidator(polkadot)
+	podSpec := statefulSet.Spec.Template.Spec
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != leaderElectionName || podSpec.ServiceAccountName != leaderElectionName {
+		t.Fatalf("newStatefulSetValidator: elector sidecar not injected (%v)", podSpec.Containers)
+	}
+	if len(podSpec.Volumes) != 2 || podSpec.Volumes[1].Name != leaderVolumeName || len(podSpec.Containers[0].Env) != 0 {
+		t.Fatalf("newStatefulSetValidator: unexpected pod spec (%v)", podSpec)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+func newLeaderElectionServiceAccount(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: getLeaderElectionObjectMeta(CRInstance),
+	}
+}
+
+// newLeaderElectionRole allows the elector sidecars of the validator pods to acquire and renew the Lease of the leadership
+func newLeaderElectionRole(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: getLeaderElectionObjectMeta(CRInstance),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{coordinationv1.GroupName},
+				Resources: []string{"leases"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups:     []string{coordinationv1.GroupName},
+				Resources:     []string{"leases"},
+				ResourceNames: []string{ValidatorLeaseName},
+				Verbs:         []string{"get", "patch", "update"},
+			},
+		},
+	}
+}
+
+func newLeaderElectionRoleBinding(CRInstance *polkadotv1alpha1.Polkadot) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: getLeaderElectionObjectMeta(CRInstance),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      getLeaderElectionServiceAccountName(CRInstance),
+			Namespace: CRInstance.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     leaderElectionName,
+		},
+	}
+}
+
+// getLeaderElectorContainer returns the sidecar electing the active validator: it acquires the Lease once it is expired,
+// renews it while it holds it, and flags the leadership in the volume shared with the client as long as the last renewal
+// is within two thirds of the lease duration. A leader not able to renew the Lease steps down before it expires
+func getLeaderElectorContainer(CRInstance *polkadotv1alpha1.Polkadot) corev1.Container {
+	image := CRInstance.Spec.Validator.HighAvailability.LeaderElection.Image
+	if image == "" {
+		image = leaderElectionImage
+	}
+	duration := int64(getLeaseDuration(CRInstance).Seconds())
+	spec := `"spec":{"holderIdentity":"%s","leaseDurationSeconds":` + fmt.Sprint(duration) + `,"renewTime":"%s"}`
+
+	script := "renewed=0; " +
+		"while true; do " +
+		"now=$(date -u +%s); time=$(date -u +%Y-%m-%dT%H:%M:%S.000000Z); " +
+		"set -- $(kubectl get lease " + ValidatorLeaseName + " -o jsonpath='{.metadata.resourceVersion} {.spec.holderIdentity} {.spec.renewTime}' 2>/dev/null); " +
+		`if [ $# -eq 0 ]; then ` +
+		`printf '{"apiVersion":"coordination.k8s.io/v1","kind":"Lease","metadata":{"name":"` + ValidatorLeaseName + `"},` + spec + `}' "$HOSTNAME" "$time" | kubectl create -f - && renewed=$now; ` +
+		`elif [ "$2" = "$HOSTNAME" ] || [ $((now - $(date -u -d "$3" +%s))) -ge ` + fmt.Sprint(duration) + ` ]; then ` +
+		`kubectl patch lease ` + ValidatorLeaseName + ` --type merge -p "$(printf '{"metadata":{"resourceVersion":"%s"},` + spec + `}' "$1" "$HOSTNAME" "$time")" && renewed=$now; ` +
+		"fi; " +
+		fmt.Sprintf("if [ $((now - renewed)) -lt %d ]; then touch %s/active; else rm -f %s/active; fi; ", duration*2/3, leaderMountPath, leaderMountPath) +
+		fmt.Sprintf("sleep %d; ", duration/3) +
+		"done"
+
+	return corev1.Container{
+		Name:    leaderElectionName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", script},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      leaderVolumeName,
+			MountPath: leaderMountPath,
+		}},
+	}
+}
+
+// getCommandsWithLeadership wraps the client commands in a shell running the validator while the pod holds the leadership,
+// and the standby node otherwise. The running node is stopped and swapped as soon as the leadership flag changes
+func getCommandsWithLeadership(activeCommands, standbyCommands []string) []string {
+	leader := leaderMountPath + "/active"
+	script := "trap 'kill $pid; wait $pid; exit 0' TERM INT; " +
+		"while true; do " +
+		"if [ -f " + leader + " ]; then active=1; " + getShellQuoted(activeCommands) + " & " +
+		"else active=0; " + getShellQuoted(standbyCommands) + " & fi; " +
+		"pid=$!; " +
+		"while kill -0 $pid 2>/dev/null; do " +
+		"if [ -f " + leader + " ]; then leader=1; else leader=0; fi; " +
+		`[ "$leader" = "$active" ] || break; sleep 1; done; ` +
+		"kill $pid 2>/dev/null; wait $pid; sleep 1; " +
+		"done"
+	return []string{"/bin/sh", "-c", script}
+}
+
+func getLeaderVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: leaderVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func getLeaderElectionObjectMeta(CRInstance *polkadotv1alpha1.Polkadot) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        leaderElectionName,
+		Namespace:   CRInstance.Namespace,
+		Labels:      getCopyLabelsWithCustom(getValidatorLabels(), CRInstance.Spec.Metadata.Labels),
+		Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+	}
+}
+
+// getLeaderElectionServiceAccountName returns the ServiceAccount of the validator pods, the one created for the leader election
+// if the validator has no serviceAccountName
+func getLeaderElectionServiceAccountName(CRInstance *polkadotv1alpha1.Polkadot) string {
+	if CRInstance.Spec.Validator.ServiceAccountName != "" {
+		return CRInstance.Spec.Validator.ServiceAccountName
+	}
+	return leaderElectionName
+}
+
+func isLeaderElectionEnabled(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return isHighAvailabilityEnabled(CRInstance) && CRInstance.Spec.Validator.HighAvailability.LeaderElection.Enabled
+}
+
+func getLeaseDuration(CRInstance *polkadotv1alpha1.Polkadot) time.Duration {
+	if CRInstance.Spec.Validator.HighAvailability.LeaderElection.LeaseDuration != nil {
+		return CRInstance.Spec.Validator.HighAvailability.LeaderElection.LeaseDuration.Duration
+	}
+	return leaseDuration
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sync"
+)
+
+// the verbosity levels of the logs, the zap logger of the operator mapping them to its debug and warn levels
+const (
+	debugLevel = 1
+	warnLevel  = -1
+)
+
+// reconcileLoggers holds the logger of the ongoing reconciliation of each CR, a CR being never reconciled concurrently
+var reconcileLoggers sync.Map
+
+// newReconcileLogger returns the logger of a new reconciliation of the CR, every line carrying the name and the
+// namespace of the CR and the ID of the reconciliation. It is returned by getLogger until it is released
+func newReconcileLogger(key types.NamespacedName) (logger logr.Logger, release func()) {
+	logger = log.WithValues("Polkadot.Namespace", key.Namespace, "Polkadot.Name", key.Name, "ReconcileID", rand.String(8))
+	reconcileLoggers.Store(key, logger)
+	return logger, func() { reconcileLoggers.Delete(key) }
+}
+
+// getLogger returns the logger of the ongoing reconciliation of the CR
+func getLogger(CRInstance metav1.Object) logr.Logger {
+	return getRequestLogger(types.NamespacedName{Namespace: CRInstance.GetNamespace(), Name: CRInstance.GetName()})
+}
+
+// getRequestLogger returns the logger of the ongoing reconciliation of the request, a logger carrying the name and the
+// namespace of the CR outside of a reconciliation (e.g. in the unit tests)
+func getRequestLogger(key types.NamespacedName) logr.Logger {
+	if logger, ok := reconcileLoggers.Load(key); ok {
+		return logger.(logr.Logger)
+	}
+	return log.WithValues("Polkadot.Namespace", key.Namespace, "Polkadot.Name", key.Name)
+}
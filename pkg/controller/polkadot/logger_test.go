@@ -0,0 +1,24 @@
+package polkadot
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestReconcileLogger(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	key := types.NamespacedName{Namespace: polkadot.Namespace, Name: polkadot.Name}
+
+	// the handlers log with the logger of the ongoing reconciliation of the CR
+	logger, release := newReconcileLogger(key)
+	if getLogger(polkadot) != logger {
+		t.Fatalf("getLogger: the reconcile logger is not returned")
+	}
+
+	// the logger is released at the end of the reconciliation
+	release()
+	if getLogger(polkadot) == logger {
+		t.Fatalf("getLogger: the reconcile logger is not released")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+)
+
+// The metrics of the reconciliation of each CustomResource, served by the operator together with the controller-runtime ones
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "polkadot_operator_reconcile_total",
+		Help: "Total number of reconciliations per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "polkadot_operator_reconcile_errors_total",
+		Help: "Total number of failed reconciliations per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "polkadot_operator_reconcile_duration_seconds",
+		Help: "Duration of the reconciliations per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	lastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "polkadot_operator_last_successful_reconcile_timestamp_seconds",
+		Help: "Unix time of the last successful reconciliation per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	validatorUnappliedSlashes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "polkadot_operator_validator_unapplied_slashes",
+		Help: "Number of slashes of reported offences of the validator stash not applied yet, per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	validatorSlashed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "polkadot_operator_validator_slashed",
+		Help: "1 if the validator stash has been slashed in the recent eras, per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+
+	validatorLastSlashEra = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "polkadot_operator_validator_last_slash_era",
+		Help: "Era of the last slash of the validator stash, per Polkadot CustomResource",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileTotal, reconcileErrors, reconcileDuration, lastSuccessfulReconcile)
+	metrics.Registry.MustRegister(validatorUnappliedSlashes, validatorSlashed, validatorLastSlashEra)
+}
+
+func observeReconcile(request reconcile.Request, start time.Time, err error) {
+	reconcileTotal.WithLabelValues(request.Namespace, request.Name).Inc()
+	reconcileDuration.WithLabelValues(request.Namespace, request.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileErrors.WithLabelValues(request.Namespace, request.Name).Inc()
+		return
+	}
+	lastSuccessfulReconcile.WithLabelValues(request.Namespace, request.Name).Set(float64(time.Now().Unix()))
+}
+
+// deleteReconcileMetrics removes the series of a deleted CustomResource
+func deleteReconcileMetrics(request reconcile.Request) {
+	reconcileTotal.DeleteLabelValues(request.Namespace, request.Name)
+	reconcileErrors.DeleteLabelValues(request.Namespace, request.Name)
+	reconcileDuration.DeleteLabelValues(request.Namespace, request.Name)
+	lastSuccessfulReconcile.DeleteLabelValues(request.Namespace, request.Name)
+	deleteSlashMetrics(request.Namespace, request.Name)
+}
+
+// observeSlashes exports the slashes of the validator stash of a CR
+func observeSlashes(namespace, name string, slashes *polkadotv1alpha1.SlashesStatus, slashed bool) {
+	validatorUnappliedSlashes.WithLabelValues(namespace, name).Set(float64(slashes.UnappliedSlashes))
+	validatorSlashed.WithLabelValues(namespace, name).Set(0)
+	if slashed {
+		validatorSlashed.WithLabelValues(namespace, name).Set(1)
+	}
+	validatorLastSlashEra.DeleteLabelValues(namespace, name)
+	if slashes.LastSlashEra != nil {
+		validatorLastSlashEra.WithLabelValues(namespace, name).Set(float64(*slashes.LastSlashEra))
+	}
+}
+
+// deleteSlashMetrics removes the series of the validator stash of a CR
+func deleteSlashMetrics(namespace, name string) {
+	validatorUnappliedSlashes.DeleteLabelValues(namespace, name)
+	validatorSlashed.DeleteLabelValues(namespace, name)
+	validatorLastSlashEra.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,33 @@
+package polkadot
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+	"time"
+)
+
+func TestObserveReconcile(t *testing.T) {
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "metrics", Name: CRName}}
+
+	observeReconcile(request, time.Now(), nil)
+	observeReconcile(request, time.Now(), errors.New("reconcile error"))
+
+	if total := testutil.ToFloat64(reconcileTotal.WithLabelValues("metrics", CRName)); total != 2 {
+		t.Fatalf("observeReconcile: unexpected reconcile total (%v)", total)
+	}
+	if errorsTotal := testutil.ToFloat64(reconcileErrors.WithLabelValues("metrics", CRName)); errorsTotal != 1 {
+		t.Fatalf("observeReconcile: unexpected reconcile errors (%v)", errorsTotal)
+	}
+	if timestamp := testutil.ToFloat64(lastSuccessfulReconcile.WithLabelValues("metrics", CRName)); timestamp == 0 {
+		t.Fatalf("observeReconcile: last successful reconcile not set")
+	}
+
+	deleteReconcileMetrics(request)
+	if total := testutil.ToFloat64(reconcileTotal.WithLabelValues("metrics", CRName)); total != 0 {
+		t.Fatalf("deleteReconcileMetrics: series not deleted (%v)", total)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r *ReconcilerPolkadot) handleMonitoring(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	monitoring := CRInstance.Spec.Monitoring
+	if monitoring.Enabled != true {
+		isForcedRequeue, err := r.handleMonitoringDisabled(CRInstance, &monitoringv1.PodMonitor{}, PodMonitorName)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+		isForcedRequeue, err = r.handleMonitoringDisabled(CRInstance, &monitoringv1.PrometheusRule{}, PrometheusRuleName)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+		return r.handleDashboards(CRInstance)
+	}
+
+	isForcedRequeue, err := r.handlePodMonitorGeneric(CRInstance, newPodMonitor(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	if monitoring.Alerts.Enabled != true {
+		isForcedRequeue, err = r.handleMonitoringDisabled(CRInstance, &monitoringv1.PrometheusRule{}, PrometheusRuleName)
+	} else {
+		isForcedRequeue, err = r.handlePrometheusRuleGeneric(CRInstance, newPrometheusRule(CRInstance))
+	}
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	return r.handleDashboards(CRInstance)
+}
+
+// handleDashboards generates a Grafana dashboard for each role of the CR, they are removed if the monitoring or the dashboards are disabled
+func (r *ReconcilerPolkadot) handleDashboards(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	monitoring := CRInstance.Spec.Monitoring
+	for _, rr := range getRoleResources(CRInstance) {
+		var isForcedRequeue bool
+		var err error
+		if monitoring.Enabled != true || monitoring.GrafanaDashboards != true {
+			isForcedRequeue, err = r.handleMonitoringDisabled(CRInstance, &corev1.ConfigMap{}, getDashboardName(rr.role))
+		} else {
+			isForcedRequeue, err = r.handleConfigMapGeneric(CRInstance, newDashboardConfigMap(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleMonitoringDisabled deletes a Prometheus Operator resource previously generated for the CR
+func (r *ReconcilerPolkadot) handleMonitoringDisabled(CRInstance *polkadotv1alpha1.Polkadot, foundResource metav1.Object, name string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Name", name)
+
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		// the Prometheus Operator may not be installed in the cluster at all
+		logger.V(warnLevel).Info("Not able to fetch the monitoring resource, skipping...", "error", err.Error())
+		return handleSkip()
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Monitoring disabled, deleting the monitoring resource...")
+	err = r.deleteResource(foundResource)
+	if err != nil {
+		logger.Error(err, "Delete monitoring resource Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the monitoring resource")
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handlePodMonitorGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *monitoringv1.PodMonitor) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("PodMonitor.Name", desiredResource.Name)
+
+	toBeFoundResource := &monitoringv1.PodMonitor{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the PodMonitor...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("PodMonitor not found...")
+		logger.Info("Creating a new PodMonitor...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new PodMonitor...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new PodMonitor")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update PodMonitor Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the PodMonitor...")
+	}
+
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handlePrometheusRuleGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *monitoringv1.PrometheusRule) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("PrometheusRule.Name", desiredResource.Name)
+
+	toBeFoundResource := &monitoringv1.PrometheusRule{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the PrometheusRule...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("PrometheusRule not found...")
+		logger.Info("Creating a new PrometheusRule...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new PrometheusRule...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new PrometheusRule")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update PrometheusRule Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the PrometheusRule...")
+	}
+
+	return NotForcedRequeue, nil
+}
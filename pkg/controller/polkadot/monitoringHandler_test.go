@@ -0,0 +1,172 @@
+package polkadot
+
+import (
+	"context"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestHandleMonitoring(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Polkadot object with metadata and spec.
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Monitoring.Enabled = true
+	polkadot.Spec.Monitoring.Interval = "30s"
+	polkadot.Spec.Monitoring.Labels = map[string]string{"release": "prometheus"}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleMonitoring(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+	}
+
+	found := &monitoringv1.PodMonitor{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PodMonitorName}, found)
+	if err != nil {
+		t.Fatalf("handleMonitoring: (%v)", err)
+	}
+	if found.Labels["release"] != "prometheus" || found.Spec.PodMetricsEndpoints[0].Interval != "30s" ||
+		len(found.Spec.Selector.MatchExpressions[0].Values) != 2 {
+		t.Fatalf("handleMonitoring: unexpected PodMonitor (%v)", found)
+	}
+
+	polkadot.Spec.Monitoring.Interval = "1m"
+	isRequeueForced, err = reconciler.handleMonitoring(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PodMonitorName}, found)
+	if err != nil || found.Spec.PodMetricsEndpoints[0].Interval != "1m" {
+		t.Fatalf("handleMonitoring: PodMonitor not updated (%v)", err)
+	}
+
+	polkadot.Spec.Monitoring.Enabled = false
+	isRequeueForced, err = reconciler.handleMonitoring(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PodMonitorName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleMonitoring: PodMonitor not deleted (%v)", err)
+	}
+}
+
+func TestHandleMonitoringAlerts(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Polkadot object with metadata and spec.
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Monitoring.Enabled = true
+	polkadot.Spec.Monitoring.Alerts.Enabled = true
+	polkadot.Spec.Monitoring.Alerts.MinPeers = 5
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the PodMonitor and the PrometheusRule are created one per reconcile
+	for i := 0; i < 2; i++ {
+		isRequeueForced, err := reconciler.handleMonitoring(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+		}
+	}
+
+	found := &monitoringv1.PrometheusRule{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: PrometheusRuleName}, found)
+	if err != nil {
+		t.Fatalf("handleMonitoring: (%v)", err)
+	}
+	rules := found.Spec.Groups[0].Rules
+	if len(rules) != 4 || rules[2].Expr.String() != `polkadot_sync_peers{namespace="",pod=~"(sentry-sset|validator-sset)-[0-9]+"} < 5` {
+		t.Fatalf("handleMonitoring: unexpected PrometheusRule (%v)", rules)
+	}
+
+	polkadot.Spec.Monitoring.Alerts.Enabled = false
+	isRequeueForced, err := reconciler.handleMonitoring(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PrometheusRuleName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleMonitoring: PrometheusRule not deleted (%v)", err)
+	}
+}
+
+func TestHandleMonitoringDashboards(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Polkadot object with metadata and spec.
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Monitoring.Enabled = true
+	polkadot.Spec.Monitoring.GrafanaDashboards = true
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the PodMonitor and the dashboards of the sentry and of the validator are created one per reconcile
+	for i := 0; i < 3; i++ {
+		isRequeueForced, err := reconciler.handleMonitoring(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+		}
+	}
+
+	found := &corev1.ConfigMap{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: getDashboardName("validator")}, found)
+	if err != nil {
+		t.Fatalf("handleMonitoring: (%v)", err)
+	}
+	content := found.Data[getDashboardName("validator")+".json"]
+	if found.Labels[dashboardLabel] != "1" || !strings.Contains(content, `pod=~\"(validator-sset)-[0-9]+\"`) {
+		t.Fatalf("handleMonitoring: unexpected dashboard (%v, %v)", found.Labels, content)
+	}
+
+	polkadot.Spec.Monitoring.GrafanaDashboards = false
+	isRequeueForced, err := reconciler.handleMonitoring(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleMonitoring: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: getDashboardName("validator")}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleMonitoring: dashboard not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"strings"
+)
+
+// getMonitoredRoles returns the roles of the nodes deployed by the CR, the pods of the backup CronJob are not scraped
+func getMonitoredRoles(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	roles := []string{}
+	for _, rr := range getRoleResources(CRInstance) {
+		roles = append(roles, rr.role)
+	}
+	return roles
+}
+
+// newPodMonitor scrapes every pod of the CR, including the RPC nodes which Service does not expose the metrics port
+func newPodMonitor(CRInstance *polkadotv1alpha1.Polkadot) *monitoringv1.PodMonitor {
+	monitoring := CRInstance.Spec.Monitoring
+	labels := getCopyLabelsWithCustom(getCopyLabelsWithCustom(getAppLabels(), CRInstance.Spec.Metadata.Labels), monitoring.Labels)
+	targetPort := intstr.FromInt(int(getPorts(CRInstance).Prometheus))
+	endpoints := []monitoringv1.PodMetricsEndpoint{
+		{
+			TargetPort: &targetPort,
+			Path:       "/metrics",
+			Interval:   monitoring.Interval,
+		},
+	}
+	// the named port of the era exporter is only declared by the validator pods
+	if isEraExporterEnabled(CRInstance) {
+		endpoints = append(endpoints, monitoringv1.PodMetricsEndpoint{
+			Port:     eraExporterPortName,
+			Path:     "/metrics",
+			Interval: monitoring.Interval,
+		})
+	}
+
+	return &monitoringv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        PodMonitorName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      labels,
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Spec: monitoringv1.PodMonitorSpec{
+			PodMetricsEndpoints: endpoints,
+			Selector: metav1.LabelSelector{
+				MatchLabels: getAppLabels(),
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "role",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   getMonitoredRoles(CRInstance),
+					},
+				},
+			},
+			PodTargetLabels: []string{"role"},
+		},
+	}
+}
+
+// getPodsSelector returns the PromQL label matchers of the pods of the given StatefulSets
+func getPodsSelector(namespace string, statefulSetNames []string) string {
+	return fmt.Sprintf(`namespace="%s",pod=~"(%s)-[0-9]+"`, namespace, strings.Join(statefulSetNames, "|"))
+}
+
+// getAlertsSelector returns the PromQL label matchers of the pods of the CR
+func getAlertsSelector(CRInstance *polkadotv1alpha1.Polkadot) string {
+	statefulSetNames := []string{}
+	for _, rr := range getRoleResources(CRInstance) {
+		statefulSetNames = append(statefulSetNames, rr.statefulSetName)
+	}
+	return getPodsSelector(CRInstance.Namespace, statefulSetNames)
+}
+
+func getAlertRule(alert, expr, duration, severity, summary string) monitoringv1.Rule {
+	return monitoringv1.Rule{
+		Alert:       alert,
+		Expr:        intstr.FromString(expr),
+		For:         duration,
+		Labels:      map[string]string{"severity": severity},
+		Annotations: map[string]string{"summary": summary},
+	}
+}
+
+// newPrometheusRule returns the standard health alerts of the nodes, scoped to the pods of the CR
+func newPrometheusRule(CRInstance *polkadotv1alpha1.Polkadot) *monitoringv1.PrometheusRule {
+	monitoring := CRInstance.Spec.Monitoring
+	labels := getCopyLabelsWithCustom(getCopyLabelsWithCustom(getAppLabels(), CRInstance.Spec.Metadata.Labels), monitoring.Labels)
+	selector := getAlertsSelector(CRInstance)
+	minPeers := monitoring.Alerts.MinPeers
+	if minPeers == 0 {
+		minPeers = defaultMinPeers
+	}
+
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        PrometheusRuleName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      labels,
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: fmt.Sprintf("%s.%s.rules", CRInstance.Namespace, CRInstance.Name),
+					Rules: []monitoringv1.Rule{
+						getAlertRule("PolkadotNodeDown",
+							fmt.Sprintf(`up{%s} == 0`, selector),
+							"5m", "critical", "The node {{ $labels.pod }} is down"),
+						getAlertRule("PolkadotFinalizedBlockStalled",
+							fmt.Sprintf(`increase(%s_block_height_number{status="finalized",%s}[10m]) == 0`, metricsPrefix, selector),
+							"5m", "critical", "The node {{ $labels.pod }} did not finalize any block in the last 10 minutes"),
+						getAlertRule("PolkadotLowPeerCount",
+							fmt.Sprintf(`%s_sync_peers{%s} < %d`, metricsPrefix, selector, minPeers),
+							"10m", "warning", fmt.Sprintf("The node {{ $labels.pod }} is connected to less than %d peers", minPeers)),
+						getAlertRule("PolkadotHighBlockImportTime",
+							fmt.Sprintf(`histogram_quantile(0.9, rate(%s_block_verification_and_import_time_bucket{%s}[5m])) > 2`, metricsPrefix, selector),
+							"10m", "warning", "The node {{ $labels.pod }} takes more than 2 seconds to import the blocks"),
+					},
+				},
+			},
+		},
+	}
+}
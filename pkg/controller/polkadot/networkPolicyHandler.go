@@ -4,7 +4,9 @@ package polkadot
 
 import (
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -15,10 +17,7 @@ func (r *ReconcilerPolkadot) handleNetworkPolicy(CRInstance *polkadotv1alpha1.Po
 
 //pattern factory
 func getHandlerNetworkPolicy(CRInstance *polkadotv1alpha1.Polkadot) IHandlerNetworkPolicy {
-	if CRInstance.Spec.SecureCommunicationSupport.Enabled != true {
-		return &handlerNetworkPolicyDefault{}
-	}
-	if CRKind(CRInstance.Spec.Kind) == SentryAndValidator {
+	if isSentryAndValidator(CRInstance) {
 		return &handlerNetworkPolicySentryAndValidator{}
 	}
 	return &handlerNetworkPolicyDefault{}
@@ -38,12 +37,30 @@ func (h *handlerNetworkPolicySentryAndValidator) handleNetworkPolicySpecific(r *
 type handlerNetworkPolicyDefault struct {
 }
 func (h *handlerNetworkPolicyDefault) handleNetworkPolicySpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
-	return handleSkip()
+	return r.handleNetworkPolicyDisabled(CRInstance)
+}
+
+// handleNetworkPolicyDisabled deletes the validator Network Policy, left by a previous Kind
+func (r *ReconcilerPolkadot) handleNetworkPolicyDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("NetworkPolicy.Name", ValidatorNetworkPolicy)
+
+	foundResource := &v1.NetworkPolicy{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: ValidatorNetworkPolicy, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Network Policy...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Network Policy no longer needed, deleting it...")
+	return NotForcedRequeue, r.deleteResourceWithEvent(CRInstance, foundResource, "NetworkPolicy")
 }
 
 func (r *ReconcilerPolkadot) handleNetworkPolicyGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *v1.NetworkPolicy) (bool, error) {
 
-	logger := log.WithValues("Service.Namespace", desiredResource.Namespace, "Service.Name", desiredResource.Name)
+	logger := getLogger(CRInstance).WithValues("Service.Name", desiredResource.Name)
 
 	toBeFoundResource := &v1.NetworkPolicy{}
 	isNotFound,err := r.fetchResource(toBeFoundResource,types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
@@ -52,18 +69,31 @@ func (r *ReconcilerPolkadot) handleNetworkPolicyGeneric(CRInstance *polkadotv1al
 		return NotForcedRequeue, err
 	}
 	if isNotFound == true {
-		logger.Info("Network Policy not found...")
+		logger.V(debugLevel).Info("Network Policy not found...")
 		logger.Info("Creating a new Network Policy...")
-		err := r.createResource(desiredResource, CRInstance)
+		err := r.applyResource(desiredResource, CRInstance)
 		if err != nil {
 			logger.Error(err, "Error on creating a new Network Policy...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the NetworkPolicy %s: %v", desiredResource.Name, err)
 			return NotForcedRequeue, err
 		}
 		logger.Info("Created the new Network Policy")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the NetworkPolicy %s", desiredResource.Name)
 		return ForcedRequeue, nil
 	}
 
-	//TODO add check differences
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Network Policy Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the NetworkPolicy %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the Network Policy...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the NetworkPolicy %s", desiredResource.Name)
+	}
 
 	return NotForcedRequeue, nil
 }
\ No newline at end of file
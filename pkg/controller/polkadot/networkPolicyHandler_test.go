@@ -4,7 +4,6 @@ import (
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -45,7 +44,7 @@ func TestHandleNetworkPolicyGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot,test.newResource}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isRequeueForced, err := reconciler.handleNetworkPolicyGeneric(polkadot,test.newResource)
@@ -61,7 +60,7 @@ func TestHandleNetworkPolicyGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isRequeueForced, err := reconciler.handleNetworkPolicyGeneric(polkadot,test.newResource)
@@ -72,3 +71,41 @@ func TestHandleNetworkPolicyGeneric(t *testing.T) {
 	}
 }
 
+
+func TestNewNetworkPolicyValidator(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+
+	if _, ok := getHandlerNetworkPolicy(polkadot).(*handlerNetworkPolicySentryAndValidator); !ok {
+		t.Fatalf("getHandlerNetworkPolicy: the validator is not isolated without the secure communication support")
+	}
+
+	networkPolicy := newNetworkPolicyValidator(polkadot)
+	ingress := networkPolicy.Spec.Ingress
+	if len(ingress) != 2 || len(ingress[0].Ports) != 1 || len(ingress[1].Ports) != 2 {
+		t.Fatalf("newNetworkPolicyValidator: unexpected ingress (%v)", ingress)
+	}
+	if ingress[0].From[0].PodSelector.MatchLabels["role"] != getSentrylabels()["role"] || ingress[1].From[0].PodSelector.MatchLabels["name"] != operatorName {
+		t.Fatalf("newNetworkPolicyValidator: unexpected ingress peers (%v)", ingress)
+	}
+	if len(networkPolicy.Spec.PolicyTypes) != 1 || networkPolicy.Spec.Egress != nil {
+		t.Fatalf("newNetworkPolicyValidator: unexpected egress (%v)", networkPolicy.Spec)
+	}
+
+	// the metrics port is open, and the egress restricted with the secure communication support
+	polkadot.Spec.MetricsSupport.Enabled = true
+	polkadot.Spec.SecureCommunicationSupport.Enabled = true
+	networkPolicy = newNetworkPolicyValidator(polkadot)
+	if len(networkPolicy.Spec.Ingress) != 3 || networkPolicy.Spec.Ingress[2].From != nil {
+		t.Fatalf("newNetworkPolicyValidator: unexpected ingress (%v)", networkPolicy.Spec.Ingress)
+	}
+	if len(networkPolicy.Spec.PolicyTypes) != 2 || len(networkPolicy.Spec.Egress) != 2 {
+		t.Fatalf("newNetworkPolicyValidator: unexpected egress (%v)", networkPolicy.Spec)
+	}
+
+	polkadot.Spec.Kind = string(Sentry)
+	if _, ok := getHandlerNetworkPolicy(polkadot).(*handlerNetworkPolicyDefault); !ok {
+		t.Fatalf("getHandlerNetworkPolicy: unexpected handler for the Kind Sentry")
+	}
+}
@@ -4,15 +4,52 @@ package polkadot
 
 import (
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// newNetworkPolicyValidator isolates the validator behind the sentry layer: its P2P port is only reachable from the sentry
+// pods, its RPC ports only from the operator pods (in any namespace), and its metrics port from anywhere if the metrics
+// support is enabled, as well as the port of its era exporter. With the secure communication support, the validator is
+// also only allowed to reach the sentries and the DNS
 func newNetworkPolicyValidator(CRInstance *polkadotv1alpha1.Polkadot) *v1.NetworkPolicy {
 	labels := getValidatorLabels()
 	sentryLabels := getSentrylabels()
+	ports := getPorts(CRInstance)
 
-	return &v1.NetworkPolicy{
+	ingress := []v1.NetworkPolicyIngressRule{
+		{
+			Ports: getNetworkPolicyPorts(corev1.ProtocolTCP, int(ports.P2P)),
+			From: []v1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: sentryLabels,
+				},
+			}},
+		},
+		{
+			Ports: getNetworkPolicyPorts(corev1.ProtocolTCP, int(ports.RPC), int(ports.WS)),
+			From: []v1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: getOperatorLabels(),
+				},
+				NamespaceSelector: &metav1.LabelSelector{},
+			}},
+		},
+	}
+	if CRInstance.Spec.MetricsSupport.Enabled {
+		ingress = append(ingress, v1.NetworkPolicyIngressRule{
+			Ports: getNetworkPolicyPorts(corev1.ProtocolTCP, int(ports.Prometheus)),
+		})
+	}
+	if isEraExporterEnabled(CRInstance) {
+		ingress = append(ingress, v1.NetworkPolicyIngressRule{
+			Ports: getNetworkPolicyPorts(corev1.ProtocolTCP, int(getEraExporterPort(CRInstance))),
+		})
+	}
+
+	networkPolicy := &v1.NetworkPolicy{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ValidatorNetworkPolicy,
@@ -22,20 +59,47 @@ func newNetworkPolicyValidator(CRInstance *polkadotv1alpha1.Polkadot) *v1.Networ
 			PodSelector: metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Ingress: []v1.NetworkPolicyIngressRule{{
-				From: []v1.NetworkPolicyPeer{{
-					PodSelector: &metav1.LabelSelector{
-						MatchLabels: sentryLabels,
-					},
-				}},
-			}},
-			Egress: []v1.NetworkPolicyEgressRule{{
+			Ingress:     ingress,
+			PolicyTypes: []v1.PolicyType{v1.PolicyTypeIngress},
+		},
+	}
+
+	if CRInstance.Spec.SecureCommunicationSupport.Enabled {
+		networkPolicy.Spec.PolicyTypes = append(networkPolicy.Spec.PolicyTypes, v1.PolicyTypeEgress)
+		networkPolicy.Spec.Egress = []v1.NetworkPolicyEgressRule{
+			{
 				To: []v1.NetworkPolicyPeer{{
 					PodSelector: &metav1.LabelSelector{
 						MatchLabels: sentryLabels,
 					},
 				}},
-			}},
-		},
+			},
+			{
+				// the sentries are reached through the name of their Service
+				Ports: append(getNetworkPolicyPorts(corev1.ProtocolUDP, dnsPort), getNetworkPolicyPorts(corev1.ProtocolTCP, dnsPort)...),
+			},
+		}
+		// the sentries of other clusters are reached at the P2P ports of their multiaddresses, wherever they are
+		if externalPorts := getMultiaddressesPorts(CRInstance.Spec.Validator.ExternalSentries); len(externalPorts) > 0 {
+			networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, v1.NetworkPolicyEgressRule{
+				Ports: getNetworkPolicyPorts(corev1.ProtocolTCP, externalPorts...),
+			})
+		}
+	}
+	return networkPolicy
+}
+
+func getNetworkPolicyPorts(protocol corev1.Protocol, ports ...int) []v1.NetworkPolicyPort {
+	policyPorts := []v1.NetworkPolicyPort{}
+	for _, port := range ports {
+		policyProtocol := protocol
+		policyPort := intstr.FromInt(port)
+		policyPorts = append(policyPorts, v1.NetworkPolicyPort{Protocol: &policyProtocol, Port: &policyPort})
 	}
+	return policyPorts
+}
+
+// getOperatorLabels returns the labels of the operator pods, see deploy/operator.yaml
+func getOperatorLabels() map[string]string {
+	return map[string]string{"name": operatorName}
 }
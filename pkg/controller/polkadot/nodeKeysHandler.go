@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handleNodeKeys generates the missing node key Secrets of the pods of the roles with generateNodeKeys, before their
+// StatefulSets mount them. An existing Secret is never updated, so that the peer ID of a pod survives its restarts, and
+// the Secrets are kept when the role is scaled down or removed, to give the same peer ID back to a recreated ordinal
+func (r *ReconcilerPolkadot) handleNodeKeys(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	isRequeueForced := NotForcedRequeue
+	for _, rr := range getRoleResources(CRInstance) {
+		for _, name := range getNodeKeySecrets(CRInstance, rr.role, rr.replicas, rr.options) {
+			isCreated, err := r.handleNodeKeySecret(CRInstance, name, rr.role)
+			if err != nil {
+				return NotForcedRequeue, err
+			}
+			isRequeueForced = isRequeueForced || isCreated
+		}
+	}
+	return isRequeueForced, nil
+}
+
+func (r *ReconcilerPolkadot) handleNodeKeySecret(CRInstance *polkadotv1alpha1.Polkadot, name string, role string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Secret.Name", name)
+
+	foundResource := &corev1.Secret{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Secret...")
+		return NotForcedRequeue, err
+	}
+	if !isNotFound {
+		return handleSkip()
+	}
+
+	logger.V(debugLevel).Info("Secret not found...")
+	desiredResource, err := newNodeKeySecret(CRInstance, name, role)
+	if err != nil {
+		logger.Error(err, "Error on generating the node key...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Creating a new Secret...")
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Error on creating a new Secret...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Created the new Secret")
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the node key Secret %s", name)
+	return ForcedRequeue, nil
+}
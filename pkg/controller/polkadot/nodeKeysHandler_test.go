@@ -0,0 +1,76 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"reflect"
+	"testing"
+)
+
+func TestHandleNodeKeys(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("corev1.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 2
+	polkadot.Spec.Sentry.GenerateNodeKeys = true
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: record.NewFakeRecorder(10)}
+
+	isRequeueForced, err := reconciler.handleNodeKeys(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleNodeKeys: (%v, %v)", isRequeueForced, err)
+	}
+	keys := map[string]bool{}
+	for _, name := range []string{"polkadot-cr-sentry-0-nodekey", "polkadot-cr-sentry-1-nodekey"} {
+		secret := &corev1.Secret{}
+		if isNotFound, err := reconciler.fetchResource(secret, types.NamespacedName{Name: name}); isNotFound || err != nil {
+			t.Fatalf("handleNodeKeys: Secret %s not created (%v)", name, err)
+		}
+		if len(secret.Data[nodeKeyFileName]) != 64 || secret.Labels["role"] != "sentry" {
+			t.Fatalf("handleNodeKeys: unexpected Secret (%v)", secret)
+		}
+		keys[string(secret.Data[nodeKeyFileName])] = true
+	}
+	if len(keys) != 2 {
+		t.Fatalf("handleNodeKeys: the pods share their node key")
+	}
+
+	// the existing node keys are kept
+	isRequeueForced, err = reconciler.handleNodeKeys(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleNodeKeys: (%v, %v)", isRequeueForced, err)
+	}
+
+	// each pod copies the node key of its ordinal from the projected Secrets
+	podSpec := newStatefulSetSentry(polkadot).Spec.Template.Spec
+	projected := podSpec.Volumes[0].Projected
+	if podSpec.Volumes[0].Name != nodeKeysVolumeName || len(projected.Sources) != nodeKeysProjectionStep || projected.Sources[1].Secret.Name != "polkadot-cr-sentry-1-nodekey" || projected.Sources[1].Secret.Items[0].Path != "1" {
+		t.Fatalf("newStatefulSetSentry: unexpected volumes (%v)", podSpec.Volumes)
+	}
+	initContainers := podSpec.InitContainers
+	if len(initContainers) == 0 || initContainers[len(initContainers)-1].Name != nodeKeyInitName {
+		t.Fatalf("newStatefulSetSentry: node key init container not found (%v)", initContainers)
+	}
+
+	// scaling the sentries up does not change their pod template, the running pods are not rolled
+	polkadot.Spec.Sentry.Replicas = 5
+	if scaled := newStatefulSetSentry(polkadot).Spec.Template.Spec; !reflect.DeepEqual(scaled.Volumes, podSpec.Volumes) {
+		t.Fatalf("newStatefulSetSentry: volumes changed by the scale up (%v)", scaled.Volumes)
+	}
+	polkadot.Spec.Sentry.Replicas = nodeKeysProjectionStep + 1
+	if scaled := newStatefulSetSentry(polkadot).Spec.Template.Spec; len(scaled.Volumes[0].Projected.Sources) != 2*nodeKeysProjectionStep {
+		t.Fatalf("newStatefulSetSentry: unexpected projected Secrets (%v)", len(scaled.Volumes[0].Projected.Sources))
+	}
+}
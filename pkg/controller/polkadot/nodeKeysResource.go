@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+)
+
+// getNodeKeySecrets returns the names of the Secrets of the generated node keys of a role, one for each ordinal up to its
+// replicas, or up to the maximum replicas of its autoscaling. None if the role does not generate its node keys
+func getNodeKeySecrets(CRInstance *polkadotv1alpha1.Polkadot, role string, replicas int32, options polkadotv1alpha1.NodeOptions) []string {
+	if !options.GenerateNodeKeys {
+		return nil
+	}
+	if options.Autoscaling.Enabled && options.Autoscaling.MaxReplicas > replicas {
+		replicas = options.Autoscaling.MaxReplicas
+	}
+	secrets := []string{}
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		secrets = append(secrets, getNodeKeySecretName(CRInstance, role, ordinal))
+	}
+	return secrets
+}
+
+// getProjectedNodeKeySecrets returns the names of the Secrets projected in the pods of a role generating its node keys.
+// They go up to the ordinals of getNodeKeySecrets rounded up to a multiple of nodeKeysProjectionStep, so that scaling the
+// role does not change its pod template, which would roll all its running pods
+func getProjectedNodeKeySecrets(CRInstance *polkadotv1alpha1.Polkadot, role string, replicas int32, options polkadotv1alpha1.NodeOptions) []string {
+	secrets := getNodeKeySecrets(CRInstance, role, replicas, options)
+	if len(secrets) == 0 {
+		return secrets
+	}
+	for ordinal := int32(len(secrets)); ordinal%nodeKeysProjectionStep != 0; ordinal++ {
+		secrets = append(secrets, getNodeKeySecretName(CRInstance, role, ordinal))
+	}
+	return secrets
+}
+
+func getNodeKeySecretName(CRInstance *polkadotv1alpha1.Polkadot, role string, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d-nodekey", CRInstance.Name, role, ordinal)
+}
+
+// newNodeKeySecret generates a new random node key, stored as hex like the nodeKey of the CR
+func newNodeKeySecret(CRInstance *polkadotv1alpha1.Polkadot, name string, role string) (*corev1.Secret, error) {
+	labels := getAppLabels()
+	labels["role"] = role
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{nodeKeyFileName: []byte(hex.EncodeToString(seed))},
+	}, nil
+}
+
+// getGeneratedNodeKeyCommands returns the argument of the node key copied by the init container of the pod
+func getGeneratedNodeKeyCommands(nodeKeySecrets []string) []string {
+	if len(nodeKeySecrets) == 0 {
+		return []string{}
+	}
+	return []string{"--node-key-file", nodeKeyMountPath + "/" + nodeKeyFileName}
+}
+
+// getNodeKeysVolume projects the node key of every ordinal in a file named after it, the Secrets of the ordinals not
+// created yet being optional, so that the pods of a scaled up role start as soon as their node key is generated
+func getNodeKeysVolume(nodeKeySecrets []string) corev1.Volume {
+	mode := int32(0440)
+	sources := []corev1.VolumeProjection{}
+	for ordinal, secret := range nodeKeySecrets {
+		isOptional := true
+		sources = append(sources, corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Items:                []corev1.KeyToPath{{Key: nodeKeyFileName, Path: strconv.Itoa(ordinal)}},
+			Optional:             &isOptional,
+		}})
+	}
+	return corev1.Volume{
+		Name: nodeKeysVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources, DefaultMode: &mode},
+		},
+	}
+}
+
+// getGeneratedNodeKeyVolume holds the node key of the pod, copied by the init container
+func getGeneratedNodeKeyVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: nodeKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+		},
+	}
+}
+
+// getNodeKeyInitContainer copies the node key matching the ordinal of the pod to the node-key file, so that the client
+// only sees its own node key. The pod does not start until its node key is generated
+func getNodeKeyInitContainer() corev1.Container {
+	key := nodeKeysMountPath + `/${HOSTNAME##*-}`
+	script := "set -e; " +
+		"cp " + key + " " + nodeKeyMountPath + "/" + nodeKeyFileName + "; " +
+		"chmod 0440 " + nodeKeyMountPath + "/" + nodeKeyFileName
+	return corev1.Container{
+		Name:    nodeKeyInitName,
+		Image:   nodeKeyImage,
+		Command: []string{"/bin/sh", "-c", script},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: nodeKeysVolumeName, MountPath: nodeKeysMountPath, ReadOnly: true},
+			{Name: nodeKeyVolumeName, MountPath: nodeKeyMountPath},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// kindRoles are the presets of the Kinds, the roles they deploy
+var kindRoles = map[CRKind][]string{
+	Sentry:             {"sentry"},
+	Validator:          {"validator"},
+	SentryAndValidator: {"sentry", "validator"},
+	Bootnode:           {"bootnode"},
+	Archive:            {"archive"},
+	RpcNode:            {"rpcnode"},
+	Collator:           {"collator"},
+}
+
+// getNodePools returns the node pools deployed by the CR: its nodePools, or the roles of the preset of its Kind
+func getNodePools(CRInstance *polkadotv1alpha1.Polkadot) []polkadotv1alpha1.NodePool {
+	if len(CRInstance.Spec.NodePools) > 0 {
+		return CRInstance.Spec.NodePools
+	}
+	pools := []polkadotv1alpha1.NodePool{}
+	for _, role := range kindRoles[CRKind(CRInstance.Spec.Kind)] {
+		pools = append(pools, polkadotv1alpha1.NodePool{Role: role})
+	}
+	if isRpcNodeAddOn(CRInstance) {
+		pools = append(pools, polkadotv1alpha1.NodePool{Role: "rpcnode"})
+	}
+	return pools
+}
+
+func isRoleDeployed(CRInstance *polkadotv1alpha1.Polkadot, role string) bool {
+	for _, pool := range getNodePools(CRInstance) {
+		if pool.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isSentryAndValidator tells if the validator runs behind the sentries of the CR, only peering with them
+func isSentryAndValidator(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return isRoleDeployed(CRInstance, "sentry") && isRoleDeployed(CRInstance, "validator")
+}
+
+// applyNodePools overrides the role sections of the CR with the settings of its node pools, so that the resources of
+// every role are generated from its section. The CR is only changed in memory, it is never written back: its status
+// is written from a copy by updateStatus
+func applyNodePools(CRInstance *polkadotv1alpha1.Polkadot) {
+	spec := &CRInstance.Spec
+	for _, pool := range spec.NodePools {
+		var replicas *int32
+		var nodeOptions *polkadotv1alpha1.NodeOptions
+		var dataPersistence *polkadotv1alpha1.DataPersistenceSupport
+		var resources *corev1.ResourceRequirements
+		switch pool.Role {
+		case "sentry":
+			replicas, nodeOptions, dataPersistence, resources = &spec.Sentry.Replicas, &spec.Sentry.NodeOptions, &spec.Sentry.DataPersistenceSupport, &spec.Sentry.Resources
+		case "validator":
+			nodeOptions, dataPersistence, resources = &spec.Validator.NodeOptions, &spec.Validator.DataPersistenceSupport, &spec.Validator.Resources
+		case "bootnode":
+			replicas, nodeOptions, dataPersistence, resources = &spec.Bootnode.Replicas, &spec.Bootnode.NodeOptions, &spec.Bootnode.DataPersistenceSupport, &spec.Bootnode.Resources
+		case "archive":
+			replicas, nodeOptions, dataPersistence, resources = &spec.Archive.Replicas, &spec.Archive.NodeOptions, &spec.Archive.DataPersistenceSupport, &spec.Archive.Resources
+		case "rpcnode":
+			replicas, nodeOptions, dataPersistence, resources = &spec.RpcNode.Replicas, &spec.RpcNode.NodeOptions, &spec.RpcNode.DataPersistenceSupport, &spec.RpcNode.Resources
+		case "collator":
+			replicas, nodeOptions, dataPersistence, resources = &spec.Collator.Replicas, &spec.Collator.NodeOptions, &spec.Collator.DataPersistenceSupport, &spec.Collator.Resources
+		default:
+			continue
+		}
+		if replicas != nil && pool.Replicas > 0 {
+			*replicas = pool.Replicas
+		}
+		if len(pool.ExtraArgs) > 0 {
+			nodeOptions.ExtraArgs = append(append([]string{}, nodeOptions.ExtraArgs...), pool.ExtraArgs...)
+		}
+		if pool.Storage != nil {
+			*dataPersistence = *pool.Storage.DeepCopy()
+		}
+		if pool.Resources != nil {
+			*resources = *pool.Resources.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const rpcTimeout = 10 * time.Second
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// callNodeRPC calls a JSON-RPC method of a node and decodes its result, it is a variable so that the tests can mock the nodes
+var callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	client := http.Client{Timeout: rpcTimeout}
+	response, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected HTTP status %s", method, response.Status)
+	}
+
+	decoded := rpcResponse{}
+	err = json.NewDecoder(response.Body).Decode(&decoded)
+	if err != nil {
+		return err
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("%s: RPC error %d: %s", method, decoded.Error.Code, decoded.Error.Message)
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
+
+// getServiceRPCEndpoint returns the RPC endpoint of the nodes behind a Service of the CR
+func getServiceRPCEndpoint(serviceName, namespace string, rpcPort int32) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", serviceName, namespace, rpcPort)
+}
+
+// getChainServiceName returns the Service the chain is queried through, the one of a role of the CR, the validator being
+// the last choice as its RPC ports are isolated by its NetworkPolicy
+func getChainServiceName(CRInstance *polkadotv1alpha1.Polkadot) string {
+	for _, rr := range getRoleResources(CRInstance) {
+		if rr.role != "validator" {
+			return rr.serviceName
+		}
+	}
+	return ServiceValidatorName
+}
+
+// getJobsEndpoint returns the WebSocket endpoint the jobs of the CR (payouts, staking) submit their extrinsics to
+func getJobsEndpoint(CRInstance *polkadotv1alpha1.Polkadot) string {
+	return fmt.Sprintf("ws://%s.%s.svc:%d", getChainServiceName(CRInstance), CRInstance.Namespace, getPorts(CRInstance).WS)
+}
+
+// getPodRPCEndpoint returns the RPC endpoint of a single node, at the RPC port of its client container so that a pod
+// not rolled out yet with changed ports is still reached
+func getPodRPCEndpoint(pod *corev1.Pod) string {
+	rpcPort := int32(config.RPCPortEnvVar.Value)
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if container.Name == serviceName && port.Name == RPCPortName {
+				rpcPort = port.ContainerPort
+			}
+		}
+	}
+	return fmt.Sprintf("http://%s:%d", pod.Status.PodIP, rpcPort)
+}
+
+type systemHealth struct {
+	Peers           int32 `json:"peers"`
+	IsSyncing       bool  `json:"isSyncing"`
+	ShouldHavePeers bool  `json:"shouldHavePeers"`
+}
+
+type syncState struct {
+	StartingBlock int64 `json:"startingBlock"`
+	CurrentBlock  int64 `json:"currentBlock"`
+	HighestBlock  int64 `json:"highestBlock"`
+}
+
+type blockHeader struct {
+	Number string `json:"number"`
+}
+
+// getNodeSyncStatus returns the sync state and the peer count of a node, the block numbers of the headers are hex encoded
+func getNodeSyncStatus(name, endpoint string) (polkadotv1alpha1.NodeStatus, error) {
+	status := polkadotv1alpha1.NodeStatus{Name: name}
+
+	health := systemHealth{}
+	if err := callNodeRPC(endpoint, "system_health", &health); err != nil {
+		return status, err
+	}
+	state := syncState{}
+	if err := callNodeRPC(endpoint, "system_syncState", &state); err != nil {
+		return status, err
+	}
+	finalizedHash := ""
+	if err := callNodeRPC(endpoint, "chain_getFinalizedHead", &finalizedHash); err != nil {
+		return status, err
+	}
+	finalizedHeader := blockHeader{}
+	if err := callNodeRPC(endpoint, "chain_getHeader", &finalizedHeader, finalizedHash); err != nil {
+		return status, err
+	}
+	finalizedBlock, err := strconv.ParseInt(strings.TrimPrefix(finalizedHeader.Number, "0x"), 16, 64)
+	if err != nil {
+		return status, err
+	}
+
+	status.IsSyncing = health.IsSyncing
+	status.BestBlock = state.CurrentBlock
+	status.FinalizedBlock = finalizedBlock
+	status.Peers = health.Peers
+	return status, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	payoutsSucceeded = "Succeeded"
+	payoutsFailed    = "Failed"
+)
+
+// handlePayouts schedules the payouts of the staking rewards, and reports the outcome of the last payout job in the status
+// and in an event. The jobs are owned by the CronJob, whose status changes requeue the CR once a job completes
+func (r *ReconcilerPolkadot) handlePayouts(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	if !CRInstance.Spec.Payouts.Enabled {
+		return r.handlePayoutsDisabled(CRInstance)
+	}
+
+	isForcedRequeue, err := r.handleCronJobGeneric(CRInstance, newPayoutsCronJob(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	return r.handlePayoutsStatus(CRInstance)
+}
+
+func (r *ReconcilerPolkadot) handlePayoutsStatus(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	jobs := &batchv1.JobList{}
+	err := r.client.List(context.TODO(), jobs, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(getPayoutsLabels()))
+	if err != nil {
+		logger.Error(err, "Error on listing the payout jobs...")
+		return NotForcedRequeue, err
+	}
+	var last *polkadotv1alpha1.PayoutsStatus
+	for i := range jobs.Items {
+		status := getPayoutsJobStatus(&jobs.Items[i])
+		if status != nil && (last == nil || last.CompletionTime.Before(status.CompletionTime)) {
+			last = status
+		}
+	}
+	if last == nil || (CRInstance.Status.Payouts != nil && CRInstance.Status.Payouts.LastJob == last.LastJob) {
+		return handleSkip()
+	}
+
+	CRInstance.Status.Payouts = last
+	err = r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Payout job completed...", "Job.Name", last.LastJob, "Result", last.Result)
+	if last.Result == payoutsSucceeded {
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "PayoutsSucceeded", "The payout job %s paid out the unclaimed eras of the stashes", last.LastJob)
+	} else {
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "PayoutsFailed", "The payout job %s failed, see the logs of its pod", last.LastJob)
+	}
+	return NotForcedRequeue, nil
+}
+
+// getPayoutsJobStatus returns the outcome of a payout job, nil while it is running
+func getPayoutsJobStatus(job *batchv1.Job) *polkadotv1alpha1.PayoutsStatus {
+	if job.Status.Succeeded > 0 && job.Status.CompletionTime != nil {
+		return &polkadotv1alpha1.PayoutsStatus{LastJob: job.Name, Result: payoutsSucceeded, CompletionTime: job.Status.CompletionTime.DeepCopy()}
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			completionTime := condition.LastTransitionTime
+			return &polkadotv1alpha1.PayoutsStatus{LastJob: job.Name, Result: payoutsFailed, CompletionTime: &completionTime}
+		}
+	}
+	return nil
+}
+
+// handlePayoutsDisabled deletes the payouts CronJob, the status of the last payout is kept
+func (r *ReconcilerPolkadot) handlePayoutsDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("CronJob.Name", PayoutsName)
+
+	foundResource := &batchv1beta1.CronJob{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: PayoutsName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the CronJob...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Payouts disabled, deleting the CronJob...")
+	err = r.deleteResource(foundResource, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		logger.Error(err, "Delete CronJob Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the CronJob")
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,85 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestHandlePayouts(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := batchv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Payouts.Enabled = true
+	polkadot.Spec.Payouts.Stashes = []string{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}
+	polkadot.Spec.Payouts.AccountSecretRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "payouts"}, Key: "seed"}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handlePayouts(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handlePayouts: (%v, %v)", isRequeueForced, err)
+	}
+	found := &batchv1beta1.CronJob{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PayoutsName}, found)
+	if err != nil {
+		t.Fatalf("handlePayouts: (%v)", err)
+	}
+	container := found.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	if found.Spec.Schedule != payoutsSchedule || container.Env[0].Value != fmt.Sprintf("ws://sentry-service..svc:%d", getPorts(polkadot).WS) || container.Env[3].ValueFrom.SecretKeyRef.Name != "payouts" {
+		t.Fatalf("handlePayouts: unexpected CronJob (%v, %v)", found.Spec.Schedule, container.Env)
+	}
+
+	// the outcome of the last completed job is reported
+	completionTime := metav1.NewTime(time.Now())
+	jobs := []*batchv1.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-payouts-1", Labels: getPayoutsLabels()}, Status: batchv1.JobStatus{Succeeded: 1, CompletionTime: &completionTime}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-payouts-2", Labels: getPayoutsLabels()}, Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(completionTime.Add(time.Hour))},
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-payouts-3", Labels: getPayoutsLabels()}, Status: batchv1.JobStatus{Active: 1}},
+	}
+	for _, job := range jobs {
+		if err := client.Create(context.TODO(), job); err != nil {
+			t.Fatalf("Create: (%v)", err)
+		}
+	}
+	isRequeueForced, err = reconciler.handlePayouts(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handlePayouts: (%v, %v)", isRequeueForced, err)
+	}
+	if status := polkadot.Status.Payouts; status == nil || status.LastJob != "polkadot-payouts-2" || status.Result != payoutsFailed {
+		t.Fatalf("handlePayouts: unexpected status (%v)", status)
+	}
+
+	polkadot.Spec.Payouts.Enabled = false
+	isRequeueForced, err = reconciler.handlePayouts(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handlePayouts: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: PayoutsName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handlePayouts: CronJob not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
+)
+
+// payoutsScript pays out the eras of the last $ERAS eras not claimed yet by each stash. The numbers are formatted by
+// polkadot-js-api (e.g. "1,234"), the separators are removed. The job fails if any payout failed
+const payoutsScript = `set -u
+api() { polkadot-js-api --ws "$ENDPOINT" "$@"; }
+era=$(api query.staking.activeEra | tr -d '\n ' | sed -n 's/.*"index":"\{0,1\}\([0-9,]*\).*/\1/p' | tr -d ,)
+if [ -z "$era" ]; then echo "active era not found"; exit 1; fi
+first=$((era - ERAS)); if [ "$first" -lt 0 ]; then first=0; fi
+failed=0
+for stash in $STASHES; do
+  controller=$(api query.staking.bonded "$stash" | tr -d '\n ' | sed -n 's/.*"bonded":"\([^"]*\)".*/\1/p')
+  if [ -z "$controller" ]; then echo "$stash is not bonded"; failed=$((failed + 1)); continue; fi
+  claimed=$(api query.staking.ledger "$controller" | tr -d '\n ' | sed -n 's/.*"claimedRewards":\[\([^]]*\)\].*/\1/p' | grep -o '"[0-9,]*"' | tr -d '",')
+  for e in $(seq "$first" $((era - 1))); do
+    if echo "$claimed" | grep -qx "$e"; then continue; fi
+    echo "paying out the era $e of $stash"
+    api --seed "$SEED" tx.staking.payoutStakers "$stash" "$e" || failed=$((failed + 1))
+  done
+done
+echo "$failed payouts failed"
+[ "$failed" -eq 0 ]`
+
+func getPayoutsLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "payouts"
+	return labels
+}
+
+// newPayoutsCronJob submits the payouts of the stashes on schedule, through the WebSocket endpoint of the nodes of the CR.
+// A job runs once, the eras it could not pay out are retried by the next one
+func newPayoutsCronJob(CRInstance *polkadotv1alpha1.Polkadot) *batchv1beta1.CronJob {
+	payouts := CRInstance.Spec.Payouts
+	schedule := payouts.Schedule
+	if schedule == "" {
+		schedule = payoutsSchedule
+	}
+	image := payouts.Image
+	if image == "" {
+		image = polkadotJsImage
+	}
+	eras := payouts.Eras
+	if eras <= 0 {
+		eras = payoutsEras
+	}
+	env := []corev1.EnvVar{
+		{Name: "ENDPOINT", Value: getJobsEndpoint(CRInstance)},
+		{Name: "STASHES", Value: strings.Join(payouts.Stashes, " ")},
+		{Name: "ERAS", Value: strconv.Itoa(int(eras))},
+	}
+	if payouts.AccountSecretRef != nil {
+		env = append(env, corev1.EnvVar{Name: payoutsSeedEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: payouts.AccountSecretRef}})
+	}
+	backoffLimit := int32(0)
+	labels := getCopyLabelsWithCustom(getPayoutsLabels(), CRInstance.Spec.Metadata.Labels)
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: getPayoutsObjectMeta(CRInstance),
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:    corev1.RestartPolicyNever,
+							ImagePullSecrets: CRInstance.Spec.ImagePullSecrets,
+							Containers: []corev1.Container{{
+								Name:    PayoutsName,
+								Image:   image,
+								Command: []string{"/bin/sh", "-c", payoutsScript},
+								Env:     env,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if isHardened(CRInstance) {
+		hardenPodTemplate(&cronJob.Spec.JobTemplate.Spec.Template)
+	}
+	return cronJob
+}
+
+func getPayoutsObjectMeta(CRInstance *polkadotv1alpha1.Polkadot) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        PayoutsName,
+		Namespace:   CRInstance.Namespace,
+		Labels:      getCopyLabelsWithCustom(getPayoutsLabels(), CRInstance.Spec.Metadata.Labels),
+		Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+	}
+}
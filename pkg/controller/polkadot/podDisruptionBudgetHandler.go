@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// handlePodDisruptionBudget generates a PodDisruptionBudget for each role enabling it, they are removed if it is disabled
+func (r *ReconcilerPolkadot) handlePodDisruptionBudget(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	for _, rr := range getRoleResources(CRInstance) {
+		var isForcedRequeue bool
+		var err error
+		if rr.options.PodDisruptionBudget.Enabled != true {
+			isForcedRequeue, err = r.handlePodDisruptionBudgetDisabled(CRInstance, rr.statefulSetName)
+		} else {
+			isForcedRequeue, err = r.handlePodDisruptionBudgetGeneric(CRInstance, newPodDisruptionBudget(CRInstance, rr))
+		}
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+// handlePodDisruptionBudgetDisabled deletes the PodDisruptionBudget previously generated for a role
+func (r *ReconcilerPolkadot) handlePodDisruptionBudgetDisabled(CRInstance *polkadotv1alpha1.Polkadot, name string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("PodDisruptionBudget.Name", name)
+
+	foundResource := &policyv1beta1.PodDisruptionBudget{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: name, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the PodDisruptionBudget...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("PodDisruptionBudget disabled, deleting it...")
+	err = r.deleteResourceWithEvent(CRInstance, foundResource, "PodDisruptionBudget")
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handlePodDisruptionBudgetGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *policyv1beta1.PodDisruptionBudget) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("PodDisruptionBudget.Name", desiredResource.Name)
+
+	toBeFoundResource := &policyv1beta1.PodDisruptionBudget{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the PodDisruptionBudget...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("PodDisruptionBudget not found...")
+		logger.Info("Creating a new PodDisruptionBudget...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new PodDisruptionBudget...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the PodDisruptionBudget %s: %v", desiredResource.Name, err)
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new PodDisruptionBudget")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the PodDisruptionBudget %s", desiredResource.Name)
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update PodDisruptionBudget Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the PodDisruptionBudget %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the PodDisruptionBudget...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the PodDisruptionBudget %s", desiredResource.Name)
+	}
+
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,67 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"testing"
+)
+
+func TestHandlePodDisruptionBudget(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := policyv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Sentry.Replicas = 3
+	polkadot.Spec.Sentry.PodDisruptionBudget = polkadotv1alpha1.PodDisruptionBudget{Enabled: true}
+	maxUnavailable := intstr.FromString("50%")
+	polkadot.Spec.Validator.PodDisruptionBudget = polkadotv1alpha1.PodDisruptionBudget{Enabled: true, MaxUnavailable: &maxUnavailable}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// one PodDisruptionBudget is created per reconcile
+	for i := 0; i < 2; i++ {
+		isRequeueForced, err := reconciler.handlePodDisruptionBudget(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handlePodDisruptionBudget: (%v, %v)", isRequeueForced, err)
+		}
+	}
+	isRequeueForced, err := reconciler.handlePodDisruptionBudget(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handlePodDisruptionBudget: (%v, %v)", isRequeueForced, err)
+	}
+
+	expected := map[string]intstr.IntOrString{SentrySSName: intstr.FromInt(1), ValidatorSSName: maxUnavailable}
+	for name, e := range expected {
+		found := &policyv1beta1.PodDisruptionBudget{}
+		isNotFound, err := reconciler.fetchResource(found, types.NamespacedName{Name: name})
+		if err != nil || isNotFound {
+			t.Fatalf("handlePodDisruptionBudget: PodDisruptionBudget %s not created (%v)", name, err)
+		}
+		if *found.Spec.MaxUnavailable != e || found.Spec.Selector.MatchLabels["role"] == "" {
+			t.Fatalf("handlePodDisruptionBudget: unexpected PodDisruptionBudget %s (%v)", name, found.Spec)
+		}
+	}
+
+	// the PodDisruptionBudget is deleted once it is disabled
+	polkadot.Spec.Sentry.PodDisruptionBudget.Enabled = false
+	isRequeueForced, err = reconciler.handlePodDisruptionBudget(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handlePodDisruptionBudget: (%v, %v)", isRequeueForced, err)
+	}
+	isNotFound, err := reconciler.fetchResource(&policyv1beta1.PodDisruptionBudget{}, types.NamespacedName{Name: SentrySSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("handlePodDisruptionBudget: PodDisruptionBudget not deleted (%v)", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// newPodDisruptionBudget returns the PodDisruptionBudget of the pods of the role, named after its StatefulSet
+func newPodDisruptionBudget(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *policyv1beta1.PodDisruptionBudget {
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	maxUnavailable := intstr.FromInt(1)
+	if rr.options.PodDisruptionBudget.MaxUnavailable != nil {
+		maxUnavailable = *rr.options.PodDisruptionBudget.MaxUnavailable
+	}
+
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rr.statefulSetName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+}
@@ -7,15 +7,29 @@ import (
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
 )
 
 var log = logf.Log.WithName(config.ControllerNameEnvVar.Value)
@@ -26,6 +40,10 @@ type ReconcilerPolkadot struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// recorder emits the events of the CustomResources (e.g. the session keys rotations)
+	recorder record.EventRecorder
+	// backoff delays the requeues of the failed reconciliations
+	backoff requeueBackoff
 }
 
 // Add creates a new Polkadot Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -36,7 +54,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcilerPolkadot{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcilerPolkadot{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: newHistoryRecorder(mgr.GetEventRecorderFor(config.ControllerNameEnvVar.Value))}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -47,12 +65,28 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch for changes to primary resource CustomResource
-	err = c.Watch(&source.Kind{Type: &polkadotv1alpha1.Polkadot{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource CustomResource, matching the watch label selector
+	err = c.Watch(&source.Kind{Type: &polkadotv1alpha1.Polkadot{}}, &handler.EnqueueRequestForObject{}, getWatchPredicate())
 	if err != nil {
 		return err
 	}
 
+	// Watch for changes to the CustomResources referenced as bootnodes and requeue the CustomResources referencing them
+	// (the bootnode addresses are generated from their spec, the updates of their status only are filtered out)
+	err = c.Watch(&source.Kind{Type: &polkadotv1alpha1.Polkadot{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: getBootnodesReferrers(mgr.GetClient())}, predicate.Funcs{UpdateFunc: isCRChanged})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the ConfigMaps and Secrets referenced by the CustomResources (e.g. the chainspec, the node keys)
+	// and requeue the CustomResources referencing them
+	for _, referencedResource := range []runtime.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		err = c.Watch(&source.Kind{Type: referencedResource}, &handler.EnqueueRequestsFromMapFunc{ToRequests: getConfigReferrers(mgr.GetClient())})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Watch for changes to secondary resource StatefulSet and requeue the owner CustomResource
 	err = c.Watch(&source.Kind{Type: &appsv1.StatefulSet{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -71,29 +105,218 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	//TODO add watch for NetworkPolicy
+	// Watch for changes to secondary resource ServiceAccount and requeue the owner CustomResource
+	err = c.Watch(&source.Kind{Type: &corev1.ServiceAccount{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &polkadotv1alpha1.Polkadot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource ConfigMap and requeue the owner CustomResource
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &polkadotv1alpha1.Polkadot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource CronJob and requeue the owner CustomResource
+	err = c.Watch(&source.Kind{Type: &batchv1beta1.CronJob{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &polkadotv1alpha1.Polkadot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the other secondary resources and requeue the owner CustomResource, so that a reconcile
+	// interrupted by the creation of one of them is resumed as soon as it is created
+	for _, secondaryResource := range []runtime.Object{&corev1.Secret{}, &batchv1.Job{}, &networkingv1.NetworkPolicy{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}, &autoscalingv2beta2.HorizontalPodAutoscaler{}, &policyv1beta1.PodDisruptionBudget{}, &networkingv1beta1.Ingress{}} {
+		err = c.Watch(&source.Kind{Type: secondaryResource}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &polkadotv1alpha1.Polkadot{},
+		})
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// getWatchPredicate filters out the events of the CustomResources not matching the watch label selector, and the updates
+// of their status only, written by the reconciliation itself, which would otherwise requeue it endlessly
+func getWatchPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isWatched(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isWatched(e.MetaNew) && isCRChanged(e) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isWatched(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) bool { return isWatched(e.Meta) },
+	}
+}
+
+// isCRChanged tells if an update of a CustomResource changed more than its status: its spec (tracked by the generation),
+// its labels and annotations (e.g. the dry-run annotation), its finalizers or its deletion
+func isCRChanged(e event.UpdateEvent) bool {
+	return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration() ||
+		!reflect.DeepEqual(e.MetaOld.GetLabels(), e.MetaNew.GetLabels()) ||
+		!reflect.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations()) ||
+		!reflect.DeepEqual(e.MetaOld.GetFinalizers(), e.MetaNew.GetFinalizers()) ||
+		!reflect.DeepEqual(e.MetaOld.GetDeletionTimestamp(), e.MetaNew.GetDeletionTimestamp())
+}
+
+// isWatched tells if the CustomResource matches the watch label selector
+func isWatched(CRMeta metav1.Object) bool {
+	return config.WatchLabelSelector.Matches(labels.Set(CRMeta.GetLabels()))
+}
+
 // blank assignment to verify that ReconcilerPolkadot implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcilerPolkadot{}
 
 // Reconcile reads that state of the cluster for a CustomResource object and makes changes based on the state read
 // and what is in the CustomResource.Spec
-func (r *ReconcilerPolkadot) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	logger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+func (r *ReconcilerPolkadot) Reconcile(request reconcile.Request) (result reconcile.Result, err error) {
+	logger, releaseLogger := newReconcileLogger(request.NamespacedName)
+	defer releaseLogger()
 	logger.Info("Reconciling Polkadot CustomResource")
 
+	start := time.Now()
+	isDeleted := false
+	defer func() {
+		if isDeleted {
+			deleteReconcileMetrics(request)
+			r.backoff.reset(request.NamespacedName)
+			return
+		}
+		observeReconcile(request, start, err)
+		if err != nil {
+			// the failed reconciliations are requeued with an exponential backoff, instead of the rate limiter of the queue
+			delay := r.backoff.next(request.NamespacedName)
+			logger.Error(err, "Reconcile Error, requeing the request...", "RequeueAfter", delay.String())
+			result, err = reconcile.Result{RequeueAfter: delay}, nil
+			return
+		}
+		r.backoff.reset(request.NamespacedName)
+	}()
+
 	handledCRInstance, err := r.handleCustomResource(request)
 	if err != nil {
 		return handleRequeueError(err,logger)
 	}
 	if handledCRInstance == nil {
+		isDeleted = true
+		logger.V(debugLevel).Info("Return and not requeing the request")
+		return reconcile.Result{}, nil
+	}
+	if handledCRInstance.Spec.Paused {
+		logger.Info("Custom Resource paused, skipping the reconcile of its resources...")
+		err = r.handleStatus(handledCRInstance)
+		if err != nil {
+			return handleRequeueError(err,logger)
+		}
+		return handleRequeueStd(err, logger)
+	}
+	if isDryRun(handledCRInstance) {
+		logger.Info("Custom Resource in dry-run mode, reporting the changes of its resources...")
+		err = r.handleDryRun(handledCRInstance)
+		if err != nil {
+			return handleRequeueError(err,logger)
+		}
 		return handleRequeueStd(err, logger)
 	}
 
-	isRequeueForced, err := r.handleStatefulSet(handledCRInstance)
+	isRequeueForced, err := r.handleServiceAccount(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleValidatorFailover(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleBootnodes(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleReservedPeers(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handlePublicAddresses(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleSentryAddresses(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleChainspecBuilder(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleImagePinning(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleNodeKeys(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleStatefulSet(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleAutoscaling(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handlePodDisruptionBudget(handledCRInstance)
 	if err != nil {
 		return handleRequeueError(err,logger)
 	}
@@ -109,6 +332,14 @@ func (r *ReconcilerPolkadot) Reconcile(request reconcile.Request) (reconcile.Res
 		return handleRequeueForced(err, logger)
 	}
 
+	isRequeueForced, err = r.handleIngress(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
 	isRequeueForced, err = r.handleNetworkPolicy(handledCRInstance)
 	if err != nil {
 		return handleRequeueError(err,logger)
@@ -117,22 +348,117 @@ func (r *ReconcilerPolkadot) Reconcile(request reconcile.Request) (reconcile.Res
 		return handleRequeueForced(err, logger)
 	}
 
+	isRequeueForced, err = r.handleStaleResources(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleBackup(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handlePayouts(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleMonitoring(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleKeyRotation(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleStaking(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	isRequeueForced, err = r.handleSlashDetection(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isRequeueForced {
+		return handleRequeueForced(err, logger)
+	}
+
+	err = r.handleStatus(handledCRInstance)
+	if err != nil {
+		return handleRequeueError(err,logger)
+	}
+	if isUpgradeInProgress(handledCRInstance) || isFailoverInProgress(handledCRInstance) || isPeerDiscoveryInProgress(handledCRInstance) {
+		return handleRequeueForced(err, logger)
+	}
+	if period := getPollingPeriod(handledCRInstance); period > 0 {
+		return handleRequeuePolling(period, err, logger)
+	}
+
 	return handleRequeueStd(err, logger)
 }
 
-// The Controller will requeue the Request to be processed again if the returned error is non-nil or
-// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+// The Controller will requeue the Request to be processed again after Result.RequeueAfter if it is set,
+// otherwise upon completion it will remove the work from the queue. The errors are turned into delayed requeues by Reconcile.
 func handleRequeueError (err error, logger logr.Logger) (reconcile.Result, error){
-	logger.Info("Requeing the Reconciling request... ")
 	return reconcile.Result{}, err
 }
 
+// handleRequeueForced stops the reconcile after the creation or the deletion of a resource: the watches requeue the request
+// as soon as the change is observed, the delayed requeue only covers the resources which are not watched (e.g. the PodMonitor).
+// It also polls the sync state of the upgraded nodes during a sync gated upgrade, the validator pods during a failover,
+// and the nodes whose peer ID is not discovered yet
 func handleRequeueForced (err error, logger logr.Logger) (reconcile.Result, error){
-	logger.Info("Requeing the Reconciling request... ")
-	return reconcile.Result{Requeue: true}, nil
+	logger.V(debugLevel).Info("Requeing the Reconciling request... ", "RequeueAfter", config.RequeueAfterCreation.String())
+	return reconcile.Result{RequeueAfter: config.RequeueAfterCreation}, nil
 }
 
+// getPollingPeriod returns the shortest period at which the chain state of a CR is polled (e.g. the slashes of its
+// validator, the peer counts of its nodes while its network is not healthy), 0 if it is not polled
+func getPollingPeriod(CRInstance *polkadotv1alpha1.Polkadot) time.Duration {
+	period := getNetworkHealthRequeue(CRInstance, metav1.Now())
+	if isSlashDetectionEnabled(CRInstance) && (period == 0 || slashDetectionPeriod < period) {
+		period = slashDetectionPeriod
+	}
+	return period
+}
+
+// handleRequeuePolling completes the reconcile of a CR whose chain state is polled, the request is requeued after the
+// polling period or after the requeue interval if it is shorter
+func handleRequeuePolling (period time.Duration, err error, logger logr.Logger) (reconcile.Result, error){
+	if config.RequeueInterval > 0 && config.RequeueInterval < period {
+		period = config.RequeueInterval
+	}
+	logger.V(debugLevel).Info("Requeing the Reconciling request... ", "RequeueAfter", period.String())
+	return reconcile.Result{RequeueAfter: period}, nil
+}
+
+// handleRequeueStd completes the reconcile, the request is requeued after the requeue interval if it is set
 func handleRequeueStd (err error, logger logr.Logger) (reconcile.Result, error){
-	logger.Info("Return and not requeing the request")
+	if config.RequeueInterval > 0 {
+		logger.V(debugLevel).Info("Requeing the Reconciling request... ", "RequeueAfter", config.RequeueInterval.String())
+		return reconcile.Result{RequeueAfter: config.RequeueInterval}, nil
+	}
+	logger.V(debugLevel).Info("Return and not requeing the request")
 	return reconcile.Result{}, nil
 }
\ No newline at end of file
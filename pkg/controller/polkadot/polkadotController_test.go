@@ -0,0 +1,224 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReconcileBackoff(t *testing.T) {
+
+	// the Polkadot type is not registered, every fetch of the CR fails
+	scheme := runtime.NewScheme()
+
+	client := newFakeClient(scheme)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	for _, expected := range []int64{1, 2, 4} {
+		result, err := reconciler.Reconcile(*getFakeRequest())
+		if err != nil || result.RequeueAfter != config.MinRequeueBackoff*time.Duration(expected) {
+			t.Fatalf("Reconcile: unexpected requeue (%v, %v)", result, err)
+		}
+	}
+}
+
+func TestReconcilePaused(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Paused = true
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	result, err := reconciler.Reconcile(*getFakeRequest())
+	if err != nil || result.RequeueAfter != config.RequeueInterval {
+		t.Fatalf("Reconcile: unexpected requeue (%v, %v)", result, err)
+	}
+
+	// the resources of a paused CR are not created
+	isNotFound, err := reconciler.fetchResource(&appsv1.StatefulSet{}, types.NamespacedName{Name: SentrySSName})
+	if err != nil || !isNotFound {
+		t.Fatalf("Reconcile: StatefulSet created for a paused CR (%v)", err)
+	}
+
+	found := &polkadotv1alpha1.Polkadot{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, found); err != nil {
+		t.Fatalf("Reconcile: (%v)", err)
+	}
+	isPaused := false
+	for _, condition := range found.Status.Conditions {
+		isPaused = isPaused || (condition.Type == PausedType && condition.Status == corev1.ConditionTrue)
+	}
+	if !isPaused || found.Status.LastReconcileTime == nil {
+		t.Fatalf("Reconcile: status not reported for a paused CR (%v)", found.Status)
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	// every handler runs in dry-run mode, the built-in types of all the resources are registered
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Errorf("clientgoscheme.AddToScheme: %v", err)
+	}
+
+	// the StatefulSet of the sentries was deployed with a single replica
+	polkadot := getFakePolkadot()
+	polkadot.Annotations = map[string]string{DryRunAnnotation: "true"}
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	statefulSet := newStatefulSetSentry(polkadot)
+	polkadot.Spec.Sentry.Replicas = 2
+
+	client := newFakeClient(scheme, polkadot, statefulSet)
+	recorder := record.NewFakeRecorder(100)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	result, err := reconciler.Reconcile(*getFakeRequest())
+	if err != nil || result.RequeueAfter != config.RequeueInterval {
+		t.Fatalf("Reconcile: unexpected requeue (%v, %v)", result, err)
+	}
+	if events := countDryRunEvents(recorder); events == 0 {
+		t.Fatalf("Reconcile: changes not reported in the events")
+	}
+
+	// the resources are neither updated nor created
+	found := &appsv1.StatefulSet{}
+	if _, err := reconciler.fetchResource(found, types.NamespacedName{Name: SentrySSName}); err != nil || *found.Spec.Replicas != 1 {
+		t.Fatalf("Reconcile: StatefulSet updated in dry-run mode (%v)", err)
+	}
+	isNotFound, err := reconciler.fetchResource(&corev1.Service{}, types.NamespacedName{Name: ServiceSentryName})
+	if err != nil || !isNotFound {
+		t.Fatalf("Reconcile: Service created in dry-run mode (%v)", err)
+	}
+
+	// the changes are reported in the status
+	foundCR := &polkadotv1alpha1.Polkadot{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, foundCR); err != nil {
+		t.Fatalf("Reconcile: (%v)", err)
+	}
+	if foundCR.Status.DryRun == nil {
+		t.Fatalf("Reconcile: dry run not reported (%v)", foundCR.Status)
+	}
+	changes := strings.Join(foundCR.Status.DryRun.Changes, "\n")
+	if !strings.Contains(changes, "update StatefulSet "+SentrySSName+": spec.replicas") || !strings.Contains(changes, "create Service "+ServiceSentryName) {
+		t.Fatalf("Reconcile: unexpected changes (%v)", foundCR.Status.DryRun.Changes)
+	}
+	if foundCR.Status.ObservedGeneration != 0 {
+		t.Fatalf("Reconcile: generation observed in dry-run mode (%v)", foundCR.Status.ObservedGeneration)
+	}
+
+	// the same changes are not reported again by the next reconciliation
+	result, err = reconciler.Reconcile(*getFakeRequest())
+	if err != nil || result.RequeueAfter != config.RequeueInterval {
+		t.Fatalf("Reconcile: unexpected requeue (%v, %v)", result, err)
+	}
+	if events := countDryRunEvents(recorder); events != 0 {
+		t.Fatalf("Reconcile: changes reported again in the events (%v)", events)
+	}
+	reportedCR := &polkadotv1alpha1.Polkadot{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, reportedCR); err != nil {
+		t.Fatalf("Reconcile: (%v)", err)
+	}
+	if !reportedCR.Status.DryRun.Time.Equal(&foundCR.Status.DryRun.Time) {
+		t.Fatalf("Reconcile: dry run reported again (%v)", reportedCR.Status.DryRun.Time)
+	}
+}
+
+// countDryRunEvents drains the events recorded, and counts the ones reporting a dry-run change
+func countDryRunEvents(recorder *record.FakeRecorder) int {
+	count := 0
+	for {
+		select {
+		case e := <-recorder.Events:
+			if strings.Contains(e, " DryRun ") {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
+
+func TestWatchPredicate(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		update     func(*polkadotv1alpha1.Polkadot)
+		isExpected bool
+	}{
+		{
+			name: "status updated",
+			update: func(polkadot *polkadotv1alpha1.Polkadot) {
+				polkadot.ResourceVersion = "2"
+				polkadot.Status.ObservedGeneration = 1
+			},
+			isExpected: false,
+		},
+		{
+			name: "spec updated",
+			update: func(polkadot *polkadotv1alpha1.Polkadot) {
+				polkadot.ResourceVersion = "2"
+				polkadot.Generation = 2
+			},
+			isExpected: true,
+		},
+		{
+			name: "annotations updated",
+			update: func(polkadot *polkadotv1alpha1.Polkadot) {
+				polkadot.ResourceVersion = "2"
+				polkadot.Annotations = map[string]string{DryRunAnnotation: "true"}
+			},
+			isExpected: true,
+		},
+		{
+			name: "deleted",
+			update: func(polkadot *polkadotv1alpha1.Polkadot) {
+				polkadot.ResourceVersion = "2"
+				polkadot.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			},
+			isExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldCR := getFakePolkadot()
+			oldCR.ResourceVersion = "1"
+			oldCR.Generation = 1
+			newCR := oldCR.DeepCopy()
+			test.update(newCR)
+
+			e := event.UpdateEvent{MetaOld: oldCR, ObjectOld: oldCR, MetaNew: newCR, ObjectNew: newCR}
+			if getWatchPredicate().Update(e) != test.isExpected {
+				t.Fatalf("getWatchPredicate: expected (%v)", test.isExpected)
+			}
+		})
+	}
+}
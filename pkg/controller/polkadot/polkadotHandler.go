@@ -9,7 +9,7 @@ import (
 )
 
 func (r *ReconcilerPolkadot) handleCustomResource(request reconcile.Request) (*polkadotv1alpha1.Polkadot, error) {
-	logger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	logger := getRequestLogger(request.NamespacedName)
 
 	toBeFoundResource := &polkadotv1alpha1.Polkadot{}
 	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: request.Name, Namespace: request.Namespace})
@@ -26,5 +26,25 @@ func (r *ReconcilerPolkadot) handleCustomResource(request reconcile.Request) (*p
 	}
 	foundResource := toBeFoundResource
 
+	if !isWatched(foundResource) {
+		// The Custom Resource is reconciled by another instance of the operator (e.g. the request of an owned object).
+		// Return and don't requeue
+		logger.Info("Custom Resource not matching the watch label selector, skipping...")
+		return nil, nil
+	}
+
+	if foundResource.DeletionTimestamp != nil {
+		// The Custom Resource is being deleted, the owned objects are garbage collected after the cleanup.
+		// Return and don't requeue
+		logger.Info("Custom Resource being deleted, cleaning up...")
+		return nil, r.handleCleanup(foundResource)
+	}
+
+	err = r.handleFinalizer(foundResource)
+	if err != nil {
+		return nil, err
+	}
+
+	applyNodePools(foundResource)
 	return foundResource, nil
 }
\ No newline at end of file
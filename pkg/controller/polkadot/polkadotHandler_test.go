@@ -1,9 +1,10 @@
 package polkadot
 
 import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"testing"
 )
@@ -43,7 +44,7 @@ func TestHandleCustomResource(t *testing.T) {
 			objs := []runtime.Object{polkadot}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			polkadot, err := reconciler.handleCustomResource(*test.request)
@@ -59,7 +60,7 @@ func TestHandleCustomResource(t *testing.T) {
 			objs := []runtime.Object{}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			polkadot, err := reconciler.handleCustomResource(*test.request)
@@ -72,3 +73,54 @@ func TestHandleCustomResource(t *testing.T) {
 
 
 
+
+func TestHandleCustomResourceWatchLabelSelector(t *testing.T) {
+
+	defer func(selector labels.Selector) { config.WatchLabelSelector = selector }(config.WatchLabelSelector)
+	config.WatchLabelSelector = labels.SelectorFromSet(labels.Set{"team": "blockchain"})
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		isExpected bool
+	}{
+		{
+			name:       "Polkadot matching the selector",
+			labels:     map[string]string{"team": "blockchain"},
+			isExpected: true,
+		},
+		{
+			name:       "Polkadot of another team",
+			labels:     map[string]string{"team": "other"},
+			isExpected: false,
+		},
+		{
+			name:       "Polkadot without labels",
+			isExpected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polkadot := getFakePolkadot()
+			polkadot.Labels = test.labels
+
+			if isWatched(polkadot) != test.isExpected {
+				t.Fatalf("isWatched: expected (%v)", test.isExpected)
+			}
+
+			client := newFakeClient(scheme, polkadot)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+			found, err := reconciler.handleCustomResource(*getFakeRequest())
+			if (found != nil) != test.isExpected || err != nil {
+				t.Fatalf("handleCustomResource: unexpected result (%v, %v)", found, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+)
+
+// handlePublicAddresses resolves the public address of the roles enabling autoPublicAddr from their Service, and records
+// it in the status, from which the --public-addr argument of the nodes is generated. The Services are watched, so the
+// pods are rolled out with the address once a LoadBalancer gets its ingress
+func (r *ReconcilerPolkadot) handlePublicAddresses(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	var publicAddresses []polkadotv1alpha1.PublicAddress
+	for _, rr := range getRoleResources(CRInstance) {
+		if !rr.options.AutoPublicAddr {
+			continue
+		}
+		service := &corev1.Service{}
+		isNotFound, err := r.fetchResource(service, types.NamespacedName{Name: rr.serviceName, Namespace: CRInstance.Namespace})
+		if err != nil {
+			logger.Error(err, "Error on fetch the Service...", "Service.Name", rr.serviceName)
+			return NotForcedRequeue, err
+		}
+		address := ""
+		if !isNotFound || rr.options.HostNetwork {
+			address = getPublicAddress(rr.options, service, getPorts(CRInstance).P2P)
+		}
+		if address == "" {
+			logger.Info("Public address of the role not known yet...", "Role", rr.role, "Service.Name", rr.serviceName)
+			continue
+		}
+		publicAddresses = append(publicAddresses, polkadotv1alpha1.PublicAddress{Role: rr.role, Address: address})
+	}
+	if reflect.DeepEqual(publicAddresses, CRInstance.Status.PublicAddresses) {
+		return handleSkip()
+	}
+
+	CRInstance.Status.PublicAddresses = publicAddresses
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Updated the public addresses...", "PublicAddresses", publicAddresses)
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "PublicAddressesUpdated", "Updated the public addresses, %d roles resolved", len(publicAddresses))
+	return NotForcedRequeue, nil
+}
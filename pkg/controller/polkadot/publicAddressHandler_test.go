@@ -0,0 +1,91 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHandlePublicAddresses(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.ServiceType = corev1.ServiceTypeLoadBalancer
+	polkadot.Spec.Sentry.AutoPublicAddr = true
+
+	service := newServiceSentry(polkadot)
+	client := newFakeClient(scheme, polkadot, service)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the address is not known until the LoadBalancer gets its ingress
+	isRequeueForced, err := reconciler.handlePublicAddresses(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handlePublicAddresses: (%v, %v)", isRequeueForced, err)
+	}
+	if polkadot.Status.PublicAddresses != nil {
+		t.Fatalf("handlePublicAddresses: unexpected public addresses (%v)", polkadot.Status.PublicAddresses)
+	}
+
+	service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	if err := client.Status().Update(context.TODO(), service); err != nil {
+		t.Fatalf("Update: (%v)", err)
+	}
+	if _, err := reconciler.handlePublicAddresses(polkadot); err != nil {
+		t.Fatalf("handlePublicAddresses: (%v)", err)
+	}
+	expected := []polkadotv1alpha1.PublicAddress{{Role: "sentry", Address: "/ip4/203.0.113.10/tcp/-1"}}
+	if !reflect.DeepEqual(polkadot.Status.PublicAddresses, expected) {
+		t.Fatalf("handlePublicAddresses: unexpected public addresses (%v)", polkadot.Status.PublicAddresses)
+	}
+	container := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0]
+	if commands := strings.Join(container.Command, " "); !strings.Contains(commands, "--public-addr "+expected[0].Address) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", commands)
+	}
+	if env := container.Env[len(container.Env)-1]; env.Name != nodeIPEnv || env.ValueFrom.FieldRef.FieldPath != "status.hostIP" {
+		t.Fatalf("newStatefulSetSentry: unexpected env (%v)", container.Env)
+	}
+}
+
+func TestGetPublicAddress(t *testing.T) {
+
+	nodePort := &corev1.Service{Spec: corev1.ServiceSpec{
+		Type:  corev1.ServiceTypeNodePort,
+		Ports: []corev1.ServicePort{{Name: RPCPortName, NodePort: 30001}, {Name: P2PPortName, NodePort: 30002}},
+	}}
+	loadBalancer := &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}}
+	loadBalancer.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+
+	tests := []struct {
+		name     string
+		options  polkadotv1alpha1.NodeOptions
+		service  *corev1.Service
+		expected string
+	}{
+		{"NodePort", polkadotv1alpha1.NodeOptions{}, nodePort, "/ip4/$(NODE_IP)/tcp/30002"},
+		{"Host network", polkadotv1alpha1.NodeOptions{HostNetwork: true}, nodePort, "/ip4/$(NODE_IP)/tcp/30333"},
+		{"LoadBalancer hostname", polkadotv1alpha1.NodeOptions{}, loadBalancer, "/dns4/lb.example.com/tcp/30333"},
+		{"External DNS", polkadotv1alpha1.NodeOptions{ExternalDNS: polkadotv1alpha1.ExternalDNS{Hostname: "sentry.example.com"}}, loadBalancer, "/dns4/sentry.example.com/tcp/30333"},
+		{"ClusterIP", polkadotv1alpha1.NodeOptions{}, &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if address := getPublicAddress(test.options, test.service, 30333); address != test.expected {
+				t.Fatalf("getPublicAddress: unexpected address (%v)", address)
+			}
+		})
+	}
+}
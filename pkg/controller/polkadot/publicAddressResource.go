@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getPublicAddress returns the multiaddress external peers reach the role at, empty while it is not known (e.g. a
+// LoadBalancer without ingress yet). The pods on the host network, and the NodePort Services, are reached at the IP of
+// the node of each pod, expanded from the NODE_IP environment variable by the kubelet
+func getPublicAddress(options polkadotv1alpha1.NodeOptions, service *corev1.Service, p2pPort int32) string {
+	if options.HostNetwork {
+		return fmt.Sprintf("/ip4/$(%s)/tcp/%d", nodeIPEnv, p2pPort)
+	}
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		if options.ExternalDNS.Hostname != "" {
+			return fmt.Sprintf("/dns4/%s/tcp/%d", options.ExternalDNS.Hostname, p2pPort)
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				return fmt.Sprintf("/ip4/%s/tcp/%d", ingress.IP, p2pPort)
+			}
+			if ingress.Hostname != "" {
+				return fmt.Sprintf("/dns4/%s/tcp/%d", ingress.Hostname, p2pPort)
+			}
+		}
+	case corev1.ServiceTypeNodePort:
+		for _, port := range service.Spec.Ports {
+			if port.Name == P2PPortName && port.NodePort != 0 {
+				return fmt.Sprintf("/ip4/$(%s)/tcp/%d", nodeIPEnv, port.NodePort)
+			}
+		}
+	}
+	return ""
+}
+
+// getPublicAddrCommands returns the --public-addr argument of a role, resolved in the status
+func getPublicAddrCommands(CRInstance *polkadotv1alpha1.Polkadot, role string) []string {
+	for _, publicAddress := range CRInstance.Status.PublicAddresses {
+		if publicAddress.Role == role {
+			return []string{"--public-addr", publicAddress.Address}
+		}
+	}
+	return []string{}
+}
+
+// getNodeIPEnv returns the IP of the node of the pod, referenced by the public address of the role
+func getNodeIPEnv() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: nodeIPEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.hostIP"},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	"k8s.io/apimachinery/pkg/types"
+	"sync"
+	"time"
+)
+
+// requeueBackoff computes the exponential delays of the requeues after the failed reconciliations of each CustomResource,
+// the zero value is ready to use
+type requeueBackoff struct {
+	mutex    sync.Mutex
+	failures map[types.NamespacedName]uint
+}
+
+// next returns the delay of the next requeue of the CustomResource, doubled at every consecutive failure up to the maximum
+func (b *requeueBackoff) next(key types.NamespacedName) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures == nil {
+		b.failures = map[types.NamespacedName]uint{}
+	}
+	failures := b.failures[key]
+	b.failures[key] = failures + 1
+
+	delay := config.MinRequeueBackoff
+	for i := uint(0); i < failures && delay < config.MaxRequeueBackoff; i++ {
+		delay *= 2
+	}
+	if delay > config.MaxRequeueBackoff {
+		delay = config.MaxRequeueBackoff
+	}
+	return delay
+}
+
+// reset forgets the failures of the CustomResource, after a successful reconciliation or its deletion
+func (b *requeueBackoff) reset(key types.NamespacedName) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.failures, key)
+}
@@ -0,0 +1,37 @@
+package polkadot
+
+import (
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestRequeueBackoff(t *testing.T) {
+
+	key := types.NamespacedName{Name: CRName}
+	backoff := &requeueBackoff{}
+
+	expected := []int64{1, 2, 4, 8}
+	for _, factor := range expected {
+		if delay := backoff.next(key); delay != config.MinRequeueBackoff*time.Duration(factor) {
+			t.Fatalf("next: unexpected delay (%v)", delay)
+		}
+	}
+	for i := 0; i < 64; i++ {
+		backoff.next(key)
+	}
+	if delay := backoff.next(key); delay != config.MaxRequeueBackoff {
+		t.Fatalf("next: delay not capped (%v)", delay)
+	}
+
+	// the other CustomResources are not affected
+	if delay := backoff.next(types.NamespacedName{Name: "other-cr"}); delay != config.MinRequeueBackoff {
+		t.Fatalf("next: unexpected delay (%v)", delay)
+	}
+
+	backoff.reset(key)
+	if delay := backoff.next(key); delay != config.MinRequeueBackoff {
+		t.Fatalf("reset: failures not forgotten (%v)", delay)
+	}
+}
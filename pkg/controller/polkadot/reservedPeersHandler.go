@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+)
+
+// handleReservedPeers discovers the peer IDs of the validator and of the sentries of a SentryAndValidator deployment, wired
+// with each other as reserved nodes. The peer ID of a node is derived from its node key, or queried through the
+// system_localPeerId RPC method of its pod if it has none. The roles with a reserved peer ID set in the CR are not discovered,
+// except the sentries whose addresses are exported to the validators of other clusters
+func (r *ReconcilerPolkadot) handleReservedPeers(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	var reservedPeers *polkadotv1alpha1.ReservedPeersStatus
+	if isSentryAndValidator(CRInstance) || isSentryAddressesExported(CRInstance) {
+		previous := CRInstance.Status.ReservedPeers
+		if previous == nil {
+			previous = &polkadotv1alpha1.ReservedPeersStatus{}
+		}
+		reservedPeers = &polkadotv1alpha1.ReservedPeersStatus{}
+		var err error
+		if isSentryAndValidator(CRInstance) && CRInstance.Spec.Sentry.ReservedValidatorID == "" {
+			validatorReplicas := int32(1)
+			if isHighAvailabilityEnabled(CRInstance) {
+				validatorReplicas = validatorHAReplicas
+			}
+			validator := CRInstance.Spec.Validator
+			reservedPeers.Validators, err = r.getPeerIDs(CRInstance, ValidatorSSName, validatorReplicas, validator.NodeKey, validator.NodeKeySecretRef, previous.Validators)
+			if err != nil {
+				return NotForcedRequeue, err
+			}
+		}
+		if CRInstance.Spec.Validator.ReservedSentryID == "" || isSentryAddressesExported(CRInstance) {
+			sentry := CRInstance.Spec.Sentry
+			reservedPeers.Sentries, err = r.getPeerIDs(CRInstance, SentrySSName, sentry.Replicas, sentry.NodeKey, sentry.NodeKeySecretRef, previous.Sentries)
+			if err != nil {
+				return NotForcedRequeue, err
+			}
+		}
+	}
+	if reflect.DeepEqual(reservedPeers, CRInstance.Status.ReservedPeers) {
+		return handleSkip()
+	}
+
+	CRInstance.Status.ReservedPeers = reservedPeers
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	if reservedPeers != nil {
+		logger.Info("Updated the reserved peers...", "Validators", reservedPeers.Validators, "Sentries", reservedPeers.Sentries)
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "ReservedPeersUpdated", "Updated the reserved peers, validators %v, sentries %v", reservedPeers.Validators, reservedPeers.Sentries)
+	}
+	return NotForcedRequeue, nil
+}
+
+// getPeerIDs returns the peer ID of the node key shared by the pods of a StatefulSet, or the ones of its pods by ordinal.
+// The previous peer ID of a pod is kept while it is not reachable
+func (r *ReconcilerPolkadot) getPeerIDs(CRInstance *polkadotv1alpha1.Polkadot, statefulSetName string, replicas int32, nodeKey string, nodeKeySecretRef *corev1.SecretKeySelector, previous []string) ([]string, error) {
+	logger := getLogger(CRInstance).WithValues("StatefulSet.Name", statefulSetName)
+
+	if nodeKey != "" || nodeKeySecretRef != nil {
+		key := []byte(nodeKey)
+		if nodeKeySecretRef != nil {
+			secret := &corev1.Secret{}
+			isNotFound, err := r.fetchResource(secret, types.NamespacedName{Name: nodeKeySecretRef.Name, Namespace: CRInstance.Namespace})
+			if err != nil {
+				logger.Error(err, "Error on fetch the Secret...", "Secret.Name", nodeKeySecretRef.Name)
+				return nil, err
+			}
+			if isNotFound {
+				logger.V(warnLevel).Info("Node key Secret not found...", "Secret.Name", nodeKeySecretRef.Name)
+				return previous, nil
+			}
+			key = secret.Data[nodeKeySecretRef.Key]
+		}
+		peerID, err := getPeerID(key)
+		if err != nil {
+			logger.Error(err, "Error on deriving the peer ID from the node key...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "InvalidNodeKey", "The node key of %s is not valid: %v", statefulSetName, err)
+			return previous, nil
+		}
+		return []string{peerID}, nil
+	}
+
+	peerIDs := make([]string, replicas)
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		if int(ordinal) < len(previous) {
+			peerIDs[ordinal] = previous[ordinal]
+		}
+		pod := &corev1.Pod{}
+		podName := fmt.Sprintf("%s-%d", statefulSetName, ordinal)
+		isNotFound, err := r.fetchResource(pod, types.NamespacedName{Name: podName, Namespace: CRInstance.Namespace})
+		if err != nil {
+			logger.Error(err, "Error on fetch the Pod...", "Pod.Name", podName)
+			return nil, err
+		}
+		if isNotFound || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		peerID := ""
+		err = callNodeRPC(getPodRPCEndpoint(pod), "system_localPeerId", &peerID)
+		if err != nil {
+			logger.V(warnLevel).Info("Not able to get the peer ID of the node...", "Pod.Name", podName, "error", err.Error())
+			continue
+		}
+		peerIDs[ordinal] = peerID
+	}
+	return peerIDs, nil
+}
+
+// isPeerDiscoveryInProgress tells if a peer ID to be discovered through the RPC of the nodes is still unknown
+func isPeerDiscoveryInProgress(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	reservedPeers := CRInstance.Status.ReservedPeers
+	if reservedPeers == nil {
+		return false
+	}
+	for _, peerIDs := range [][]string{reservedPeers.Validators, reservedPeers.Sentries} {
+		for _, peerID := range peerIDs {
+			if peerID == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
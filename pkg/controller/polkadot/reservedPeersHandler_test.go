@@ -0,0 +1,94 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	// the well known node key of the development chains, and its peer ID
+	fakeNodeKey = "0000000000000000000000000000000000000000000000000000000000000001"
+	fakePeerID  = "12D3KooWEyoppNCUx8Yx66oV9fJnriXwCcXwDDUA2kj6vnc6iDEp"
+)
+
+func TestGetPeerID(t *testing.T) {
+	peerID, err := getPeerID([]byte(fakeNodeKey + "\n"))
+	if peerID != fakePeerID || err != nil {
+		t.Fatalf("getPeerID: (%v, %v)", peerID, err)
+	}
+	if _, err := getPeerID([]byte("0x01")); err == nil {
+		t.Fatalf("getPeerID: invalid node key accepted")
+	}
+}
+
+func TestHandleReservedPeers(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// the sentries have no node key, only the first one is running
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		return json.Unmarshal([]byte(`"sentry-0-peer-id"`), result)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Sentry.Replicas = 2
+	polkadot.Spec.Sentry.NodeKey = ""
+	polkadot.Spec.Sentry.ReservedValidatorID = ""
+	polkadot.Spec.Validator.ReservedSentryID = ""
+	polkadot.Spec.Validator.NodeKey = ""
+	polkadot.Spec.Validator.NodeKeySecretRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "node-key"}, Key: "key"}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "node-key"}, Data: map[string][]byte{"key": []byte(fakeNodeKey)}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: SentrySSName + "-0"}}
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.PodIP = "10.0.0.1"
+
+	client := newFakeClient(scheme, polkadot, secret, pod)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleReservedPeers(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleReservedPeers: (%v, %v)", isRequeueForced, err)
+	}
+	expected := &polkadotv1alpha1.ReservedPeersStatus{Validators: []string{fakePeerID}, Sentries: []string{"sentry-0-peer-id", ""}}
+	if !reflect.DeepEqual(polkadot.Status.ReservedPeers, expected) || !isPeerDiscoveryInProgress(polkadot) {
+		t.Fatalf("handleReservedPeers: unexpected reserved peers (%v)", polkadot.Status.ReservedPeers)
+	}
+
+	// the discovered peers are wired with each other
+	sentryCommands := strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(sentryCommands, "--reserved-nodes /dns4/"+getHeadlessServiceName(ServiceValidatorName)+"/tcp/-1/p2p/"+fakePeerID) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", sentryCommands)
+	}
+	validatorCommands := strings.Join(newStatefulSetValidator(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(validatorCommands, "--reserved-only --reserved-nodes /dns4/"+getHeadlessServiceName(ServiceSentryName)+"/tcp/-1/p2p/sentry-0-peer-id") {
+		t.Fatalf("newStatefulSetValidator: unexpected commands (%v)", validatorCommands)
+	}
+
+	// the reserved peer ID set in the CR takes precedence
+	polkadot.Spec.Validator.ReservedSentryID = "sentry-peer-id"
+	if _, err := reconciler.handleReservedPeers(polkadot); err != nil || polkadot.Status.ReservedPeers.Sentries != nil || isPeerDiscoveryInProgress(polkadot) {
+		t.Fatalf("handleReservedPeers: unexpected reserved peers (%v, %v)", polkadot.Status.ReservedPeers, err)
+	}
+
+	// the reserved peers are cleared for the other Kinds
+	polkadot.Spec.Kind = string(Sentry)
+	if _, err := reconciler.handleReservedPeers(polkadot); err != nil || polkadot.Status.ReservedPeers != nil {
+		t.Fatalf("handleReservedPeers: (%v)", err)
+	}
+}
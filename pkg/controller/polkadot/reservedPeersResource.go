@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// multiaddressPortRegexp matches the TCP port of a multiaddress
+var multiaddressPortRegexp = regexp.MustCompile(`/tcp/([0-9]+)`)
+
+// getPeerID returns the libp2p peer ID of an ed25519 node key, given as hex (as for --node-key) or as the raw 32 bytes
+// of a key file. The peer ID is the base58 identity multihash of the protobuf encoded public key
+func getPeerID(nodeKey []byte) (string, error) {
+	seed := nodeKey
+	if len(seed) != ed25519.SeedSize {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(nodeKey)), "0x"))
+		if err != nil {
+			return "", fmt.Errorf("invalid node key: %v", err)
+		}
+		seed = decoded
+	}
+	if len(seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("invalid node key: %d bytes instead of %d", len(seed), ed25519.SeedSize)
+	}
+	publicKey := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+	// KeyType Ed25519 (field 1) and Data (field 2) of the libp2p PublicKey message
+	encodedKey := append([]byte{0x08, 0x01, 0x12, byte(len(publicKey))}, publicKey...)
+	// identity multihash
+	multihash := append([]byte{0x00, byte(len(encodedKey))}, encodedKey...)
+	return getBase58(multihash), nil
+}
+
+func getBase58(data []byte) string {
+	result := []byte{}
+	value := new(big.Int).SetBytes(data)
+	base := big.NewInt(int64(len(base58Alphabet)))
+	mod := new(big.Int)
+	for value.Sign() > 0 {
+		value.DivMod(value, base, mod)
+		result = append(result, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		result = append(result, base58Alphabet[0])
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result)
+}
+
+func getReservedPeers(CRInstance *polkadotv1alpha1.Polkadot) polkadotv1alpha1.ReservedPeersStatus {
+	if CRInstance.Status.ReservedPeers == nil {
+		return polkadotv1alpha1.ReservedPeersStatus{}
+	}
+	return *CRInstance.Status.ReservedPeers
+}
+
+// getReservedNodesCommands returns the --reserved-nodes argument reaching the peers through a Service of the CR.
+// The peer ID set in the CR takes precedence over the discovered ones, no argument is returned while none is known
+func getReservedNodesCommands(serviceName string, p2pPort int32, peerID string, discoveredPeerIDs []string) []string {
+	peerIDs := discoveredPeerIDs
+	if peerID != "" {
+		peerIDs = []string{peerID}
+	}
+	addresses := []string{}
+	isAdded := map[string]bool{}
+	for _, id := range peerIDs {
+		if id == "" || isAdded[id] {
+			continue
+		}
+		isAdded[id] = true
+		addresses = append(addresses, fmt.Sprintf("/dns4/%s/tcp/%d/p2p/%s", serviceName, p2pPort, id))
+	}
+	if len(addresses) == 0 {
+		return []string{}
+	}
+	return append([]string{"--reserved-nodes"}, addresses...)
+}
+
+// getMultiaddressesPorts returns the distinct TCP ports of multiaddresses (e.g. /dns4/host/tcp/30333/p2p/id)
+func getMultiaddressesPorts(addresses []string) []int {
+	ports := []int{}
+	isAdded := map[int]bool{}
+	for _, address := range addresses {
+		match := multiaddressPortRegexp.FindStringSubmatch(address)
+		if match == nil {
+			continue
+		}
+		port, err := strconv.Atoi(match[1])
+		if err != nil || isAdded[port] {
+			continue
+		}
+		isAdded[port] = true
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// getExternalValidators returns the multiaddresses of the validators reserved by the sentries outside of the CR: the ones of
+// other clusters, and the validator running outside of Kubernetes fronted by the sentries
+func getExternalValidators(CRInstance *polkadotv1alpha1.Polkadot) []string {
+	sentry := CRInstance.Spec.Sentry
+	externalValidators := append([]string{}, sentry.ExternalValidators...)
+	if sentry.ExternalValidatorAddr != "" && sentry.ReservedValidatorID != "" {
+		externalValidators = append(externalValidators, fmt.Sprintf("%s/p2p/%s", sentry.ExternalValidatorAddr, sentry.ReservedValidatorID))
+	}
+	return externalValidators
+}
+
+// appendReservedNodes adds the multiaddresses of the reserved nodes running in other clusters to a --reserved-nodes argument
+func appendReservedNodes(reservedNodesCommands []string, externalAddresses []string) []string {
+	if len(externalAddresses) == 0 {
+		return reservedNodesCommands
+	}
+	if len(reservedNodesCommands) == 0 {
+		reservedNodesCommands = []string{"--reserved-nodes"}
+	}
+	return append(reservedNodesCommands, externalAddresses...)
+}
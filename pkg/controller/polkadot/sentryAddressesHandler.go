@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"strings"
+)
+
+// handleSentryAddresses exports the public multiaddresses of the sentries to the status, so that they can be listed in the
+// externalSentries of validators running in other clusters. A sentry is exported once its peer ID is known and the public
+// address of the role is resolved, the IP of its node being the one of its pod for the host network and the NodePorts
+func (r *ReconcilerPolkadot) handleSentryAddresses(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	var sentryAddresses []string
+	publicAddress := getPublicAddrCommands(CRInstance, "sentry")
+	if isSentryAddressesExported(CRInstance) && len(publicAddress) == 2 {
+		isAdded := map[string]bool{}
+		for ordinal, peerID := range getReservedPeers(CRInstance).Sentries {
+			if peerID == "" {
+				continue
+			}
+			address := publicAddress[1]
+			if strings.Contains(address, "$("+nodeIPEnv+")") {
+				pod := &corev1.Pod{}
+				podName := fmt.Sprintf("%s-%d", SentrySSName, ordinal)
+				isNotFound, err := r.fetchResource(pod, types.NamespacedName{Name: podName, Namespace: CRInstance.Namespace})
+				if err != nil {
+					logger.Error(err, "Error on fetch the Pod...", "Pod.Name", podName)
+					return NotForcedRequeue, err
+				}
+				if isNotFound || pod.Status.HostIP == "" {
+					continue
+				}
+				address = strings.Replace(address, "$("+nodeIPEnv+")", pod.Status.HostIP, 1)
+			}
+			address = fmt.Sprintf("%s/p2p/%s", address, peerID)
+			if !isAdded[address] {
+				isAdded[address] = true
+				sentryAddresses = append(sentryAddresses, address)
+			}
+		}
+	}
+	if reflect.DeepEqual(sentryAddresses, CRInstance.Status.SentryAddresses) {
+		return handleSkip()
+	}
+
+	CRInstance.Status.SentryAddresses = sentryAddresses
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Updated the sentry addresses...", "SentryAddresses", sentryAddresses)
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "SentryAddressesUpdated", "Updated the exported sentry addresses, %d sentries", len(sentryAddresses))
+	return NotForcedRequeue, nil
+}
+
+// isSentryAddressesExported tells if the sentries are reachable from other clusters, at the public address of the role
+func isSentryAddressesExported(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return isRoleDeployed(CRInstance, "sentry") && CRInstance.Spec.Sentry.AutoPublicAddr
+}
@@ -0,0 +1,100 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const fakeExternalSentry = "/dns4/sentry.b.example.com/tcp/30333/p2p/" + fakePeerID
+
+func TestHandleSentryAddresses(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		return json.Unmarshal([]byte(`"sentry-0-peer-id"`), result)
+	}
+
+	// the sentries are exposed on the host network, only the first one is scheduled
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 2
+	polkadot.Spec.Sentry.NodeKey = ""
+	polkadot.Spec.Sentry.AutoPublicAddr = true
+	polkadot.Spec.Sentry.ExternalValidators = []string{"/dns4/validator.a.example.com/tcp/30333/p2p/validator-peer-id"}
+	polkadot.Status.PublicAddresses = []polkadotv1alpha1.PublicAddress{{Role: "sentry", Address: "/ip4/$(" + nodeIPEnv + ")/tcp/30333"}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: SentrySSName + "-0"}}
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.PodIP = "10.0.0.1"
+	pod.Status.HostIP = "203.0.113.10"
+
+	client := newFakeClient(scheme, polkadot, pod)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	if _, err := reconciler.handleReservedPeers(polkadot); err != nil || polkadot.Status.ReservedPeers == nil {
+		t.Fatalf("handleReservedPeers: sentries not discovered (%v, %v)", polkadot.Status.ReservedPeers, err)
+	}
+	isRequeueForced, err := reconciler.handleSentryAddresses(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleSentryAddresses: (%v, %v)", isRequeueForced, err)
+	}
+	expected := []string{"/ip4/203.0.113.10/tcp/30333/p2p/sentry-0-peer-id"}
+	if !reflect.DeepEqual(polkadot.Status.SentryAddresses, expected) {
+		t.Fatalf("handleSentryAddresses: unexpected sentry addresses (%v)", polkadot.Status.SentryAddresses)
+	}
+
+	// the sentries are wired with the validators of the other clusters
+	sentryCommands := strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(sentryCommands, "--reserved-nodes "+polkadot.Spec.Sentry.ExternalValidators[0]) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", sentryCommands)
+	}
+
+	// the addresses are no longer exported without autoPublicAddr
+	polkadot.Spec.Sentry.AutoPublicAddr = false
+	if _, err := reconciler.handleSentryAddresses(polkadot); err != nil || polkadot.Status.SentryAddresses != nil {
+		t.Fatalf("handleSentryAddresses: unexpected sentry addresses (%v, %v)", polkadot.Status.SentryAddresses, err)
+	}
+}
+
+func TestNewStatefulSetValidatorExternalSentries(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.ExternalSentries = []string{fakeExternalSentry}
+
+	// the validator only peers with the sentries of the other clusters
+	commands := strings.Join(newStatefulSetValidator(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(commands, "--reserved-only --reserved-nodes "+fakeExternalSentry) {
+		t.Fatalf("newStatefulSetValidator: unexpected commands (%v)", commands)
+	}
+
+	// the local sentries come first
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Validator.ReservedSentryID = "sentry-peer-id"
+	commands = strings.Join(newStatefulSetValidator(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(commands, "--reserved-only --reserved-nodes /dns4/"+getHeadlessServiceName(ServiceSentryName)+"/tcp/-1/p2p/"+polkadot.Spec.Validator.ReservedSentryID+" "+fakeExternalSentry) {
+		t.Fatalf("newStatefulSetValidator: unexpected commands (%v)", commands)
+	}
+
+	// the P2P port of the sentries of the other clusters is open with the secure communication support
+	polkadot.Spec.SecureCommunicationSupport.Enabled = true
+	egress := newNetworkPolicyValidator(polkadot).Spec.Egress
+	if len(egress) != 3 || len(egress[2].Ports) != 1 || egress[2].Ports[0].Port.IntVal != 30333 {
+		t.Fatalf("newNetworkPolicyValidator: unexpected egress (%v)", egress)
+	}
+}
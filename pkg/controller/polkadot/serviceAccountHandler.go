@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r *ReconcilerPolkadot) handleServiceAccount(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	for _, serviceAccount := range newServiceAccounts(CRInstance) {
+		isForcedRequeue, err := r.handleServiceAccountGeneric(CRInstance, serviceAccount)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+func (r *ReconcilerPolkadot) handleServiceAccountGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *corev1.ServiceAccount) (bool, error) {
+
+	logger := getLogger(CRInstance).WithValues("ServiceAccount.Name", desiredResource.Name)
+
+	toBeFoundResource := &corev1.ServiceAccount{}
+	isNotFound, err := r.fetchResource(toBeFoundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the ServiceAccount...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("ServiceAccount not found...")
+		logger.Info("Creating a new ServiceAccount...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new ServiceAccount...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new ServiceAccount")
+		return ForcedRequeue, nil
+	}
+	foundResource := toBeFoundResource
+
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update ServiceAccount Error...")
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
+		logger.Info("Updated the ServiceAccount...")
+	}
+
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,69 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestHandleServiceAccount(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		options         polkadotv1alpha1.NodeOptions
+		isCreated       bool
+		isRequeueForced bool
+	}{
+		{
+			name:            "ServiceAccount not requested",
+			options:         polkadotv1alpha1.NodeOptions{ServiceAccountName: "validator"},
+			isCreated:       false,
+			isRequeueForced: false,
+		},
+		{
+			name:            "ServiceAccount created",
+			options:         polkadotv1alpha1.NodeOptions{ServiceAccountName: "validator", CreateServiceAccount: true, ServiceAccountAnnotations: map[string]string{"iam": "role"}},
+			isCreated:       true,
+			isRequeueForced: true,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A Polkadot object with metadata and spec.
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(Validator)
+			polkadot.Spec.Validator.NodeOptions = test.options
+
+			// Create a fake client to mock API calls.
+			client := newFakeClient(scheme, polkadot)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+			isRequeueForced, err := reconciler.handleServiceAccount(polkadot)
+			if isRequeueForced != test.isRequeueForced || err != nil {
+				t.Fatalf("handleServiceAccount: (%v, %v)", isRequeueForced, err)
+			}
+
+			found := &corev1.ServiceAccount{}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: test.options.ServiceAccountName}, found)
+			if (err == nil) != test.isCreated {
+				t.Fatalf("handleServiceAccount: unexpected ServiceAccount (%v)", err)
+			}
+			if test.isCreated && found.Annotations["iam"] != "role" {
+				t.Fatalf("handleServiceAccount: annotations not propagated (%v)", found.Annotations)
+			}
+		})
+	}
+}
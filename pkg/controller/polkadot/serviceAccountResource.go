@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newServiceAccounts returns the ServiceAccounts to be created by the operator for the roles of the CR
+func newServiceAccounts(CRInstance *polkadotv1alpha1.Polkadot) []*corev1.ServiceAccount {
+	serviceAccounts := []*corev1.ServiceAccount{}
+	names := map[string]bool{}
+	for _, rr := range getRoleResources(CRInstance) {
+		if !rr.options.CreateServiceAccount || rr.options.ServiceAccountName == "" || names[rr.options.ServiceAccountName] {
+			continue
+		}
+		names[rr.options.ServiceAccountName] = true
+		serviceAccounts = append(serviceAccounts, newServiceAccount(CRInstance, rr))
+	}
+	return serviceAccounts
+}
+
+func newServiceAccount(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources) *corev1.ServiceAccount {
+	labels := getAppLabels()
+	labels["role"] = rr.role
+
+	annotations := getCopy(CRInstance.Spec.Metadata.Annotations)
+	for key, value := range rr.options.ServiceAccountAnnotations {
+		annotations[key] = value
+	}
+
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rr.options.ServiceAccountName,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: annotations,
+		},
+	}
+}
@@ -3,7 +3,6 @@
 package polkadot
 
 import (
-	"github.com/go-logr/logr"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -11,11 +10,18 @@ import (
 
 func (r *ReconcilerPolkadot) handleService(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
 	handler := getHandlerService(CRInstance)
-	return handler.handleServiceSpecific(r,CRInstance)
+	isForcedRequeue, err := handler.handleServiceSpecific(r,CRInstance)
+	if isForcedRequeue == ForcedRequeue || err != nil || !isRpcNodeAddOn(CRInstance) {
+		return isForcedRequeue, err
+	}
+	return r.handleRoleServices(CRInstance, newServiceRpcNode(CRInstance))
 }
 
 //pattern factory
 func getHandlerService(CRInstance *polkadotv1alpha1.Polkadot) IHandlerService {
+	if len(CRInstance.Spec.NodePools) > 0 {
+		return &handlerServiceNodePools{}
+	}
 	if CRKind(CRInstance.Spec.Kind) == Validator {
 		return &handlerServiceValidator{}
 	}
@@ -25,6 +31,18 @@ func getHandlerService(CRInstance *polkadotv1alpha1.Polkadot) IHandlerService {
 	if CRKind(CRInstance.Spec.Kind) == SentryAndValidator {
 		return &handlerServiceSentryAndValidator{}
 	}
+	if CRKind(CRInstance.Spec.Kind) == Bootnode {
+		return &handlerServiceBootnode{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == Archive {
+		return &handlerServiceArchive{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == RpcNode {
+		return &handlerServiceRpcNode{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == Collator {
+		return &handlerServiceCollator{}
+	}
 	return &handlerServiceDefault{}
 }
 
@@ -36,23 +54,89 @@ type IHandlerService interface {
 type handlerServiceValidator struct {
 }
 func (h *handlerServiceValidator) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
-	return r.handleServiceGeneric(CRInstance, newServiceValidator(CRInstance))
+	return r.handleRoleServices(CRInstance, newServiceValidator(CRInstance))
 }
 
 type handlerServiceSentry struct {
 }
 func (h *handlerServiceSentry) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
-	return r.handleServiceGeneric(CRInstance, newServiceSentry(CRInstance))
+	return r.handleRoleServices(CRInstance, newServiceSentry(CRInstance))
 }
 
 type handlerServiceSentryAndValidator struct {
 }
 func (h *handlerServiceSentryAndValidator) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
-	isForcedRequeue, err := r.handleServiceGeneric(CRInstance, newServiceSentry(CRInstance))
+	isForcedRequeue, err := r.handleRoleServices(CRInstance, newServiceSentry(CRInstance))
+	if isForcedRequeue == ForcedRequeue || err != nil {
+		return isForcedRequeue, err
+	}
+	return r.handleRoleServices(CRInstance, newServiceValidator(CRInstance))
+}
+
+type handlerServiceBootnode struct {
+}
+func (h *handlerServiceBootnode) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	isForcedRequeue, err := r.handleRoleServices(CRInstance, newServiceBootnode(CRInstance))
 	if isForcedRequeue == ForcedRequeue || err != nil {
 		return isForcedRequeue, err
 	}
-	return r.handleServiceGeneric(CRInstance, newServiceValidator(CRInstance))
+	// one dedicated Service for each bootnode, so that every identity is reachable on a stable endpoint
+	for ordinal := int32(0); ordinal < CRInstance.Spec.Bootnode.Replicas; ordinal++ {
+		isForcedRequeue, err = r.handleServiceGeneric(CRInstance, newServiceBootnodePod(CRInstance, ordinal))
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+type handlerServiceArchive struct {
+}
+func (h *handlerServiceArchive) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	return r.handleRoleServices(CRInstance, newServiceArchive(CRInstance))
+}
+
+type handlerServiceRpcNode struct {
+}
+func (h *handlerServiceRpcNode) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	return r.handleRoleServices(CRInstance, newServiceRpcNode(CRInstance))
+}
+
+type handlerServiceCollator struct {
+}
+func (h *handlerServiceCollator) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	return r.handleRoleServices(CRInstance, newServiceCollator(CRInstance))
+}
+
+// handlerServiceNodePools handles the Services of each node pool, with the strategy of its role
+type handlerServiceNodePools struct {
+}
+func (h *handlerServiceNodePools) handleServiceSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	for _, pool := range CRInstance.Spec.NodePools {
+		isForcedRequeue, err := getHandlerServiceRole(pool.Role).handleServiceSpecific(r, CRInstance)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+func getHandlerServiceRole(role string) IHandlerService {
+	switch role {
+	case "sentry":
+		return &handlerServiceSentry{}
+	case "validator":
+		return &handlerServiceValidator{}
+	case "bootnode":
+		return &handlerServiceBootnode{}
+	case "archive":
+		return &handlerServiceArchive{}
+	case "rpcnode":
+		return &handlerServiceRpcNode{}
+	case "collator":
+		return &handlerServiceCollator{}
+	}
+	return &handlerServiceDefault{}
 }
 
 type handlerServiceDefault struct {
@@ -61,9 +145,20 @@ func (h *handlerServiceDefault) handleServiceSpecific(r *ReconcilerPolkadot, CRI
 	return handleSkip()
 }
 
+// handleRoleServices handles the Service of the clients of a role and its headless Service
+func (r *ReconcilerPolkadot) handleRoleServices(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *corev1.Service) (bool, error) {
+	for _, service := range getRoleServices(CRInstance, desiredResource) {
+		isForcedRequeue, err := r.handleServiceGeneric(CRInstance, service)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
 func (r *ReconcilerPolkadot) handleServiceGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *corev1.Service) (bool, error) {
 
-	logger := log.WithValues("Service.Namespace", desiredResource.Namespace, "Service.Name", desiredResource.Name)
+	logger := getLogger(CRInstance).WithValues("Service.Name", desiredResource.Name)
 
 	toBeFoundResource := &corev1.Service{}
 	isNotFound,err := r.fetchResource(toBeFoundResource,types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
@@ -72,32 +167,30 @@ func (r *ReconcilerPolkadot) handleServiceGeneric(CRInstance *polkadotv1alpha1.P
 		return NotForcedRequeue, err
 	}
 	if isNotFound == true {
-		logger.Info("Service not found...")
+		logger.V(debugLevel).Info("Service not found...")
 		logger.Info("Creating a new Service...")
-		err := r.createResource(desiredResource, CRInstance)
+		err := r.applyResource(desiredResource, CRInstance)
 		if err != nil {
 			logger.Error(err, "Error on creating a new Service...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the Service %s: %v", desiredResource.Name, err)
 			return NotForcedRequeue, err
 		}
 		logger.Info("Created the new Service")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the Service %s", desiredResource.Name)
 		return ForcedRequeue, nil
 	}
 	foundResource := toBeFoundResource
 
-	if areServicesDifferent(foundResource, desiredResource, logger) {
-		logger.Info("Updating the Service...")
-		err := r.updateResource(desiredResource)
-		if err != nil {
-			logger.Error(err, "Update Service Error...")
-			return NotForcedRequeue, err
-		}
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Service Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the Service %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
 		logger.Info("Updated the Service...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the Service %s", desiredResource.Name)
 	}
 
 	return NotForcedRequeue, nil
 }
-
-func areServicesDifferent(currentService *corev1.Service, desiredService *corev1.Service, logger logr.Logger) bool {
-	result := false
-	return result
-}
\ No newline at end of file
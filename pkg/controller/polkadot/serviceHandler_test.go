@@ -2,9 +2,9 @@ package polkadot
 
 import (
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -45,7 +45,7 @@ func TestServiceGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot,test.newResource}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isRequeueForced, err := reconciler.handleServiceGeneric(polkadot,test.newResource)
@@ -61,7 +61,7 @@ func TestServiceGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isRequeueForced, err := reconciler.handleServiceGeneric(polkadot,test.newResource)
@@ -72,3 +72,90 @@ func TestServiceGeneric(t *testing.T) {
 	}
 }
 
+func TestNewServiceType(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+
+	// the default types of the roles
+	if serviceType := newServiceSentry(polkadot).Spec.Type; serviceType != corev1.ServiceTypeNodePort {
+		t.Fatalf("newServiceSentry: unexpected type (%v)", serviceType)
+	}
+	if serviceType := newServiceValidator(polkadot).Spec.Type; serviceType != corev1.ServiceTypeClusterIP {
+		t.Fatalf("newServiceValidator: unexpected type (%v)", serviceType)
+	}
+
+	// the sentries get a public endpoint, while the validator stays internal
+	polkadot.Spec.Sentry.ServiceType = corev1.ServiceTypeLoadBalancer
+	polkadot.Spec.RpcNode.ServiceType = corev1.ServiceTypeClusterIP
+	if serviceType := newServiceSentry(polkadot).Spec.Type; serviceType != corev1.ServiceTypeLoadBalancer {
+		t.Fatalf("newServiceSentry: unexpected type (%v)", serviceType)
+	}
+	if serviceType := newServiceRpcNode(polkadot).Spec.Type; serviceType != corev1.ServiceTypeClusterIP {
+		t.Fatalf("newServiceRpcNode: unexpected type (%v)", serviceType)
+	}
+	if serviceType := newServiceValidator(polkadot).Spec.Type; serviceType != corev1.ServiceTypeClusterIP {
+		t.Fatalf("newServiceValidator: unexpected type (%v)", serviceType)
+	}
+}
+
+func TestNewServiceExternalDNS(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	ttl := int32(60)
+	polkadot.Spec.Sentry.ExternalDNS = polkadotv1alpha1.ExternalDNS{Hostname: "sentry.example.com", TTL: &ttl}
+	polkadot.Spec.Bootnode.ExternalDNS = polkadotv1alpha1.ExternalDNS{Hostname: "bootnode.example.com"}
+
+	annotations := newServiceSentry(polkadot).Annotations
+	if annotations[externalDNSHostname] != "sentry.example.com" || annotations[externalDNSTTL] != "60" {
+		t.Fatalf("newServiceSentry: unexpected annotations (%v)", annotations)
+	}
+	if annotations := newServiceValidator(polkadot).Annotations; annotations[externalDNSHostname] != "" {
+		t.Fatalf("newServiceValidator: unexpected annotations (%v)", annotations)
+	}
+
+	// each bootnode is published under its own name
+	if hostname := newServiceBootnodePod(polkadot, 1).Annotations[externalDNSHostname]; hostname != "1.bootnode.example.com" {
+		t.Fatalf("newServiceBootnodePod: unexpected hostname (%v)", hostname)
+	}
+	if hostname := newServiceBootnode(polkadot).Annotations[externalDNSHostname]; hostname != "" {
+		t.Fatalf("newServiceBootnode: unexpected hostname (%v)", hostname)
+	}
+}
+
+func TestGetRoleServices(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Sentry.ExternalDNS = polkadotv1alpha1.ExternalDNS{Hostname: "sentry.example.com"}
+
+	// the internal validator is peered through its headless Service only
+	services := getRoleServices(polkadot, newServiceValidator(polkadot))
+	if len(services) != 2 || services[0].Name != ServiceValidatorName || services[1].Name != getHeadlessServiceName(ServiceValidatorName) {
+		t.Fatalf("getRoleServices: unexpected services (%v)", services)
+	}
+	for _, port := range services[0].Spec.Ports {
+		if port.Name == P2PPortName {
+			t.Fatalf("getRoleServices: unexpected P2P port (%v)", services[0].Spec.Ports)
+		}
+	}
+	headless := services[1].Spec
+	if headless.ClusterIP != corev1.ClusterIPNone || !headless.PublishNotReadyAddresses || len(headless.Ports) != 1 || headless.Ports[0].Name != P2PPortName {
+		t.Fatalf("getRoleServices: unexpected headless Service (%v)", headless)
+	}
+
+	// the public P2P port of the sentries stays on their NodePort Service, the records of external-dns on it
+	services = getRoleServices(polkadot, newServiceSentry(polkadot))
+	if ports := services[0].Spec.Ports; len(ports) != len(newServiceSentry(polkadot).Spec.Ports) {
+		t.Fatalf("getRoleServices: unexpected ports (%v)", ports)
+	}
+	if hostname := services[1].Annotations[externalDNSHostname]; hostname != "" {
+		t.Fatalf("getRoleServices: unexpected hostname of the headless Service (%v)", hostname)
+	}
+
+	// the pods of the StatefulSets get their DNS names from the headless Services
+	if serviceName := newStatefulSetSentry(polkadot).Spec.ServiceName; serviceName != getHeadlessServiceName(ServiceSentryName) {
+		t.Fatalf("newStatefulSetSentry: unexpected service name (%v)", serviceName)
+	}
+}
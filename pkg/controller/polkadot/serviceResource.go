@@ -3,8 +3,10 @@
 package polkadot
 
 import (
+	"fmt"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/config"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -12,24 +14,135 @@ import (
 
 func newServiceSentry(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
 	labels := getSentrylabels()
-	return getService(ServiceSentryName,CRInstance,labels,corev1.ServiceTypeNodePort)
+	serviceType := getServiceType(CRInstance.Spec.Sentry.NodeOptions, corev1.ServiceTypeNodePort)
+	service := getService(ServiceSentryName,CRInstance,labels,serviceType)
+	externalDNS := CRInstance.Spec.Sentry.ExternalDNS
+	service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, externalDNS.Hostname)
+	return service
 }
 
 func newServiceValidator(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
 	labels := getValidatorLabels()
 	serviceType := corev1.ServiceTypeClusterIP
-	if CRKind(CRInstance.Spec.Kind) == Validator {
+	if !isSentryAndValidator(CRInstance) {
 		serviceType = corev1.ServiceTypeNodePort
 	}
-	return getService(ServiceValidatorName,CRInstance,labels,serviceType)
+	serviceType = getServiceType(CRInstance.Spec.Validator.NodeOptions, serviceType)
+	service := getService(ServiceValidatorName,CRInstance,labels,serviceType)
+	externalDNS := CRInstance.Spec.Validator.ExternalDNS
+	service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, externalDNS.Hostname)
+	// the sentries reach the validator through its Service, which only selects the active validator pod
+	if isHighAvailabilityEnabled(CRInstance) && CRInstance.Status.Failover != nil {
+		service.Spec.Selector = getCopy(labels)
+		service.Spec.Selector[appsv1.StatefulSetPodNameLabel] = CRInstance.Status.Failover.ActivePod
+	}
+	return service
+}
+
+func newServiceBootnode(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
+	labels := getBootnodeLabels()
+	return getService(ServiceBootnodeName,CRInstance,labels,corev1.ServiceTypeClusterIP)
+}
+
+func newServiceBootnodePod(CRInstance *polkadotv1alpha1.Polkadot, ordinal int32) *corev1.Service {
+	labels := getBootnodeLabels()
+	podName := fmt.Sprintf("%s-%d", BootnodeSSName, ordinal)
+	serviceType := getServiceType(CRInstance.Spec.Bootnode.NodeOptions, corev1.ServiceTypeNodePort)
+	service := getService(fmt.Sprintf("%s-%d", ServiceBootnodeName, ordinal),CRInstance,labels,serviceType)
+	service.Spec.Selector = getCopy(labels)
+	service.Spec.Selector[appsv1.StatefulSetPodNameLabel] = podName
+	// each bootnode gets its own record, its peer ID is bound to its address
+	externalDNS := CRInstance.Spec.Bootnode.ExternalDNS
+	if externalDNS.Hostname != "" {
+		service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, fmt.Sprintf("%d.%s", ordinal, externalDNS.Hostname))
+	}
+	return service
+}
+
+func newServiceArchive(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
+	labels := getArchiveLabels()
+	serviceType := getServiceType(CRInstance.Spec.Archive.NodeOptions, corev1.ServiceTypeClusterIP)
+	service := getService(ServiceArchiveName,CRInstance,labels,serviceType)
+	externalDNS := CRInstance.Spec.Archive.ExternalDNS
+	service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, externalDNS.Hostname)
+	return service
+}
+
+func newServiceCollator(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
+	labels := getCollatorLabels()
+	serviceType := getServiceType(CRInstance.Spec.Collator.NodeOptions, corev1.ServiceTypeNodePort)
+	service := getService(ServiceCollatorName,CRInstance,labels,serviceType)
+	externalDNS := CRInstance.Spec.Collator.ExternalDNS
+	service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, externalDNS.Hostname)
+	return service
+}
+
+// newServiceRpcNode load balances the RPC traffic among the RPC nodes. Only the ready pods are added to the endpoints,
+// and the client health check used as readiness probe fails while the node is syncing, so only the synced replicas are served
+func newServiceRpcNode(CRInstance *polkadotv1alpha1.Polkadot) *corev1.Service {
+	labels := getRpcNodeLabels()
+	serviceType := getServiceType(CRInstance.Spec.RpcNode.NodeOptions, corev1.ServiceTypeLoadBalancer)
+	service := getService(ServiceRpcNodeName,CRInstance,labels,serviceType)
+	service.Spec.Ports = getServicePortsRPC(CRInstance)
+	service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	externalDNS := CRInstance.Spec.RpcNode.ExternalDNS
+	service.Annotations = getExternalDNSAnnotations(service.Annotations, externalDNS, externalDNS.Hostname)
+	return service
+}
+
+// getRoleServices splits the Service of a role in the Service of its clients, with the RPC, WS and metrics ports, and the
+// headless Service of its P2P port. The P2P port stays on the Service of the clients when it is exposed outside of the
+// cluster (NodePort or LoadBalancer), as the public address of the nodes
+func getRoleServices(CRInstance *polkadotv1alpha1.Polkadot, service *corev1.Service) []*corev1.Service {
+	clientService := service.DeepCopy()
+	if clientService.Spec.Type == corev1.ServiceTypeClusterIP {
+		ports := []corev1.ServicePort{}
+		for _, port := range clientService.Spec.Ports {
+			if port.Name != P2PPortName {
+				ports = append(ports, port)
+			}
+		}
+		clientService.Spec.Ports = ports
+	}
+	return []*corev1.Service{clientService, newServiceHeadless(CRInstance, service)}
+}
+
+// newServiceHeadless is the governing Service of the StatefulSet of a role, giving each pod a stable DNS name for the
+// P2P traffic, e.g. sentry-sset-0.sentry-service-headless. The addresses of the pods are published before they are ready,
+// so that the nodes peer while syncing. It selects the same pods as the Service of the role
+func newServiceHeadless(CRInstance *polkadotv1alpha1.Polkadot, service *corev1.Service) *corev1.Service {
+	annotations := getCopy(service.Annotations)
+	// the records of external-dns are the ones of the Service of the role
+	delete(annotations, externalDNSHostname)
+	delete(annotations, externalDNSTTL)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getHeadlessServiceName(service.Name),
+			Namespace:   service.Namespace,
+			Labels:      getCopy(service.Labels),
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			Ports:                    []corev1.ServicePort{getP2PPort(CRInstance)},
+			Selector:                 getCopy(service.Spec.Selector),
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+func getHeadlessServiceName(serviceName string) string {
+	return serviceName + "-headless"
 }
 
 func getService(name string, CRInstance *polkadotv1alpha1.Polkadot, labels  map[string]string, serviceType corev1.ServiceType) *corev1.Service{
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: CRInstance.Namespace,
-			Labels:    labels,
+			Name:        name,
+			Namespace:   CRInstance.Namespace,
+			Labels:      getCopyLabelsWithCustom(labels, CRInstance.Spec.Metadata.Labels),
+			Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
 		},
 		Spec: corev1.ServiceSpec{
 			Type:     serviceType,
@@ -39,42 +152,115 @@ func getService(name string, CRInstance *polkadotv1alpha1.Polkadot, labels  map[
 	}
 }
 
+// getServiceType returns the Service type set in the options of a role, or the default one of the role
+func getServiceType(options polkadotv1alpha1.NodeOptions, defaultServiceType corev1.ServiceType) corev1.ServiceType {
+	if options.ServiceType != "" {
+		return options.ServiceType
+	}
+	return defaultServiceType
+}
+
+// getExternalDNSAnnotations adds to the annotations of a Service, or of an Ingress, the ones publishing its hostname through
+// external-dns. The records of a LoadBalancer Service point to its external IPs, the ones of a NodePort Service to the nodes
+func getExternalDNSAnnotations(annotations map[string]string, externalDNS polkadotv1alpha1.ExternalDNS, hostname string) map[string]string {
+	if hostname != "" {
+		annotations[externalDNSHostname] = hostname
+	}
+	if externalDNS.TTL != nil {
+		annotations[externalDNSTTL] = fmt.Sprint(*externalDNS.TTL)
+	}
+	return annotations
+}
+
+// getPorts returns the listen ports of the nodes of the CR, the ones of the preset of the chain or the ones configured in the operator
+// apply to the ports not set
+func getPorts(CRInstance *polkadotv1alpha1.Polkadot) polkadotv1alpha1.Ports {
+	defaultPorts := polkadotv1alpha1.Ports{
+		P2P:        int32(config.P2PPortEnvVar.Value),
+		RPC:        int32(config.RPCPortEnvVar.Value),
+		WS:         int32(config.WSPortEnvVar.Value),
+		Prometheus: int32(config.MetricsPortEnvVar.Value),
+	}
+	if preset := getChainPreset(CRInstance); preset != nil {
+		defaultPorts = polkadotv1alpha1.Ports{P2P: preset.P2PPort, RPC: preset.RPCPort, WS: preset.WSPort, Prometheus: preset.MetricsPort}
+	}
+	ports := CRInstance.Spec.Ports
+	if ports.P2P == 0 {
+		ports.P2P = defaultPorts.P2P
+	}
+	if ports.RPC == 0 {
+		ports.RPC = defaultPorts.RPC
+	}
+	if ports.WS == 0 {
+		ports.WS = defaultPorts.WS
+	}
+	if ports.Prometheus == 0 {
+		ports.Prometheus = defaultPorts.Prometheus
+	}
+	return ports
+}
+
 func getServicePorts(CRInstance *polkadotv1alpha1.Polkadot) []corev1.ServicePort{
 	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
+	ports := getPorts(CRInstance)
 
 	service := []corev1.ServicePort{
-		{
-			Name:       P2PPortName,
-			Port:       int32(config.P2PPortEnvVar.Value),
-			TargetPort: intstr.FromInt(config.P2PPortEnvVar.Value),
-			Protocol:   "TCP",
-		},
+		getP2PPort(CRInstance),
 		{
 			Name:       RPCPortName,
-			Port:       int32(config.RPCPortEnvVar.Value),
-			TargetPort: intstr.FromInt(config.RPCPortEnvVar.Value),
+			Port:       ports.RPC,
+			TargetPort: intstr.FromInt(int(ports.RPC)),
 			Protocol:   "TCP",
 		},
 		{
 			Name:       WSPortName,
-			Port:       int32(config.WSPortEnvVar.Value),
-			TargetPort: intstr.FromInt(config.WSPortEnvVar.Value),
+			Port:       ports.WS,
+			TargetPort: intstr.FromInt(int(ports.WS)),
 			Protocol:   "TCP",
 		},
 	}
 
 	if isMetricsSupportEnabled == true{
-		service = append(service,*getMetricsPort())
+		service = append(service,*getMetricsPort(CRInstance))
 	}
 
 	return service
 }
 
-func getMetricsPort() *corev1.ServicePort{
+func getP2PPort(CRInstance *polkadotv1alpha1.Polkadot) corev1.ServicePort{
+	port := getPorts(CRInstance).P2P
+	return corev1.ServicePort{
+		Name:       P2PPortName,
+		Port:       port,
+		TargetPort: intstr.FromInt(int(port)),
+		Protocol:   "TCP",
+	}
+}
+
+func getServicePortsRPC(CRInstance *polkadotv1alpha1.Polkadot) []corev1.ServicePort{
+	ports := getPorts(CRInstance)
+	return []corev1.ServicePort{
+		{
+			Name:       RPCPortName,
+			Port:       ports.RPC,
+			TargetPort: intstr.FromInt(int(ports.RPC)),
+			Protocol:   "TCP",
+		},
+		{
+			Name:       WSPortName,
+			Port:       ports.WS,
+			TargetPort: intstr.FromInt(int(ports.WS)),
+			Protocol:   "TCP",
+		},
+	}
+}
+
+func getMetricsPort(CRInstance *polkadotv1alpha1.Polkadot) *corev1.ServicePort{
+	port := getPorts(CRInstance).Prometheus
 	return &corev1.ServicePort{
 		Name:       metricsPortName,
-		Port:       int32(config.MetricsPortEnvVar.Value),
-		TargetPort: intstr.FromInt(config.MetricsPortEnvVar.Value),
+		Port:       port,
+		TargetPort: intstr.FromInt(int(port)),
 		Protocol:   "TCP",
 	}
 }
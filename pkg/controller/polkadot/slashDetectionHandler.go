@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+// handleSlashDetection reads the offences and the slashes of the stash of the validator on chain, through the RPC endpoint
+// of the nodes of the CR. They are reported in the status, by the OffenceDetected and Slashed conditions, by events and by
+// the metrics of the operator. The chain is polled at each reconcile, the CR being requeued every slashDetectionPeriod
+func (r *ReconcilerPolkadot) handleSlashDetection(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+	if !isSlashDetectionEnabled(CRInstance) {
+		return r.handleSlashDetectionDisabled(CRInstance)
+	}
+
+	endpoint := getServiceRPCEndpoint(getChainServiceName(CRInstance), CRInstance.Namespace, getPorts(CRInstance).RPC)
+	slashes, err := getStashSlashes(endpoint, getSlashDetectionStash(CRInstance))
+	if err != nil {
+		logger.V(warnLevel).Info("Not able to read the slashes of the stash...", "error", err.Error())
+		return handleSkip()
+	}
+	slashed := isRecentlySlashed(slashes, getSlashRecentEras(CRInstance))
+	observeSlashes(CRInstance.Namespace, CRInstance.Name, slashes, slashed)
+
+	previous := CRInstance.Status.DeepCopy()
+	CRInstance.Status.Slashes = slashes
+	setSlashConditions(CRInstance, slashed, metav1.Now())
+	if reflect.DeepEqual(previous, &CRInstance.Status) {
+		return handleSkip()
+	}
+	err = r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+
+	previousUnapplied := int32(0)
+	var previousLastSlashEra *int64
+	if previous.Slashes != nil && previous.Slashes.Stash == slashes.Stash {
+		previousUnapplied = previous.Slashes.UnappliedSlashes
+		previousLastSlashEra = previous.Slashes.LastSlashEra
+	}
+	if slashes.UnappliedSlashes > previousUnapplied {
+		logger.V(warnLevel).Info("Offence of the validator detected...", "Stash", slashes.Stash, "UnappliedSlashes", slashes.UnappliedSlashes)
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "OffenceDetected", "%d slashes of reported offences of the stash %s are not applied yet", slashes.UnappliedSlashes, slashes.Stash)
+	}
+	if slashed && !reflect.DeepEqual(previousLastSlashEra, slashes.LastSlashEra) {
+		logger.V(warnLevel).Info("Slash of the validator detected...", "Stash", slashes.Stash, "Era", *slashes.LastSlashEra)
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "Slashed", "The stash %s has been slashed in era %d", slashes.Stash, *slashes.LastSlashEra)
+	}
+	return NotForcedRequeue, nil
+}
+
+// handleSlashDetectionDisabled removes the slashes from the status and from the metrics
+func (r *ReconcilerPolkadot) handleSlashDetectionDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	deleteSlashMetrics(CRInstance.Namespace, CRInstance.Name)
+
+	previous := CRInstance.Status.DeepCopy()
+	CRInstance.Status.Slashes = nil
+	removeCondition(&CRInstance.Status, OffenceDetectedType)
+	removeCondition(&CRInstance.Status, SlashedType)
+	if reflect.DeepEqual(previous, &CRInstance.Status) {
+		return handleSkip()
+	}
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		getLogger(CRInstance).Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	return NotForcedRequeue, nil
+}
+
+func isSlashDetectionEnabled(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	return CRInstance.Spec.Validator.SlashDetection.Enabled && isRoleDeployed(CRInstance, "validator")
+}
+
+// getSlashDetectionStash returns the watched stash, the one of the staking bootstrap by default
+func getSlashDetectionStash(CRInstance *polkadotv1alpha1.Polkadot) string {
+	if CRInstance.Spec.Validator.SlashDetection.Stash != "" {
+		return CRInstance.Spec.Validator.SlashDetection.Stash
+	}
+	return CRInstance.Spec.Validator.Staking.Stash
+}
+
+func getSlashRecentEras(CRInstance *polkadotv1alpha1.Polkadot) int64 {
+	if CRInstance.Spec.Validator.SlashDetection.RecentEras == 0 {
+		return slashRecentEras
+	}
+	return int64(CRInstance.Spec.Validator.SlashDetection.RecentEras)
+}
+
+// isRecentlySlashed tells if the last slash of the stash is one of the recent eras, the active one included
+func isRecentlySlashed(slashes *polkadotv1alpha1.SlashesStatus, recentEras int64) bool {
+	return slashes.LastSlashEra != nil && *slashes.LastSlashEra+recentEras > slashes.ActiveEra
+}
+
+// getStashSlashes reads the active era, the slashing spans of the stash and the unapplied slashes of the Staking pallet.
+// The spans of a stash are stored once it is slashed, the era of its last slash being their third field
+func getStashSlashes(endpoint string, stash string) (*polkadotv1alpha1.SlashesStatus, error) {
+	accountID, err := decodeSS58Address(stash)
+	if err != nil {
+		return nil, err
+	}
+
+	activeEra, err := getStorage(endpoint, getStorageKey("Staking", "ActiveEra"))
+	if err != nil {
+		return nil, err
+	}
+	if activeEra == nil {
+		return nil, fmt.Errorf("no active era")
+	}
+	era, err := decodeUint32(activeEra, 0)
+	if err != nil {
+		return nil, err
+	}
+	slashes := &polkadotv1alpha1.SlashesStatus{Stash: stash, ActiveEra: int64(era)}
+
+	spans, err := getStorage(endpoint, getStorageKey("Staking", "SlashingSpans", accountID))
+	if err != nil {
+		return nil, err
+	}
+	if spans != nil {
+		lastSlashEra, err := decodeUint32(spans, 8)
+		if err != nil {
+			return nil, err
+		}
+		slashes.LastSlashEra = new(int64)
+		*slashes.LastSlashEra = int64(lastSlashEra)
+	}
+
+	keys, err := getStorageKeys(endpoint, getStorageKey("Staking", "UnappliedSlashes"), unappliedSlashesCount)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		// the slashes of an era are removed once applied, possibly since the keys have been read
+		value, err := getStorage(endpoint, key)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		count, err := countUnappliedSlashes(value, accountID)
+		if err != nil {
+			return nil, err
+		}
+		slashes.UnappliedSlashes += count
+	}
+	return slashes, nil
+}
+
+// setSlashConditions reports the unapplied slashes of the stash with OffenceDetected, and its recent slash with Slashed
+func setSlashConditions(CRInstance *polkadotv1alpha1.Polkadot, slashed bool, now metav1.Time) {
+	slashes := CRInstance.Status.Slashes
+
+	offenceDetected := polkadotv1alpha1.PolkadotCondition{Type: OffenceDetectedType, Status: corev1.ConditionFalse, Reason: "NoUnappliedSlashes"}
+	if slashes.UnappliedSlashes > 0 {
+		offenceDetected.Status = corev1.ConditionTrue
+		offenceDetected.Reason = "UnappliedSlashes"
+		offenceDetected.Message = fmt.Sprintf("%d slashes of reported offences of the stash %s are not applied yet", slashes.UnappliedSlashes, slashes.Stash)
+	}
+	setCondition(&CRInstance.Status, offenceDetected, now)
+
+	slashedCondition := polkadotv1alpha1.PolkadotCondition{Type: SlashedType, Status: corev1.ConditionFalse, Reason: "NoRecentSlash"}
+	if slashed {
+		slashedCondition.Status = corev1.ConditionTrue
+		slashedCondition.Reason = "RecentSlash"
+		slashedCondition.Message = fmt.Sprintf("the stash %s has been slashed in era %d", slashes.Stash, *slashes.LastSlashEra)
+	}
+	setCondition(&CRInstance.Status, slashedCondition, now)
+}
@@ -0,0 +1,126 @@
+package polkadot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+)
+
+const aliceStash = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+
+func TestGetStorageKey(t *testing.T) {
+	if key := getStorageKey("System", "Account"); key != "0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9" {
+		t.Fatalf("getStorageKey: unexpected key (%v)", key)
+	}
+	accountID, err := decodeSS58Address(aliceStash)
+	if err != nil || hex.EncodeToString(accountID) != "d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d" {
+		t.Fatalf("decodeSS58Address: (%x, %v)", accountID, err)
+	}
+	if _, err := decodeSS58Address("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQ0"); err == nil {
+		t.Fatalf("decodeSS58Address: invalid address decoded")
+	}
+}
+
+// getFakeUnappliedSlash returns a SCALE encoded UnappliedSlash of a validator, with a nominator and a reporter
+func getFakeUnappliedSlash(validator, nominator, reporter []byte) []byte {
+	balance := make([]byte, 16)
+	slash := append(append([]byte{}, validator...), balance...)
+	slash = append(append(append(slash, 4), nominator...), balance...)
+	slash = append(append(slash, 4), reporter...)
+	return append(slash, balance...)
+}
+
+func TestHandleSlashDetection(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	alice, _ := decodeSS58Address(aliceStash)
+	other := bytes.Repeat([]byte{1}, 32)
+	activeEra := make([]byte, 4)
+	binary.LittleEndian.PutUint32(activeEra, 100)
+	spans := make([]byte, 13)
+	binary.LittleEndian.PutUint32(spans[8:], 98)
+	unappliedSlashes := append([]byte{8}, getFakeUnappliedSlash(alice, other, other)...)
+	unappliedSlashes = append(unappliedSlashes, getFakeUnappliedSlash(other, other, alice)...)
+	unappliedSlashesKey := getStorageKey("Staking", "UnappliedSlashes") + "01000000"
+	storage := map[string][]byte{
+		getStorageKey("Staking", "ActiveEra"):            activeEra,
+		getStorageKey("Staking", "SlashingSpans", alice): spans,
+		unappliedSlashesKey:                              unappliedSlashes,
+	}
+
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		switch method {
+		case "state_getStorage":
+			value, isFound := storage[params[0].(string)]
+			if !isFound {
+				return json.Unmarshal([]byte(`null`), result)
+			}
+			return json.Unmarshal([]byte(`"0x`+hex.EncodeToString(value)+`"`), result)
+		case "state_getKeysPaged":
+			return json.Unmarshal([]byte(`["`+unappliedSlashesKey+`"]`), result)
+		}
+		t.Fatalf("handleSlashDetection: unexpected RPC method (%v)", method)
+		return nil
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.Staking.Stash = aliceStash
+	polkadot.Spec.Validator.SlashDetection.Enabled = true
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleSlashDetection(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleSlashDetection: (%v, %v)", isRequeueForced, err)
+	}
+	slashes := polkadot.Status.Slashes
+	if slashes == nil || slashes.Stash != aliceStash || slashes.ActiveEra != 100 || slashes.UnappliedSlashes != 1 || slashes.LastSlashEra == nil || *slashes.LastSlashEra != 98 {
+		t.Fatalf("handleSlashDetection: unexpected slashes (%v)", slashes)
+	}
+	conditions := map[string]polkadotv1alpha1.PolkadotCondition{}
+	for _, condition := range polkadot.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+	if conditions[OffenceDetectedType].Status != corev1.ConditionTrue || conditions[SlashedType].Status != corev1.ConditionTrue {
+		t.Fatalf("handleSlashDetection: unexpected conditions (%v)", polkadot.Status.Conditions)
+	}
+	if unapplied := testutil.ToFloat64(validatorUnappliedSlashes.WithLabelValues(polkadot.Namespace, polkadot.Name)); unapplied != 1 {
+		t.Fatalf("handleSlashDetection: unexpected unapplied slashes metric (%v)", unapplied)
+	}
+
+	// the slash is no longer recent once the recent eras are over
+	polkadot.Spec.Validator.SlashDetection.RecentEras = 2
+	delete(storage, unappliedSlashesKey)
+	isRequeueForced, err = reconciler.handleSlashDetection(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleSlashDetection: (%v, %v)", isRequeueForced, err)
+	}
+	for _, condition := range polkadot.Status.Conditions {
+		if (condition.Type == OffenceDetectedType || condition.Type == SlashedType) && condition.Status != corev1.ConditionFalse {
+			t.Fatalf("handleSlashDetection: unexpected condition (%v)", condition)
+		}
+	}
+
+	polkadot.Spec.Validator.SlashDetection.Enabled = false
+	isRequeueForced, err = reconciler.handleSlashDetection(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleSlashDetection: (%v, %v)", isRequeueForced, err)
+	}
+	if polkadot.Status.Slashes != nil || len(polkadot.Status.Conditions) != 0 {
+		t.Fatalf("handleSlashDetection: slashes not removed (%v, %v)", polkadot.Status.Slashes, polkadot.Status.Conditions)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// handleStaking runs the Job bootstrapping the validator on chain once its session keys are rotated: the stash is bonded
+// and the keys are registered with session.setKeys. A new rotation of the keys replaces the Job, and the registration of
+// the keys is reported in the sessionKeys of the status
+func (r *ReconcilerPolkadot) handleStaking(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Job.Name", StakingName)
+	if !CRInstance.Spec.Validator.Staking.Enabled || !isRoleDeployed(CRInstance, "validator") {
+		return r.handleStakingDisabled(CRInstance)
+	}
+	if CRInstance.Status.SessionKeys == nil {
+		logger.Info("Session keys not rotated yet, postponing the staking bootstrap...")
+		return handleSkip()
+	}
+
+	desiredResource := newStakingJob(CRInstance)
+	err := setJobHash(desiredResource)
+	if err != nil {
+		return NotForcedRequeue, err
+	}
+
+	foundResource := &batchv1.Job{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Job...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true {
+		logger.V(debugLevel).Info("Job not found...")
+		logger.Info("Creating a new Job...")
+		err := r.applyResource(desiredResource, CRInstance)
+		if err != nil {
+			logger.Error(err, "Error on creating a new Job...")
+			return NotForcedRequeue, err
+		}
+		logger.Info("Created the new Job")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "StakingBootstrapStarted", "Registering the session keys %s of the validator on chain", CRInstance.Status.SessionKeys.PublicKeys)
+		return ForcedRequeue, nil
+	}
+	if foundResource.Annotations[templateHashAnnotation] != desiredResource.Annotations[templateHashAnnotation] {
+		if !metav1.IsControlledBy(foundResource, CRInstance) {
+			logger.V(warnLevel).Info("Job not controlled by the CR, not able to replace it...")
+			return handleSkip()
+		}
+		logger.Info("Session keys or staking changed, replacing the Job...")
+		err = r.deleteResource(foundResource, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil {
+			logger.Error(err, "Delete Job Error...")
+			return NotForcedRequeue, err
+		}
+		return ForcedRequeue, nil
+	}
+	return r.handleStakingStatus(CRInstance, foundResource)
+}
+
+// handleStakingStatus reports the registration of the session keys, as observed on the Job registering them
+func (r *ReconcilerPolkadot) handleStakingStatus(CRInstance *polkadotv1alpha1.Polkadot, job *batchv1.Job) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	sessionKeys := CRInstance.Status.SessionKeys
+	registration := getJobRegistration(job)
+	if registration == sessionKeys.Registration {
+		return handleSkip()
+	}
+	sessionKeys.Registration = registration
+	if registration == RegistrationRegistered {
+		sessionKeys.RegistrationTime = job.Status.CompletionTime.DeepCopy()
+	}
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Updated the registration of the session keys...", "Registration", registration)
+	switch registration {
+	case RegistrationRegistered:
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "SessionKeysRegistered", "The session keys %s of the validator are registered on chain", sessionKeys.PublicKeys)
+	case RegistrationFailed:
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "StakingBootstrapFailed", "The Job %s failed to register the session keys, see the logs of its pods", job.Name)
+	}
+	return NotForcedRequeue, nil
+}
+
+func getJobRegistration(job *batchv1.Job) string {
+	if job.Status.Succeeded > 0 && job.Status.CompletionTime != nil {
+		return RegistrationRegistered
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return RegistrationFailed
+		}
+	}
+	return RegistrationPending
+}
+
+// handleStakingDisabled deletes the Job, what has been submitted on chain is kept
+func (r *ReconcilerPolkadot) handleStakingDisabled(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Job.Name", StakingName)
+
+	foundResource := &batchv1.Job{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: StakingName, Namespace: CRInstance.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Job...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == true || !metav1.IsControlledBy(foundResource, CRInstance) {
+		return handleSkip()
+	}
+
+	logger.Info("Staking bootstrap disabled, deleting the Job...")
+	err = r.deleteResource(foundResource, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		logger.Error(err, "Delete Job Error...")
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the Job")
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,92 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestHandleStaking(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.KeyRotation.OnFirstStart = true
+	polkadot.Spec.Validator.Staking = polkadotv1alpha1.Staking{
+		Enabled:        true,
+		Stash:          "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		StashSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "stash"}, Key: "seed"},
+		Bond:           "1000000000000",
+		Validate:       true,
+		Commission:     5,
+	}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	// the bootstrap waits for the rotation of the session keys
+	isRequeueForced, err := reconciler.handleStaking(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStaking: (%v, %v)", isRequeueForced, err)
+	}
+	found := &batchv1.Job{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: StakingName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleStaking: Job created before the rotation (%v)", err)
+	}
+
+	polkadot.Status.SessionKeys = &polkadotv1alpha1.SessionKeysStatus{PublicKeys: "0x0001"}
+	isRequeueForced, err = reconciler.handleStaking(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStaking: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: StakingName}, found)
+	if err != nil {
+		t.Fatalf("handleStaking: (%v)", err)
+	}
+	env := map[string]corev1.EnvVar{}
+	for _, envVar := range found.Spec.Template.Spec.Containers[0].Env {
+		env[envVar.Name] = envVar
+	}
+	if env["KEYS"].Value != "0x0001" || env["CONTROLLER"].Value != polkadot.Spec.Validator.Staking.Stash || env["COMMISSION"].Value != "50000000" || env["CONTROLLER_SEED"].ValueFrom.SecretKeyRef.Name != "stash" {
+		t.Fatalf("handleStaking: unexpected env (%v)", env)
+	}
+
+	completionTime := metav1.Now()
+	found.Status = batchv1.JobStatus{Succeeded: 1, CompletionTime: &completionTime}
+	if err := client.Status().Update(context.TODO(), found); err != nil {
+		t.Fatalf("Update: (%v)", err)
+	}
+	isRequeueForced, err = reconciler.handleStaking(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStaking: (%v, %v)", isRequeueForced, err)
+	}
+	if polkadot.Status.SessionKeys.Registration != RegistrationRegistered || polkadot.Status.SessionKeys.RegistrationTime == nil {
+		t.Fatalf("handleStaking: unexpected session keys (%v)", polkadot.Status.SessionKeys)
+	}
+
+	// a new rotation replaces the Job
+	polkadot.Status.SessionKeys = &polkadotv1alpha1.SessionKeysStatus{PublicKeys: "0x0002"}
+	isRequeueForced, err = reconciler.handleStaking(polkadot)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStaking: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: StakingName}, found)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleStaking: Job not replaced (%v)", err)
+	}
+}
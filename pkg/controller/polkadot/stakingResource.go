@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+)
+
+// stakingScript bonds the stash unless it is already bonded, then registers the session keys and, if requested, the
+// intention to validate. Every step is signed and submitted by polkadot-js-api, which waits for the inclusion
+const stakingScript = `set -eu
+api() { polkadot-js-api --ws "$ENDPOINT" "$@"; }
+bonded=$(api query.staking.bonded "$STASH" | tr -d '\n ' | sed -n 's/.*"bonded":"\([^"]*\)".*/\1/p')
+if [ -z "$bonded" ]; then
+  echo "bonding $BOND from $STASH to $CONTROLLER"
+  api --seed "$STASH_SEED" tx.staking.bond "$CONTROLLER" "$BOND" Staked
+else
+  echo "$STASH already bonded to $bonded"
+fi
+echo "setting the session keys $KEYS"
+api --seed "$CONTROLLER_SEED" tx.session.setKeys "$KEYS" 0x
+if [ "$VALIDATE" = "true" ]; then
+  echo "validating with a commission of $COMMISSION perbill"
+  api --seed "$CONTROLLER_SEED" tx.staking.validate "{\"commission\":$COMMISSION}"
+fi`
+
+func getStakingLabels() map[string]string {
+	labels := getAppLabels()
+	labels["role"] = "staking"
+	return labels
+}
+
+// newStakingJob bonds the stash of the validator and registers the session keys of the last rotation on chain. The keys
+// are part of the pod template, so that a rotation replaces the Job
+func newStakingJob(CRInstance *polkadotv1alpha1.Polkadot) *batchv1.Job {
+	staking := CRInstance.Spec.Validator.Staking
+	image := staking.Image
+	if image == "" {
+		image = polkadotJsImage
+	}
+	controller := staking.Controller
+	if controller == "" {
+		controller = staking.Stash
+	}
+	controllerSecretRef := staking.ControllerSecretRef
+	if controllerSecretRef == nil {
+		controllerSecretRef = staking.StashSecretRef
+	}
+	publicKeys := ""
+	if CRInstance.Status.SessionKeys != nil {
+		publicKeys = CRInstance.Status.SessionKeys.PublicKeys
+	}
+	backoffLimit := int32(stakingBackoffLimit)
+	labels := getCopyLabelsWithCustom(getStakingLabels(), CRInstance.Spec.Metadata.Labels)
+
+	job := &batchv1.Job{
+		ObjectMeta: getStakingObjectMeta(CRInstance),
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyOnFailure,
+					ImagePullSecrets: CRInstance.Spec.ImagePullSecrets,
+					Containers: []corev1.Container{{
+						Name:    StakingName,
+						Image:   image,
+						Command: []string{"/bin/sh", "-c", stakingScript},
+						Env: []corev1.EnvVar{
+							{Name: "ENDPOINT", Value: getJobsEndpoint(CRInstance)},
+							{Name: "STASH", Value: staking.Stash},
+							{Name: "CONTROLLER", Value: controller},
+							{Name: "BOND", Value: staking.Bond},
+							{Name: "KEYS", Value: publicKeys},
+							{Name: "VALIDATE", Value: strconv.FormatBool(staking.Validate)},
+							// the commission is a perbill
+							{Name: "COMMISSION", Value: strconv.Itoa(int(staking.Commission) * 10000000)},
+							{Name: "STASH_SEED", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: staking.StashSecretRef}},
+							{Name: "CONTROLLER_SEED", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: controllerSecretRef}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if isHardened(CRInstance) {
+		hardenPodTemplate(&job.Spec.Template)
+	}
+	return job
+}
+
+func getStakingObjectMeta(CRInstance *polkadotv1alpha1.Polkadot) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        StakingName,
+		Namespace:   CRInstance.Namespace,
+		Labels:      getCopyLabelsWithCustom(getStakingLabels(), CRInstance.Spec.Metadata.Labels),
+		Annotations: getCopy(CRInstance.Spec.Metadata.Annotations),
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// handleStaleResources deletes the resources generated for the roles no longer deployed by the CR,
+// e.g. the validator StatefulSet and Service after a change of the Kind from SentryAndValidator to Sentry.
+// The ServiceAccounts are kept, as they may be shared with the deployed roles
+func (r *ReconcilerPolkadot) handleStaleResources(CRInstance *polkadotv1alpha1.Polkadot) (bool, error) {
+	logger := getLogger(CRInstance)
+
+	deployedRoles := map[string]bool{}
+	for _, rr := range getRoleResources(CRInstance) {
+		deployedRoles[rr.role] = true
+	}
+
+	for _, list := range []runtime.Object{&appsv1.StatefulSetList{}, &corev1.ServiceList{}, &corev1.ConfigMapList{}, &autoscalingv2beta2.HorizontalPodAutoscalerList{}, &policyv1beta1.PodDisruptionBudgetList{}, &networkingv1beta1.IngressList{}} {
+		err := r.client.List(context.TODO(), list, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(getAppLabels()))
+		if err != nil {
+			logger.Error(err, "Error on fetch the resources of the roles...")
+			return NotForcedRequeue, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return NotForcedRequeue, err
+		}
+		for _, item := range items {
+			resource := item.(metav1.Object)
+			role, hasRole := resource.GetLabels()["role"]
+			if !hasRole || deployedRoles[role] || !metav1.IsControlledBy(resource, CRInstance) {
+				continue
+			}
+			gvk, err := apiutil.GVKForObject(item, r.scheme)
+			if err != nil {
+				return NotForcedRequeue, err
+			}
+			logger.Info("Role no longer deployed, deleting its resource...", "Role", role)
+			err = r.deleteResourceWithEvent(CRInstance, resource, gvk.Kind)
+			if err != nil {
+				return NotForcedRequeue, err
+			}
+		}
+	}
+	return NotForcedRequeue, nil
+}
@@ -0,0 +1,106 @@
+package polkadot
+
+import (
+	"context"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"testing"
+)
+
+func TestHandleStaleResources(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := autoscalingv2beta2.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := policyv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := networkingv1beta1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// A Polkadot object changed from SentryAndValidator to Sentry
+	polkadot := getFakePolkadot()
+	polkadot.UID = "polkadot-uid"
+	polkadot.Spec.Kind = string(Sentry)
+
+	sentryStatefulSet := getFakeStatefulSet(SentrySSName, 1)
+	sentryStatefulSet.Labels = getSentrylabels()
+	validatorStatefulSet := getFakeStatefulSet(ValidatorSSName, 1)
+	validatorStatefulSet.Labels = getValidatorLabels()
+	validatorService := newServiceValidator(polkadot)
+	validatorDashboard := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: getDashboardName("validator"), Labels: getValidatorLabels()}}
+	networkPolicy := newNetworkPolicyValidator(polkadot)
+	validatorAutoscaler := newHorizontalPodAutoscaler(polkadot, roleResources{role: "validator", statefulSetName: ValidatorSSName})
+	for _, owned := range []metav1.Object{sentryStatefulSet, validatorStatefulSet, validatorService, validatorDashboard, networkPolicy, validatorAutoscaler} {
+		if err := controllerutil.SetControllerReference(polkadot, owned, scheme); err != nil {
+			t.Fatalf("SetControllerReference: (%v)", err)
+		}
+	}
+	// not owned by the CR, it is kept
+	otherStatefulSet := getFakeStatefulSet("other-sset", 1)
+	otherStatefulSet.Labels = getValidatorLabels()
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{polkadot, sentryStatefulSet, validatorStatefulSet, otherStatefulSet, validatorService, validatorDashboard, networkPolicy, validatorAutoscaler}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, objs...)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleStaleResources(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStaleResources: (%v)", err)
+	}
+	isRequeueForced, err = reconciler.handleNetworkPolicy(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleNetworkPolicy: (%v)", err)
+	}
+
+	expected := []struct {
+		name      string
+		resource  runtime.Object
+		isDeleted bool
+	}{
+		{name: SentrySSName, resource: &appsv1.StatefulSet{}, isDeleted: false},
+		{name: "other-sset", resource: &appsv1.StatefulSet{}, isDeleted: false},
+		{name: ValidatorSSName, resource: &appsv1.StatefulSet{}, isDeleted: true},
+		{name: ServiceValidatorName, resource: &corev1.Service{}, isDeleted: true},
+		{name: getDashboardName("validator"), resource: &corev1.ConfigMap{}, isDeleted: true},
+		{name: ValidatorNetworkPolicy, resource: &v1.NetworkPolicy{}, isDeleted: true},
+		{name: ValidatorSSName, resource: &autoscalingv2beta2.HorizontalPodAutoscaler{}, isDeleted: true},
+	}
+	for _, e := range expected {
+		isNotFound, err := reconciler.fetchResource(e.resource, types.NamespacedName{Name: e.name})
+		if err != nil || isNotFound != e.isDeleted {
+			t.Fatalf("handleStaleResources: unexpected state of %s, deleted: %v", e.name, isNotFound)
+		}
+	}
+
+	// the CR itself is not touched
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: CRName}, polkadot); err != nil {
+		t.Fatalf("handleStaleResources: (%v)", err)
+	}
+}
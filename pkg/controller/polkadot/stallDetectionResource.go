@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"time"
+)
+
+// getStallDetectorContainer returns the sidecar polling the best block of the client through chain_getHeader, it touches
+// the progress file in the volume shared with the client every time the best block changes
+func getStallDetectorContainer(stallDetection polkadotv1alpha1.StallDetection, rpcPort int32) corev1.Container {
+	image := stallDetection.Image
+	if image == "" {
+		image = stallDetectorImage
+	}
+	progress := stallMountPath + "/progress"
+	script := "last=''; touch " + progress + "; " +
+		"while true; do " +
+		`best=$(wget -qO- --header 'Content-Type: application/json' --post-data '{"id":1,"jsonrpc":"2.0","method":"chain_getHeader","params":[]}' ` +
+		fmt.Sprintf("http://localhost:%d", rpcPort) + ` 2>/dev/null | sed -n 's/.*"number":"\(0x[0-9a-fA-F]*\)".*/\1/p'); ` +
+		`if [ -n "$best" ] && [ "$best" != "$last" ]; then last=$best; touch ` + progress + "; fi; " +
+		fmt.Sprintf("sleep %d; ", stallPollPeriod) +
+		"done"
+
+	return corev1.Container{
+		Name:    stallDetectorName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", script},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      stallVolumeName,
+			MountPath: stallMountPath,
+		}},
+	}
+}
+
+// getStallProbeClient returns the liveness probe of the client failing once the progress file is older than the timeout.
+// The initial delay gives a restarted client the whole timeout to import a new block
+func getStallProbeClient(stallDetection polkadotv1alpha1.StallDetection) *corev1.Probe {
+	timeout := int64(getStallTimeout(stallDetection).Seconds())
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/sh", "-c", fmt.Sprintf("[ $(( $(date +%%s) - $(stat -c %%Y %s/progress) )) -lt %d ]", stallMountPath, timeout)},
+			},
+		},
+		InitialDelaySeconds: int32(timeout),
+		PeriodSeconds:       stallPollPeriod,
+	}
+}
+
+func getStallVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: stallVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func getStallTimeout(stallDetection polkadotv1alpha1.StallDetection) time.Duration {
+	if stallDetection.Timeout != nil {
+		return stallDetection.Timeout.Duration
+	}
+	return stallTimeout
+}
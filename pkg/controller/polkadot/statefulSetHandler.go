@@ -3,19 +3,34 @@
 package polkadot
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"github.com/go-logr/logr"
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 func (r *ReconcilerPolkadot) handleStatefulSet(CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
 	handler := getHandlerStatefulSet(CRInstance)
-	return handler.handleStatefulSetSpecific(r,CRInstance)
+	isForcedRequeue, err := handler.handleStatefulSetSpecific(r,CRInstance)
+	if isForcedRequeue == ForcedRequeue || err != nil || !isRpcNodeAddOn(CRInstance) {
+		return isForcedRequeue, err
+	}
+	return r.handleStatefulSetGeneric(CRInstance, newStatefulSetRpcNode(CRInstance))
 }
 
 //pattern factory
 func getHandlerStatefulSet(CRInstance *polkadotv1alpha1.Polkadot) IHandlerStatefulSet {
+	if len(CRInstance.Spec.NodePools) > 0 {
+		return &handlerStatefulSetNodePools{}
+	}
 	if CRKind(CRInstance.Spec.Kind) == Validator {
 		return &handlerStatefulSetValidator{}
 	}
@@ -25,6 +40,18 @@ func getHandlerStatefulSet(CRInstance *polkadotv1alpha1.Polkadot) IHandlerStatef
 	if CRKind(CRInstance.Spec.Kind) == SentryAndValidator {
 		return &handlerStatefulSetSentryAndValidator{}
 	}
+	if CRKind(CRInstance.Spec.Kind) == Bootnode {
+		return &handlerStatefulSetBootnode{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == Archive {
+		return &handlerStatefulSetArchive{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == RpcNode {
+		return &handlerStatefulSetRpcNode{}
+	}
+	if CRKind(CRInstance.Spec.Kind) == Collator {
+		return &handlerStatefulSetCollator{}
+	}
 	return &handlerStatefulSetDefault{}
 }
 
@@ -55,6 +82,67 @@ func (h *handlerStatefulSetSentryAndValidator) handleStatefulSetSpecific(r *Reco
 	return r.handleStatefulSetGeneric(CRInstance, newStatefulSetValidator(CRInstance))
 }
 
+type handlerStatefulSetBootnode struct {
+}
+func (h *handlerStatefulSetBootnode) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
+	// the pods without a valid node key would crashloop, the StatefulSet is neither created nor updated
+	if err := checkBootnodeNodeKeys(CRInstance); err != nil {
+		getLogger(CRInstance).Error(err, "Invalid node keys of the bootnodes...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "InvalidNodeKeys", "The StatefulSet %s is not applied: %v", BootnodeSSName, err)
+		return NotForcedRequeue, err
+	}
+	return r.handleStatefulSetGeneric(CRInstance, newBootNodeStatefulSetForCR(CRInstance))
+}
+
+type handlerStatefulSetArchive struct {
+}
+func (h *handlerStatefulSetArchive) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
+	return r.handleStatefulSetGeneric(CRInstance, newStatefulSetArchive(CRInstance))
+}
+
+type handlerStatefulSetRpcNode struct {
+}
+func (h *handlerStatefulSetRpcNode) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
+	return r.handleStatefulSetGeneric(CRInstance, newStatefulSetRpcNode(CRInstance))
+}
+
+type handlerStatefulSetCollator struct {
+}
+func (h *handlerStatefulSetCollator) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
+	return r.handleStatefulSetGeneric(CRInstance, newStatefulSetCollator(CRInstance))
+}
+
+// handlerStatefulSetNodePools handles the StatefulSet of each node pool, with the strategy of its role
+type handlerStatefulSetNodePools struct {
+}
+func (h *handlerStatefulSetNodePools) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
+	for _, pool := range CRInstance.Spec.NodePools {
+		isForcedRequeue, err := getHandlerStatefulSetRole(pool.Role).handleStatefulSetSpecific(r, CRInstance)
+		if isForcedRequeue == ForcedRequeue || err != nil {
+			return isForcedRequeue, err
+		}
+	}
+	return NotForcedRequeue, nil
+}
+
+func getHandlerStatefulSetRole(role string) IHandlerStatefulSet {
+	switch role {
+	case "sentry":
+		return &handlerStatefulSetSentry{}
+	case "validator":
+		return &handlerStatefulSetValidator{}
+	case "bootnode":
+		return &handlerStatefulSetBootnode{}
+	case "archive":
+		return &handlerStatefulSetArchive{}
+	case "rpcnode":
+		return &handlerStatefulSetRpcNode{}
+	case "collator":
+		return &handlerStatefulSetCollator{}
+	}
+	return &handlerStatefulSetDefault{}
+}
+
 type handlerStatefulSetDefault struct {
 }
 func (h *handlerStatefulSetDefault) handleStatefulSetSpecific(r *ReconcilerPolkadot, CRInstance *polkadotv1alpha1.Polkadot) (bool, error){
@@ -63,7 +151,7 @@ func (h *handlerStatefulSetDefault) handleStatefulSetSpecific(r *ReconcilerPolka
 
 func (r *ReconcilerPolkadot) handleStatefulSetGeneric(CRInstance *polkadotv1alpha1.Polkadot, desiredResource *appsv1.StatefulSet) (bool, error) {
 
-	logger := log.WithValues("Deployment.Namespace", desiredResource.Namespace, "Deployment.Name", desiredResource.Name)
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", desiredResource.Name)
 
 	toBeFoundResource := &appsv1.StatefulSet{}
 	isNotFound, err := r.fetchResource(toBeFoundResource,types.NamespacedName{Name: desiredResource.Name, Namespace: desiredResource.Namespace})
@@ -71,59 +159,339 @@ func (r *ReconcilerPolkadot) handleStatefulSetGeneric(CRInstance *polkadotv1alph
 		logger.Error(err, "Error on fetch the StatefulSet...")
 		return NotForcedRequeue, err
 	}
+	pinStatefulSetImages(CRInstance, desiredResource)
+	err = r.setConfigChecksum(desiredResource)
+	if err != nil {
+		logger.Error(err, "Error on computing the checksum of the mounted ConfigMaps and Secrets...")
+		return NotForcedRequeue, err
+	}
+	if isNotFound == false {
+		if isStatefulSetFrozen(CRInstance, desiredResource) {
+			logger.Info("Role in maintenance, skipping the update of the StatefulSet...")
+			return NotForcedRequeue, nil
+		}
+		if isStatefulSetAutoscaled(CRInstance, desiredResource) {
+			// the replicas set by the HorizontalPodAutoscaler are kept
+			desiredResource.Spec.Replicas = toBeFoundResource.Spec.Replicas
+		}
+		err = r.handleUpgradePartition(CRInstance, toBeFoundResource, desiredResource)
+		if err != nil {
+			logger.Error(err, "Error on setting the upgrade partition of the StatefulSet...")
+			return NotForcedRequeue, err
+		}
+	}
+	err = setStatefulSetHash(desiredResource)
+	if err != nil {
+		logger.Error(err, "Error on hashing the StatefulSet...")
+		return NotForcedRequeue, err
+	}
 	if isNotFound == true {
-		logger.Info("StatefulSet not found...")
+		logger.V(debugLevel).Info("StatefulSet not found...")
 		logger.Info("Creating a new StatefulSet...")
-		err := r.createResource(desiredResource, CRInstance)
+		err := r.applyResource(desiredResource, CRInstance)
 		if err != nil {
 			logger.Error(err, "Error on creating a new StatefulSet...")
+			r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedCreate", "Failed to create the StatefulSet %s: %v", desiredResource.Name, err)
 			return NotForcedRequeue, err
 		}
 		logger.Info("Created the new StatefulSet")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Created", "Created the StatefulSet %s", desiredResource.Name)
 		return ForcedRequeue, nil
 	}
 	foundResource := toBeFoundResource
 
-	if areStatefulSetDifferent(foundResource, desiredResource, logger) {
-		logger.Info("Updating the StatefulSet...")
-		err := r.updateResource(desiredResource)
-		if err != nil {
-			logger.Error(err, "Update StatefulSet Error...")
-			return NotForcedRequeue, err
-		}
+	err = validateVolumeClaimTemplates(foundResource, desiredResource)
+	if err != nil {
+		logger.Error(err, "Invalid volume claim templates...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "InvalidStorage", "Invalid storage of the StatefulSet %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+
+	if isStatefulSetStorageExpanded(foundResource, desiredResource, logger) {
+		return r.handleStatefulSetStorageExpansion(CRInstance, foundResource, desiredResource)
+	}
+	if foundResource.Spec.ServiceName != desiredResource.Spec.ServiceName {
+		// e.g. a StatefulSet created before its headless Service, governed by the former polkadot Service
+		logger.Info("Found a change of the governing Service...", "Service.Name", desiredResource.Spec.ServiceName)
+		return r.recreateStatefulSet(CRInstance, foundResource, "its governing Service")
+	}
+	if isPodManagementPolicyChanged(foundResource, desiredResource) {
+		logger.Info("Found a change of the pod management policy...", "PodManagementPolicy", desiredResource.Spec.PodManagementPolicy)
+		return r.recreateStatefulSet(CRInstance, foundResource, "its pod management policy")
+	}
+
+	if isConfigChecksumChanged(foundResource, desiredResource) {
+		logger.Info("The mounted ConfigMaps or Secrets changed, rolling the pods...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "ConfigChanged", "The ConfigMaps or Secrets mounted by the StatefulSet %s changed, rolling its pods", desiredResource.Name)
+	}
+
+	drift := getStatefulSetDrift(foundResource, desiredResource)
+	if len(drift) > 0 {
+		logger.V(warnLevel).Info("Found a drift from the desired state, correcting it...", "Drift", drift)
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "DriftDetected", "The StatefulSet %s drifted from the desired state (%s), correcting it", desiredResource.Name, strings.Join(drift, ", "))
+	} else if foundResource.Annotations[specHashAnnotation] == desiredResource.Annotations[specHashAnnotation] {
+		// the desired state is unchanged since the last apply
+		return NotForcedRequeue, nil
+	}
+
+	// the volume claim templates of a StatefulSet are immutable, they only apply to the volumes still to be created
+	desiredResource.Spec.VolumeClaimTemplates = foundResource.Spec.VolumeClaimTemplates
+	err = r.applyResource(desiredResource, CRInstance)
+	if err != nil {
+		logger.Error(err, "Update StatefulSet Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to update the StatefulSet %s: %v", desiredResource.Name, err)
+		return NotForcedRequeue, err
+	}
+	if isResourceChanged(foundResource, desiredResource) {
 		logger.Info("Updated the StatefulSet...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Updated the StatefulSet %s", desiredResource.Name)
 	}
 
 	return NotForcedRequeue, nil
 }
 
-func areStatefulSetDifferent(current *appsv1.StatefulSet, desired *appsv1.StatefulSet, logger logr.Logger) bool {
-	result := false
+// isStatefulSetFrozen tells if the StatefulSet belongs to a role in the Freeze maintenance mode, which is not updated
+func isStatefulSetFrozen(CRInstance *polkadotv1alpha1.Polkadot, statefulSet *appsv1.StatefulSet) bool {
+	options := getStatefulSetOptions(CRInstance, statefulSet)
+	return options != nil && getMaintenanceMode(*options) == MaintenanceFreeze
+}
+
+// isStatefulSetAutoscaled tells if the replicas of the StatefulSet are driven by a HorizontalPodAutoscaler.
+// A role scaled down for maintenance is not, as the autoscaler leaves alone the targets with zero replicas
+func isStatefulSetAutoscaled(CRInstance *polkadotv1alpha1.Polkadot, statefulSet *appsv1.StatefulSet) bool {
+	options := getStatefulSetOptions(CRInstance, statefulSet)
+	return options != nil && options.Autoscaling.Enabled && getMaintenanceMode(*options) != MaintenanceScaleDown
+}
 
-	if isStatefulSetReplicaDifferent(current, desired, logger) {
-		result = true
+func getStatefulSetOptions(CRInstance *polkadotv1alpha1.Polkadot, statefulSet *appsv1.StatefulSet) *polkadotv1alpha1.NodeOptions {
+	for _, rr := range getRoleResources(CRInstance) {
+		if rr.statefulSetName == statefulSet.Name {
+			return &rr.options
+		}
 	}
-	if isStatefulSetVersionDifferent(current, desired, logger) {
-		result = true
+	return nil
+}
+
+// isConfigChecksumChanged tells if the content of the mounted ConfigMaps and Secrets changed since the found StatefulSet
+// was applied. A checksum missing on either side is a change of the volumes, reported as a change of the template
+func isConfigChecksumChanged(current *appsv1.StatefulSet, desired *appsv1.StatefulSet) bool {
+	currentChecksum, isCurrentFound := current.Spec.Template.Annotations[checksumAnnotation]
+	desiredChecksum, isDesiredFound := desired.Spec.Template.Annotations[checksumAnnotation]
+	return isCurrentFound && isDesiredFound && currentChecksum != desiredChecksum
+}
+
+// setStatefulSetHash annotates the StatefulSet with the hash of its desired metadata and spec, so that a change
+// of the desired state is detected comparing the annotation of the found StatefulSet
+func setStatefulSetHash(statefulSet *appsv1.StatefulSet) error {
+	data, err := json.Marshal(struct {
+		Labels      map[string]string
+		Annotations map[string]string
+		Spec        appsv1.StatefulSetSpec
+	}{statefulSet.Labels, statefulSet.Annotations, statefulSet.Spec})
+	if err != nil {
+		return err
 	}
+	annotations := getCopy(statefulSet.Annotations)
+	annotations[specHashAnnotation] = fmt.Sprintf("%x", sha256.Sum256(data))
+	statefulSet.Annotations = annotations
+	return nil
+}
+
+// getStatefulSetDrift returns the fields of the found StatefulSet which differ from the desired state, e.g. after a manual edit
+// or a partially failed update. The apply corrects any drift, the returned fields are only reported
+func getStatefulSetDrift(current *appsv1.StatefulSet, desired *appsv1.StatefulSet) []string {
+	drift := getContainersDrift(current.Spec.Template.Spec.InitContainers, desired.Spec.Template.Spec.InitContainers)
+	return append(drift, getContainersDrift(current.Spec.Template.Spec.Containers, desired.Spec.Template.Spec.Containers)...)
+}
 
-	return result
+func getContainersDrift(current []corev1.Container, desired []corev1.Container) []string {
+	var drift []string
+	for i := range desired {
+		currentContainer := getContainer(current, desired[i].Name)
+		if currentContainer == nil {
+			drift = append(drift, desired[i].Name)
+			continue
+		}
+		for _, field := range getContainerDrift(currentContainer, &desired[i]) {
+			drift = append(drift, desired[i].Name+"."+field)
+		}
+	}
+	return drift
 }
 
-func isStatefulSetReplicaDifferent(current *appsv1.StatefulSet, desired *appsv1.StatefulSet, logger logr.Logger) bool {
-	size := *desired.Spec.Replicas
-	if *current.Spec.Replicas != size {
-		logger.Info("Found a replica size mismatch...")
-		return true
+// getContainerDrift compares the fields of a container set by the operator
+func getContainerDrift(current *corev1.Container, desired *corev1.Container) []string {
+	var drift []string
+	if current.Image != desired.Image {
+		drift = append(drift, "image")
 	}
-	return false
+	if !equality.Semantic.DeepEqual(current.Command, desired.Command) {
+		drift = append(drift, "command")
+	}
+	if !equality.Semantic.DeepEqual(current.Args, desired.Args) {
+		drift = append(drift, "args")
+	}
+	if !equality.Semantic.DeepEqual(current.Env, desired.Env) {
+		drift = append(drift, "env")
+	}
+	if !equality.Semantic.DeepEqual(current.VolumeMounts, desired.VolumeMounts) {
+		drift = append(drift, "volumeMounts")
+	}
+	if isProbeDrifted(current.LivenessProbe, desired.LivenessProbe) {
+		drift = append(drift, "livenessProbe")
+	}
+	if isProbeDrifted(current.ReadinessProbe, desired.ReadinessProbe) {
+		drift = append(drift, "readinessProbe")
+	}
+	if isProbeDrifted(current.StartupProbe, desired.StartupProbe) {
+		drift = append(drift, "startupProbe")
+	}
+	return drift
+}
+
+// isProbeDrifted compares the probes ignoring the fields left unset by the operator, defaulted by the API server
+func isProbeDrifted(current *corev1.Probe, desired *corev1.Probe) bool {
+	if current == nil || desired == nil {
+		return current != desired
+	}
+	defaulted := desired.DeepCopy()
+	if defaulted.TimeoutSeconds == 0 {
+		defaulted.TimeoutSeconds = current.TimeoutSeconds
+	}
+	if defaulted.PeriodSeconds == 0 {
+		defaulted.PeriodSeconds = current.PeriodSeconds
+	}
+	if defaulted.SuccessThreshold == 0 {
+		defaulted.SuccessThreshold = current.SuccessThreshold
+	}
+	if defaulted.FailureThreshold == 0 {
+		defaulted.FailureThreshold = current.FailureThreshold
+	}
+	if defaulted.HTTPGet != nil && current.HTTPGet != nil && defaulted.HTTPGet.Scheme == "" {
+		defaulted.HTTPGet.Scheme = current.HTTPGet.Scheme
+	}
+	return !equality.Semantic.DeepEqual(current, defaulted)
 }
 
-func isStatefulSetVersionDifferent(current *appsv1.StatefulSet, desired *appsv1.StatefulSet, logger logr.Logger) bool {
-	version := desired.ObjectMeta.Labels["version"]
-	if current.ObjectMeta.Labels["version"] != version {
-		logger.Info("Found a version mismatch...")
-		return true
+func getContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// validateVolumeClaimTemplates checks that the storage requested for the volumes of the StatefulSet does not decrease
+func validateVolumeClaimTemplates(current *appsv1.StatefulSet, desired *appsv1.StatefulSet) error {
+	for _, currentClaim := range current.Spec.VolumeClaimTemplates {
+		for _, desiredClaim := range desired.Spec.VolumeClaimTemplates {
+			if currentClaim.Name != desiredClaim.Name {
+				continue
+			}
+			currentSize, isCurrentFound := currentClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+			desiredSize, isDesiredFound := desiredClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+			if isCurrentFound && isDesiredFound && desiredSize.Cmp(currentSize) < 0 {
+				return fmt.Errorf("the storage size of the volume %s can only grow: requested %s, current %s", desiredClaim.Name, desiredSize.String(), currentSize.String())
+			}
+		}
+	}
+	return nil
+}
+
+func isStatefulSetStorageExpanded(current *appsv1.StatefulSet, desired *appsv1.StatefulSet, logger logr.Logger) bool {
+	for _, desiredClaim := range desired.Spec.VolumeClaimTemplates {
+		currentClaim := getVolumeClaimTemplate(current, desiredClaim.Name)
+		if currentClaim == nil {
+			continue
+		}
+		if isStorageSizeGreater(desiredClaim, *currentClaim) {
+			logger.Info("Found a storage size increase...")
+			return true
+		}
 	}
 	return false
 }
+
+func getVolumeClaimTemplate(statefulSet *appsv1.StatefulSet, name string) *corev1.PersistentVolumeClaim {
+	for i := range statefulSet.Spec.VolumeClaimTemplates {
+		if statefulSet.Spec.VolumeClaimTemplates[i].Name == name {
+			return &statefulSet.Spec.VolumeClaimTemplates[i]
+		}
+	}
+	return nil
+}
+
+func isStorageSizeGreater(claim corev1.PersistentVolumeClaim, other corev1.PersistentVolumeClaim) bool {
+	size, isFound := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	otherSize, isOtherFound := other.Spec.Resources.Requests[corev1.ResourceStorage]
+	return isFound && isOtherFound && size.Cmp(otherSize) > 0
+}
+
+// handleStatefulSetStorageExpansion resizes the existing volumes of the StatefulSet (their storage class must allow the volume expansion).
+// As the volume claim templates are immutable, the StatefulSet is then deleted leaving its pods and volumes in place,
+// to be recreated with the new templates at the next reconcile, adopting the orphaned pods
+func (r *ReconcilerPolkadot) handleStatefulSetStorageExpansion(CRInstance *polkadotv1alpha1.Polkadot, current *appsv1.StatefulSet, desired *appsv1.StatefulSet) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", current.Name)
+
+	for _, desiredClaim := range desired.Spec.VolumeClaimTemplates {
+		for ordinal := 0; ; ordinal++ {
+			name := fmt.Sprintf("%s-%s-%d", desiredClaim.Name, current.Name, ordinal)
+			foundClaim := &corev1.PersistentVolumeClaim{}
+			isNotFound, err := r.fetchResource(foundClaim, types.NamespacedName{Name: name, Namespace: current.Namespace})
+			if err != nil {
+				logger.Error(err, "Error on fetch the PersistentVolumeClaim...", "PersistentVolumeClaim.Name", name)
+				return NotForcedRequeue, err
+			}
+			if isNotFound == true {
+				if int32(ordinal) >= *current.Spec.Replicas {
+					break
+				}
+				continue
+			}
+			if !isStorageSizeGreater(desiredClaim, *foundClaim) {
+				continue
+			}
+			logger.Info("Expanding the PersistentVolumeClaim...", "PersistentVolumeClaim.Name", name)
+			foundClaim.Spec.Resources.Requests[corev1.ResourceStorage] = desiredClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+			err = r.updateResource(foundClaim)
+			if err != nil {
+				logger.Error(err, "Update PersistentVolumeClaim Error...", "PersistentVolumeClaim.Name", name)
+				r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedUpdate", "Failed to expand the PersistentVolumeClaim %s: %v", name, err)
+				return NotForcedRequeue, err
+			}
+			r.recordEvent(CRInstance, corev1.EventTypeNormal, "Updated", "Expanded the PersistentVolumeClaim %s", name)
+		}
+	}
+	return r.recreateStatefulSet(CRInstance, current, "its volume claim templates")
+}
+
+// isPodManagementPolicyChanged tells if the pod management policy of the StatefulSet changed, an empty policy being the
+// OrderedReady default of the API server
+func isPodManagementPolicyChanged(current *appsv1.StatefulSet, desired *appsv1.StatefulSet) bool {
+	return getPodManagementPolicy(current) != getPodManagementPolicy(desired)
+}
+
+func getPodManagementPolicy(statefulSet *appsv1.StatefulSet) appsv1.PodManagementPolicyType {
+	if statefulSet.Spec.PodManagementPolicy == "" {
+		return appsv1.OrderedReadyPodManagement
+	}
+	return statefulSet.Spec.PodManagementPolicy
+}
+
+// recreateStatefulSet deletes the StatefulSet to update its immutable fields, leaving its pods and volumes in place.
+// It is recreated at the next reconcile, adopting the orphaned pods
+func (r *ReconcilerPolkadot) recreateStatefulSet(CRInstance *polkadotv1alpha1.Polkadot, current *appsv1.StatefulSet, fields string) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", current.Name)
+
+	logger.Info("Deleting the StatefulSet to update " + fields + "...")
+	err := r.deleteResource(current, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+	if err != nil {
+		logger.Error(err, "Delete StatefulSet Error...")
+		r.recordEvent(CRInstance, corev1.EventTypeWarning, "FailedDelete", "Failed to delete the StatefulSet %s: %v", current.Name, err)
+		return NotForcedRequeue, err
+	}
+	logger.Info("Deleted the StatefulSet, it will be recreated...")
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "Deleted", "Deleted the StatefulSet %s to update %s, it will be recreated", current.Name, fields)
+	return ForcedRequeue, nil
+}
@@ -1,10 +1,18 @@
 package polkadot
 
 import (
+	"context"
 	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"strings"
 	"testing"
 )
 
@@ -45,7 +53,7 @@ func TestHandleStatefulSetGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot,test.newResource}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
+			client := newFakeClient(scheme, objs...)
 			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
 
 			isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot,test.newResource)
@@ -61,14 +69,661 @@ func TestHandleStatefulSetGeneric(t *testing.T) {
 			objs := []runtime.Object{polkadot}
 
 			// Create a fake client to mock API calls.
-			client := fake.NewFakeClientWithScheme(scheme, objs...)
-			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+			client := newFakeClient(scheme, objs...)
+			recorder := record.NewFakeRecorder(1)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
 
 			isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot,test.newResource)
 			if !isRequeueForced || err != nil {
 				t.Fatalf("handleNetworkPolicy: (%v)", isRequeueForced)
 			}
+			if event := <-recorder.Events; event != "Normal Created Created the StatefulSet "+SentrySSName {
+				t.Fatalf("handleStatefulSetGeneric: unexpected event (%v)", event)
+			}
+		})
+	}
+}
+
+
+func TestValidateVolumeClaimTemplates(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.Sentry.DataPersistenceSupport = polkadotv1alpha1.DataPersistenceSupport{
+		Enabled:               true,
+		PersistentVolumeClaim: corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-volume"}},
+		StorageClassName:      "ssd",
+		StorageSize:           resource.NewQuantity(100*1024*1024*1024, resource.BinarySI),
+	}
+	current := newStatefulSetSentry(polkadot)
+
+	tests := []struct {
+		name        string
+		storageSize string
+		isError     bool
+	}{
+		{
+			name:        "Storage size unchanged",
+			storageSize: "100Gi",
+			isError:     false,
+		},
+		{
+			name:        "Storage size increased",
+			storageSize: "200Gi",
+			isError:     false,
+		},
+		{
+			name:        "Storage size decreased",
+			storageSize: "50Gi",
+			isError:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modified := polkadot.DeepCopy()
+			storageSize := resource.MustParse(test.storageSize)
+			modified.Spec.Sentry.DataPersistenceSupport.StorageSize = &storageSize
+			desired := newStatefulSetSentry(modified)
+
+			err := validateVolumeClaimTemplates(current, desired)
+			if (err != nil) != test.isError {
+				t.Fatalf("validateVolumeClaimTemplates: unexpected result (%v)", err)
+			}
+		})
+	}
+}
+
+func TestHandleStatefulSetStorageExpansion(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.Sentry.DataPersistenceSupport = polkadotv1alpha1.DataPersistenceSupport{
+		Enabled:               true,
+		PersistentVolumeClaim: corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-volume"}},
+		StorageSize:           resource.NewQuantity(100*1024*1024*1024, resource.BinarySI),
+	}
+	current := newStatefulSetSentry(polkadot)
+	claim := current.Spec.VolumeClaimTemplates[0].DeepCopy()
+	claim.Name = "polkadot-volume-" + SentrySSName + "-0"
+
+	expanded := polkadot.DeepCopy()
+	storageSize := resource.MustParse("200Gi")
+	expanded.Spec.Sentry.DataPersistenceSupport.StorageSize = &storageSize
+	desired := newStatefulSetSentry(expanded)
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot, current, claim)
+	recorder := record.NewFakeRecorder(2)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, desired)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+
+	foundClaim := &corev1.PersistentVolumeClaim{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: claim.Name, Namespace: claim.Namespace}, foundClaim)
+	if err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if size := foundClaim.Spec.Resources.Requests[corev1.ResourceStorage]; size.Cmp(storageSize) != 0 {
+		t.Fatalf("handleStatefulSetGeneric: volume not expanded (%v)", size.String())
+	}
+
+	err = client.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: current.Namespace}, &v1.StatefulSet{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleStatefulSetGeneric: StatefulSet not deleted (%v)", err)
+	}
+	if len(recorder.Events) != 2 {
+		t.Fatalf("handleStatefulSetGeneric: expansion events not emitted (%v)", len(recorder.Events))
+	}
+}
+
+func TestHandleStatefulSetImageDrift(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.ClientVersion = "v0.8.2"
+
+	// the image of the StatefulSet edited manually
+	current := newStatefulSetSentry(polkadot)
+	getContainerClientFromStatefulSet(current).Image = "parity/polkadot:v0.8.0"
+	desired := newStatefulSetSentry(polkadot)
+
+	if drift := getStatefulSetDrift(current, desired); len(drift) != 1 || drift[0] != serviceName+".image" {
+		t.Fatalf("getStatefulSetDrift: unexpected drift (%v)", drift)
+	}
+	if drift := getStatefulSetDrift(desired, newStatefulSetSentry(polkadot)); len(drift) != 0 {
+		t.Fatalf("getStatefulSetDrift: unexpected drift (%v)", drift)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot, current)
+	recorder := record.NewFakeRecorder(2)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, desired)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning DriftDetected") {
+		t.Fatalf("handleStatefulSetGeneric: unexpected event (%v)", event)
+	}
+
+	found := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, found); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if image := getContainerClientFromStatefulSet(found).Image; image != getContainerClientFromStatefulSet(desired).Image {
+		t.Fatalf("handleStatefulSetGeneric: image drift not corrected (%v)", image)
+	}
+}
+
+func TestGetStatefulSetDrift(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.Sentry.Env = []corev1.EnvVar{{Name: "RUST_LOG", Value: "info"}}
+	polkadot.Spec.Sentry.DataPersistenceSupport = polkadotv1alpha1.DataPersistenceSupport{
+		Enabled:               true,
+		PersistentVolumeClaim: corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-volume"}},
+	}
+
+	tests := []struct {
+		name          string
+		edit          func(container *corev1.Container)
+		expectedDrift string
+	}{
+		{
+			name:          "Args edited",
+			edit:          func(container *corev1.Container) { container.Command = append(container.Command, "--unsafe-rpc-external") },
+			expectedDrift: serviceName + ".command",
+		},
+		{
+			name:          "Env edited",
+			edit:          func(container *corev1.Container) { container.Env[0].Value = "debug" },
+			expectedDrift: serviceName + ".env",
+		},
+		{
+			name:          "Volume mounts edited",
+			edit:          func(container *corev1.Container) { container.VolumeMounts = nil },
+			expectedDrift: serviceName + ".volumeMounts",
+		},
+		{
+			name:          "Probe edited",
+			edit:          func(container *corev1.Container) { container.LivenessProbe.HTTPGet.Path = "/" },
+			expectedDrift: serviceName + ".livenessProbe",
+		},
+		{
+			name:          "Probe removed",
+			edit:          func(container *corev1.Container) { container.ReadinessProbe = nil },
+			expectedDrift: serviceName + ".readinessProbe",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := newStatefulSetSentry(polkadot)
+			test.edit(getContainerClientFromStatefulSet(current))
+
+			drift := getStatefulSetDrift(current, newStatefulSetSentry(polkadot))
+			if len(drift) != 1 || drift[0] != test.expectedDrift {
+				t.Fatalf("getStatefulSetDrift: unexpected drift (%v)", drift)
+			}
 		})
 	}
+
+	// the defaults set by the API server are not a drift
+	current := newStatefulSetSentry(polkadot)
+	probe := getContainerClientFromStatefulSet(current).LivenessProbe
+	probe.TimeoutSeconds, probe.SuccessThreshold, probe.FailureThreshold = 1, 1, 3
+	probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+	if drift := getStatefulSetDrift(current, newStatefulSetSentry(polkadot)); len(drift) != 0 {
+		t.Fatalf("getStatefulSetDrift: unexpected drift (%v)", drift)
+	}
+}
+
+func TestHandleStatefulSetHash(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot))
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+	created := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, created); err != nil || created.Annotations[specHashAnnotation] == "" {
+		t.Fatalf("handleStatefulSetGeneric: hash annotation not set (%v)", err)
+	}
+
+	// the desired state is unchanged: the StatefulSet is not applied
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	found := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, found); err != nil || found.ResourceVersion != created.ResourceVersion {
+		t.Fatalf("handleStatefulSetGeneric: unexpected update (%v)", err)
+	}
+
+	// the desired state is changed: the StatefulSet is applied with the new hash
+	polkadot.Spec.Sentry.Replicas = 2
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, found); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if *found.Spec.Replicas != 2 || found.Annotations[specHashAnnotation] == created.Annotations[specHashAnnotation] {
+		t.Fatalf("handleStatefulSetGeneric: desired state not applied (%v)", found.Annotations)
+	}
+}
+
+func TestHandleStatefulSetConfigChecksum(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "spec.json"}
+	chainspec := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "chainspec"},
+		Data:       map[string]string{"spec.json": `{"name":"Local"}`, "unused.json": "{}"},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("corev1.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot, chainspec)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	created := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, created); err != nil || created.Spec.Template.Annotations[checksumAnnotation] == "" {
+		t.Fatalf("handleStatefulSetGeneric: checksum annotation not set (%v)", err)
+	}
+	<-recorder.Events
+
+	// a key of the ConfigMap which is not mounted does not roll the pods
+	chainspec.Data["unused.json"] = `{"unused":true}`
+	if err := client.Update(context.TODO(), chainspec); err != nil {
+		t.Fatalf("Update: (%v)", err)
+	}
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	found := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, found); err != nil || found.ResourceVersion != created.ResourceVersion {
+		t.Fatalf("handleStatefulSetGeneric: unexpected update (%v)", err)
+	}
+
+	// the mounted chainspec changed: the checksum of the pod template changes, rolling the pods
+	chainspec.Data["spec.json"] = `{"name":"Other"}`
+	if err := client.Update(context.TODO(), chainspec); err != nil {
+		t.Fatalf("Update: (%v)", err)
+	}
+	if _, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot)); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, found); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if found.Spec.Template.Annotations[checksumAnnotation] == created.Spec.Template.Annotations[checksumAnnotation] {
+		t.Fatalf("handleStatefulSetGeneric: checksum not updated (%v)", found.Spec.Template.Annotations)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Normal ConfigChanged") {
+		t.Fatalf("handleStatefulSetGeneric: unexpected event (%v)", event)
+	}
+}
+
+func TestHandleStatefulSetServiceName(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	// a StatefulSet created before the headless Services
+	current := newStatefulSetSentry(polkadot)
+	current.Spec.ServiceName = serviceName
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot, current)
+	recorder := record.NewFakeRecorder(1)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	// the service name is immutable: the StatefulSet is deleted, to be recreated at the next reconcile
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetSentry(polkadot))
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, &v1.StatefulSet{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleStatefulSetGeneric: StatefulSet not deleted (%v)", err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Normal Deleted") {
+		t.Fatalf("handleStatefulSetGeneric: unexpected event (%v)", event)
+	}
+}
+
+func TestHandleStatefulSetMaintenance(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Sentry.Replicas = 2
+	current := newStatefulSetValidator(polkadot)
+	if err := setStatefulSetHash(current); err != nil {
+		t.Fatalf("setStatefulSetHash: (%v)", err)
+	}
+
+	// the validator is scaled to zero, the sentries are untouched
+	polkadot.Spec.Validator.Maintenance = polkadotv1alpha1.Maintenance{Enabled: true}
+	if replicas := *newStatefulSetValidator(polkadot).Spec.Replicas; replicas != 0 {
+		t.Fatalf("newStatefulSetValidator: unexpected replicas in maintenance (%v)", replicas)
+	}
+	if replicas := *newStatefulSetSentry(polkadot).Spec.Replicas; replicas != 2 {
+		t.Fatalf("newStatefulSetSentry: unexpected replicas (%v)", replicas)
+	}
+
+	// the frozen validator is not updated
+	polkadot.Spec.Validator.Maintenance.Mode = MaintenanceFreeze
+	polkadot.Spec.ClientVersion = "v0.8.3"
+	client := newFakeClient(scheme, polkadot, current)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, newStatefulSetValidator(polkadot))
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+	found := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: ValidatorSSName}, found); err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v)", err)
+	}
+	if found.Annotations[specHashAnnotation] != current.Annotations[specHashAnnotation] {
+		t.Fatalf("handleStatefulSetGeneric: frozen StatefulSet updated")
+	}
+}
+
+func TestHandleStatefulSetNodePoolsAfterStatusUpdate(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// the pool overrides the replicas of the sentry section, the sentries publishing the address of their node
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = ""
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.Sentry.HostNetwork = true
+	polkadot.Spec.Sentry.AutoPublicAddr = true
+	polkadot.Spec.NodePools = []polkadotv1alpha1.NodePool{{Role: "sentry", Replicas: 3}}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: record.NewFakeRecorder(10)}
+	handled, err := reconciler.handleCustomResource(*getFakeRequest())
+	if handled == nil || err != nil {
+		t.Fatalf("handleCustomResource: (%v)", err)
+	}
+
+	// the public address is written in the status before the StatefulSet is handled
+	if _, err := reconciler.handlePublicAddresses(handled); err != nil || len(handled.Status.PublicAddresses) != 1 {
+		t.Fatalf("handlePublicAddresses: (%v, %v)", handled.Status.PublicAddresses, err)
+	}
+	if handled.Spec.Sentry.Replicas != 3 {
+		t.Fatalf("handlePublicAddresses: node pools dropped by the status update (%d replicas)", handled.Spec.Sentry.Replicas)
+	}
+	if _, err := reconciler.handleStatefulSet(handled); err != nil {
+		t.Fatalf("handleStatefulSet: (%v)", err)
+	}
+	sentry := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, sentry); err != nil || *sentry.Spec.Replicas != 3 {
+		t.Fatalf("handleStatefulSet: unexpected sentries (%v)", err)
+	}
+
+	// the status is written with the resourceVersion of the previous write
+	handled.Status.PublicAddresses = nil
+	if err := reconciler.updateStatus(handled); err != nil {
+		t.Fatalf("updateStatus: (%v)", err)
+	}
+}
+
+func TestHandleStatefulSetNodePools(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = ""
+	polkadot.Spec.NodePools = []polkadotv1alpha1.NodePool{
+		{Role: "sentry", Replicas: 2},
+		{Role: "validator"},
+		{Role: "archive", Replicas: 1, ExtraArgs: []string{"--db-cache", "4096"}},
+	}
+	applyNodePools(polkadot)
+
+	roles := []string{}
+	for _, rr := range getRoleResources(polkadot) {
+		roles = append(roles, rr.role)
+	}
+	if strings.Join(roles, ",") != "sentry,validator,archive" {
+		t.Fatalf("getRoleResources: unexpected roles (%v)", roles)
+	}
+	if !isSentryAndValidator(polkadot) || isRpcNodeAddOn(polkadot) {
+		t.Fatalf("getNodePools: unexpected topology (%v)", getNodePools(polkadot))
+	}
+
+	client := newFakeClient(scheme, polkadot)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+	// a StatefulSet is created by each reconcile, until the ones of all the pools exist
+	for i := 0; i < 3; i++ {
+		isRequeueForced, err := reconciler.handleStatefulSet(polkadot)
+		if !isRequeueForced || err != nil {
+			t.Fatalf("handleStatefulSet: (%v, %v)", isRequeueForced, err)
+		}
+	}
+	isRequeueForced, err := reconciler.handleStatefulSet(polkadot)
+	if isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSet: (%v, %v)", isRequeueForced, err)
+	}
+
+	sentry := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, sentry); err != nil || *sentry.Spec.Replicas != 2 {
+		t.Fatalf("handleStatefulSet: unexpected sentries (%v)", err)
+	}
+	validator := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: ValidatorSSName}, validator); err != nil {
+		t.Fatalf("handleStatefulSet: (%v)", err)
+	}
+	// the validator of the pools runs behind the sentries
+	if command := strings.Join(validator.Spec.Template.Spec.Containers[0].Command, " "); !strings.Contains(command, "--reserved-only") {
+		t.Fatalf("handleStatefulSet: validator not behind the sentries (%v)", command)
+	}
+	archive := &v1.StatefulSet{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: ArchiveSSName}, archive); err != nil {
+		t.Fatalf("handleStatefulSet: (%v)", err)
+	}
+	if command := strings.Join(archive.Spec.Template.Spec.Containers[0].Command, " "); !strings.Contains(command, "--db-cache 4096") {
+		t.Fatalf("handleStatefulSet: extra arguments of the pool not passed (%v)", command)
+	}
+}
+
+func TestHandleStatefulSetPodManagementPolicy(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 10
+	current := newStatefulSetSentry(polkadot)
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// Create a fake client to mock API calls.
+	client := newFakeClient(scheme, polkadot, current)
+	recorder := record.NewFakeRecorder(1)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+	// the default policy of the API server is not a change
+	defaulted := newStatefulSetSentry(polkadot)
+	defaulted.Spec.PodManagementPolicy = v1.OrderedReadyPodManagement
+	if isPodManagementPolicyChanged(current, defaulted) {
+		t.Fatalf("isPodManagementPolicyChanged: OrderedReady default reported as a change")
+	}
+
+	// the pod management policy is immutable: the StatefulSet is deleted, to be recreated at the next reconcile
+	polkadot.Spec.Sentry.PodManagementPolicy = v1.ParallelPodManagement
+	desired := newStatefulSetSentry(polkadot)
+	if desired.Spec.PodManagementPolicy != v1.ParallelPodManagement {
+		t.Fatalf("newStatefulSetSentry: unexpected pod management policy (%v)", desired.Spec.PodManagementPolicy)
+	}
+	isRequeueForced, err := reconciler.handleStatefulSetGeneric(polkadot, desired)
+	if !isRequeueForced || err != nil {
+		t.Fatalf("handleStatefulSetGeneric: (%v, %v)", isRequeueForced, err)
+	}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: SentrySSName}, &v1.StatefulSet{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("handleStatefulSetGeneric: StatefulSet not deleted (%v)", err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Normal Deleted") {
+		t.Fatalf("handleStatefulSetGeneric: unexpected event (%v)", event)
+	}
 }
 
+func TestHandleStatefulSetBootnodeNodeKeys(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		nodeKeys   []string
+		isExpected bool
+	}{
+		{
+			name:       "Node key of each bootnode",
+			nodeKeys:   []string{strings.Repeat("01", 32), "0x" + strings.Repeat("02", 32)},
+			isExpected: true,
+		},
+		{
+			name:       "Missing node key",
+			nodeKeys:   []string{strings.Repeat("01", 32)},
+			isExpected: false,
+		},
+		{
+			name:       "Invalid node key",
+			nodeKeys:   []string{strings.Repeat("01", 32), "02"},
+			isExpected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(Bootnode)
+			polkadot.Spec.Bootnode.Replicas = 2
+			polkadot.Spec.Bootnode.NodeKeys = test.nodeKeys
+
+			client := newFakeClient(scheme, polkadot)
+			recorder := record.NewFakeRecorder(1)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme, recorder: recorder}
+
+			_, err := reconciler.handleStatefulSet(polkadot)
+			if (err == nil) != test.isExpected {
+				t.Fatalf("handleStatefulSet: unexpected error (%v)", err)
+			}
+			// the StatefulSet of the bootnodes without a valid node key each is not created
+			err = client.Get(context.TODO(), types.NamespacedName{Name: BootnodeSSName}, &v1.StatefulSet{})
+			if errors.IsNotFound(err) == test.isExpected {
+				t.Fatalf("handleStatefulSet: unexpected StatefulSet (%v)", err)
+			}
+			if !test.isExpected {
+				if event := <-recorder.Events; !strings.HasPrefix(event, "Warning InvalidNodeKeys") {
+					t.Fatalf("handleStatefulSet: unexpected event (%v)", event)
+				}
+			}
+		})
+	}
+}
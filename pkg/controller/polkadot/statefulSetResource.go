@@ -7,68 +7,119 @@ import (
 	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"strconv"
+	"strings"
 )
 
-func getCommands(nodeKey,clientName string, isDataPersistenceEnabled, isMetricsSupportEnabled bool) []string{
+func getCommands(nodeKey string, nodeKeySecretRef *corev1.SecretKeySelector, clientName string, isDataPersistenceEnabled, isMetricsSupportEnabled bool, ports polkadotv1alpha1.Ports) []string{
 	c := []string{
 		"polkadot",
-		"--node-key", nodeKey,
 		"--name", clientName,
 		"--port",
-		strconv.Itoa(config.P2PPortEnvVar.Value),
+		strconv.Itoa(int(ports.P2P)),
 		"--rpc-port",
-		strconv.Itoa(config.RPCPortEnvVar.Value),
+		strconv.Itoa(int(ports.RPC)),
 		"--ws-port",
-		strconv.Itoa(config.WSPortEnvVar.Value),
+		strconv.Itoa(int(ports.WS)),
 		"--unsafe-rpc-external",
 		"--unsafe-ws-external",
 		"--rpc-cors=all",
 		//"--no-telemetry",
 	}
+	if nodeKeySecretRef != nil {
+		c = append(c, "--node-key-file", nodeKeyMountPath+"/"+nodeKeyFileName)
+	} else if nodeKey != "" {
+		c = append(c, "--node-key", nodeKey)
+	}
 	if isDataPersistenceEnabled == true {
 		c = append(c,"-d=" + volumeMountPath)
 	}
 	if isMetricsSupportEnabled == true {
-		c = append(c, "--prometheus-external", "--prometheus-port", strconv.Itoa(config.MetricsPortEnvVar.Value))
+		c = append(c, "--prometheus-external", "--prometheus-port", strconv.Itoa(int(ports.Prometheus)))
+	}
+	return c
+}
+
+// getOptionsCommands returns the client arguments of the role options (sync, pruning, vault), the defaults of the client apply to the empty ones
+func getOptionsCommands(options polkadotv1alpha1.NodeOptions) []string {
+	c := []string{}
+	if options.SyncMode != "" {
+		c = append(c, "--sync", options.SyncMode)
+	}
+	if options.StatePruning != "" {
+		c = append(c, "--pruning", options.StatePruning)
+	}
+	if options.BlocksPruning != "" {
+		c = append(c, "--blocks-pruning", options.BlocksPruning)
 	}
+	if options.NoTelemetry == true {
+		c = append(c, "--no-telemetry")
+	} else if options.TelemetryURL != "" {
+		c = append(c, "--telemetry-url", options.TelemetryURL+" "+strconv.Itoa(int(options.TelemetryVerbosity)))
+	}
+	c = append(c, getVaultCommands(options.Vault)...)
 	return c
 }
 
 type Parameters struct{
 	name                     string
+	headlessServiceName      string
 	namespace                string
 	labels                   map[string]string
 	replicas                 int32
 	version                  string
+	image                    string
 	commands                 []string
 	clientContainerResources corev1.ResourceRequirements
 	dataPersistence          polkadotv1alpha1.DataPersistenceSupport
 	isMetricsSupportEnabled  bool
+	options                  polkadotv1alpha1.NodeOptions
+	metadata                 polkadotv1alpha1.ResourceMetadata
+	imagePullSecrets         []corev1.LocalObjectReference
+	nodeKeySecretRef         *corev1.SecretKeySelector
+	nodeKeySecrets           []string
+	keystoreSecretRef        *corev1.LocalObjectReference
+	chainPreset              *config.ChainPreset
+	chainspecConfigMapRef    *corev1.ConfigMapKeySelector
+	sidecars                 []corev1.Container
+	isLeaderElectionEnabled  bool
+	ports                    polkadotv1alpha1.Ports
+	isHardened               bool
 }
 
 func newStatefulSetSentry(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
 	replicas := CRInstance.Spec.Sentry.Replicas
-	version := CRInstance.Spec.ClientVersion
+	version := getClientVersion(CRInstance, CRInstance.Spec.Sentry.NodeOptions)
 	clientName := CRInstance.Spec.Sentry.ClientName
 	nodeKey := CRInstance.Spec.Sentry.NodeKey
-	clientContainerResources := CRInstance.Spec.Sentry.Resources
-	dataPersistence := CRInstance.Spec.Sentry.DataPersistenceSupport
+	nodeKeySecretRef := CRInstance.Spec.Sentry.NodeKeySecretRef
+	nodeKeySecrets := getProjectedNodeKeySecrets(CRInstance, "sentry", replicas, CRInstance.Spec.Sentry.NodeOptions)
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.Sentry.Resources)
+	dataPersistence := getDataPersistence(CRInstance.Spec.Sentry.DataPersistenceSupport)
 	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
 
 	labels := getSentrylabels()
 
-	commands := getCommands(nodeKey,clientName,dataPersistence.Enabled,isMetricsSupportEnabled)
+	commands := getCommands(nodeKey,nodeKeySecretRef,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
 	commands = append(commands,"--sentry")
-	if CRKind(CRInstance.Spec.Kind) == SentryAndValidator {
-		reservedValidatorID := CRInstance.Spec.Sentry.ReservedValidatorID
-		commands = append(commands, "--reserved-nodes", "/dns4/"+ServiceValidatorName+"/tcp/30333/p2p/"+reservedValidatorID)
+	commands = append(commands, getGeneratedNodeKeyCommands(nodeKeySecrets)...)
+	commands = append(commands, getChainCommands(CRInstance)...)
+	reservedNodesCommands := []string{}
+	if isSentryAndValidator(CRInstance) {
+		reservedNodesCommands = getReservedNodesCommands(getHeadlessServiceName(ServiceValidatorName), getPorts(CRInstance).P2P, CRInstance.Spec.Sentry.ReservedValidatorID, getReservedPeers(CRInstance).Validators)
 	}
+	commands = append(commands, appendReservedNodes(reservedNodesCommands, getExternalValidators(CRInstance))...)
+	commands = append(commands, getBootnodesCommands(CRInstance)...)
+	commands = append(commands, getPublicAddrCommands(CRInstance, "sentry")...)
+	commands = append(commands, getOptionsCommands(CRInstance.Spec.Sentry.NodeOptions)...)
+	commands = append(commands, CRInstance.Spec.Sentry.ExtraArgs...)
 
 	p := Parameters{
 		name:                     SentrySSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceSentryName),
 		namespace:                CRInstance.Namespace,
 		labels:                   labels,
 		replicas:                 replicas,
@@ -77,6 +128,15 @@ func newStatefulSetSentry(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.Statefu
 		clientContainerResources: clientContainerResources,
 		dataPersistence:          dataPersistence,
 		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  CRInstance.Spec.Sentry.NodeOptions,
+		nodeKeySecretRef:         nodeKeySecretRef,
+		nodeKeySecrets:           nodeKeySecrets,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
 	}
 
 	return getStatefulSet(p)
@@ -84,26 +144,66 @@ func newStatefulSetSentry(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.Statefu
 
 func newStatefulSetValidator(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
 	replicas := int32(1)
-	version := CRInstance.Spec.ClientVersion
+	version := getClientVersion(CRInstance, CRInstance.Spec.Validator.NodeOptions)
 	clientName := CRInstance.Spec.Validator.ClientName
 	nodeKey := CRInstance.Spec.Validator.NodeKey
-	clientContainerResources := CRInstance.Spec.Validator.Resources
-	dataPersistence := CRInstance.Spec.Validator.DataPersistenceSupport
+	nodeKeySecretRef := CRInstance.Spec.Validator.NodeKeySecretRef
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.Validator.Resources)
+	dataPersistence := getDataPersistence(CRInstance.Spec.Validator.DataPersistenceSupport)
 	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
 
 	labels := getValidatorLabels()
 
-	commands := getCommands(nodeKey,clientName,dataPersistence.Enabled,isMetricsSupportEnabled)
+	commands := getCommands(nodeKey,nodeKeySecretRef,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
 	commands = append(commands,"--validator")
-	if CRKind(CRInstance.Spec.Kind) == SentryAndValidator {
-		reservedSentryID := CRInstance.Spec.Validator.ReservedSentryID
-		commands = append(commands,
-			"--reserved-only",
-			"--reserved-nodes", "/dns4/"+ServiceSentryName+"/tcp/30333/p2p/"+reservedSentryID)
+	if CRInstance.Spec.Validator.KeystoreSecretRef != nil {
+		commands = append(commands, "--keystore-path", keystoreMountPath)
+	}
+	sidecars := []corev1.Container{}
+	if remoteSigner := CRInstance.Spec.Validator.RemoteSigner; remoteSigner != nil {
+		commands = append(commands, "--keystore-uri", remoteSigner.Endpoint)
+		if remoteSigner.Sidecar != nil {
+			sidecars = append(sidecars, *remoteSigner.Sidecar)
+		}
+	}
+	if isEraExporterEnabled(CRInstance) {
+		sidecars = append(sidecars, getEraExporterContainer(CRInstance))
+	}
+	networkCommands := getChainCommands(CRInstance)
+	if isSentryAndValidator(CRInstance) || len(CRInstance.Spec.Validator.ExternalSentries) > 0 {
+		reservedNodesCommands := []string{}
+		if isSentryAndValidator(CRInstance) {
+			reservedNodesCommands = getReservedNodesCommands(getHeadlessServiceName(ServiceSentryName), getPorts(CRInstance).P2P, CRInstance.Spec.Validator.ReservedSentryID, getReservedPeers(CRInstance).Sentries)
+		}
+		networkCommands = append(networkCommands, "--reserved-only")
+		networkCommands = append(networkCommands, appendReservedNodes(reservedNodesCommands, CRInstance.Spec.Validator.ExternalSentries)...)
+	} else {
+		networkCommands = append(networkCommands, getBootnodesCommands(CRInstance)...)
+		networkCommands = append(networkCommands, getPublicAddrCommands(CRInstance, "validator")...)
+	}
+	networkCommands = append(networkCommands, getOptionsCommands(CRInstance.Spec.Validator.NodeOptions)...)
+	networkCommands = append(networkCommands, CRInstance.Spec.Validator.ExtraArgs...)
+	commands = append(commands, networkCommands...)
+
+	options := CRInstance.Spec.Validator.NodeOptions
+	if isHighAvailabilityEnabled(CRInstance) {
+		// the standby pod is a full node with its own identity, it only gets the node key and the session keys once activated
+		replicas = validatorHAReplicas
+		standbyCommands := getCommands("",nil,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
+		standbyCommands = append(standbyCommands, networkCommands...)
+		if isLeaderElectionEnabled(CRInstance) {
+			commands = getCommandsWithLeadership(commands, standbyCommands)
+			sidecars = append(sidecars, getLeaderElectorContainer(CRInstance))
+			options.ServiceAccountName = getLeaderElectionServiceAccountName(CRInstance)
+		} else {
+			commands = getCommandsWithActivePod(commands, standbyCommands)
+			options.Env = append(getHighAvailabilityEnv(), options.Env...)
+		}
 	}
 
 	p := Parameters{
 		name:                     ValidatorSSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceValidatorName),
 		namespace:                CRInstance.Namespace,
 		labels:                   labels,
 		replicas:                 replicas,
@@ -112,72 +212,609 @@ func newStatefulSetValidator(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.Stat
 		clientContainerResources: clientContainerResources,
 		dataPersistence:          dataPersistence,
 		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  options,
+		nodeKeySecretRef:         nodeKeySecretRef,
+		keystoreSecretRef:        CRInstance.Spec.Validator.KeystoreSecretRef,
+		sidecars:                 sidecars,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
+		isLeaderElectionEnabled:  isLeaderElectionEnabled(CRInstance),
 	}
 
 	return getStatefulSet(p)
 }
 
+func newBootNodeStatefulSetForCR(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
+	replicas := CRInstance.Spec.Bootnode.Replicas
+	version := getClientVersion(CRInstance, CRInstance.Spec.Bootnode.NodeOptions)
+	clientName := CRInstance.Spec.Bootnode.ClientName
+	nodeKeys := CRInstance.Spec.Bootnode.NodeKeys
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.Bootnode.Resources)
+	dataPersistence := getDataPersistence(CRInstance.Spec.Bootnode.DataPersistenceSupport)
+	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
+
+	labels := getBootnodeLabels()
+
+	commands := getCommands("",nil,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
+	commands = append(commands, getChainCommands(CRInstance)...)
+	commands = append(commands, getBootnodesCommands(CRInstance)...)
+	commands = append(commands, getOptionsCommands(CRInstance.Spec.Bootnode.NodeOptions)...)
+	commands = append(commands, CRInstance.Spec.Bootnode.ExtraArgs...)
+
+	p := Parameters{
+		name:                     BootnodeSSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceBootnodeName),
+		namespace:                CRInstance.Namespace,
+		labels:                   labels,
+		replicas:                 replicas,
+		version:                  version,
+		commands:                 getCommandsWithOrdinalNodeKey(commands, nodeKeys),
+		clientContainerResources: clientContainerResources,
+		dataPersistence:          dataPersistence,
+		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  CRInstance.Spec.Bootnode.NodeOptions,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
+	}
+
+	return getStatefulSet(p)
+}
+
+func newStatefulSetArchive(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
+	replicas := CRInstance.Spec.Archive.Replicas
+	version := getClientVersion(CRInstance, CRInstance.Spec.Archive.NodeOptions)
+	clientName := CRInstance.Spec.Archive.ClientName
+	nodeKey := CRInstance.Spec.Archive.NodeKey
+	nodeKeySecretRef := CRInstance.Spec.Archive.NodeKeySecretRef
+	nodeKeySecrets := getProjectedNodeKeySecrets(CRInstance, "archive", replicas, CRInstance.Spec.Archive.NodeOptions)
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.Archive.Resources)
+	dataPersistence := getArchiveDataPersistence(CRInstance.Spec.Archive.DataPersistenceSupport)
+	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
+
+	labels := getArchiveLabels()
+
+	commands := getCommands(nodeKey,nodeKeySecretRef,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
+	commands = append(commands, "--pruning", "archive")
+	commands = append(commands, getGeneratedNodeKeyCommands(nodeKeySecrets)...)
+	archiveOptions := CRInstance.Spec.Archive.NodeOptions
+	archiveOptions.StatePruning = ""
+	commands = append(commands, getChainCommands(CRInstance)...)
+	commands = append(commands, getBootnodesCommands(CRInstance)...)
+	commands = append(commands, getPublicAddrCommands(CRInstance, "archive")...)
+	commands = append(commands, getOptionsCommands(archiveOptions)...)
+	commands = append(commands, CRInstance.Spec.Archive.ExtraArgs...)
+
+	p := Parameters{
+		name:                     ArchiveSSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceArchiveName),
+		namespace:                CRInstance.Namespace,
+		labels:                   labels,
+		replicas:                 replicas,
+		version:                  version,
+		commands:                 commands,
+		clientContainerResources: clientContainerResources,
+		dataPersistence:          dataPersistence,
+		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  CRInstance.Spec.Archive.NodeOptions,
+		nodeKeySecretRef:         nodeKeySecretRef,
+		nodeKeySecrets:           nodeKeySecrets,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
+	}
+
+	return getStatefulSet(p)
+}
+
+func newStatefulSetRpcNode(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
+	replicas := CRInstance.Spec.RpcNode.Replicas
+	version := getClientVersion(CRInstance, CRInstance.Spec.RpcNode.NodeOptions)
+	clientName := CRInstance.Spec.RpcNode.ClientName
+	nodeKey := CRInstance.Spec.RpcNode.NodeKey
+	nodeKeySecretRef := CRInstance.Spec.RpcNode.NodeKeySecretRef
+	nodeKeySecrets := getProjectedNodeKeySecrets(CRInstance, "rpcnode", replicas, CRInstance.Spec.RpcNode.NodeOptions)
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.RpcNode.Resources)
+	dataPersistence := getDataPersistence(CRInstance.Spec.RpcNode.DataPersistenceSupport)
+	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
+
+	labels := getRpcNodeLabels()
+
+	commands := getSafeRPCCommands(getCommands(nodeKey,nodeKeySecretRef,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance)))
+	commands = append(commands, getGeneratedNodeKeyCommands(nodeKeySecrets)...)
+	commands = append(commands, getChainCommands(CRInstance)...)
+	commands = append(commands, getBootnodesCommands(CRInstance)...)
+	commands = append(commands, getPublicAddrCommands(CRInstance, "rpcnode")...)
+	commands = append(commands, getOptionsCommands(CRInstance.Spec.RpcNode.NodeOptions)...)
+	commands = append(commands, CRInstance.Spec.RpcNode.ExtraArgs...)
+
+	p := Parameters{
+		name:                     RpcNodeSSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceRpcNodeName),
+		namespace:                CRInstance.Namespace,
+		labels:                   labels,
+		replicas:                 replicas,
+		version:                  version,
+		commands:                 commands,
+		clientContainerResources: clientContainerResources,
+		dataPersistence:          dataPersistence,
+		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  CRInstance.Spec.RpcNode.NodeOptions,
+		nodeKeySecretRef:         nodeKeySecretRef,
+		nodeKeySecrets:           nodeKeySecrets,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
+	}
+
+	return getStatefulSet(p)
+}
+
+func newStatefulSetCollator(CRInstance *polkadotv1alpha1.Polkadot) *appsv1.StatefulSet {
+	replicas := CRInstance.Spec.Collator.Replicas
+	version := getClientVersion(CRInstance, CRInstance.Spec.Collator.NodeOptions)
+	image := CRInstance.Spec.Collator.Image
+	clientName := CRInstance.Spec.Collator.ClientName
+	nodeKey := CRInstance.Spec.Collator.NodeKey
+	nodeKeySecretRef := CRInstance.Spec.Collator.NodeKeySecretRef
+	nodeKeySecrets := getProjectedNodeKeySecrets(CRInstance, "collator", replicas, CRInstance.Spec.Collator.NodeOptions)
+	clientContainerResources := getChainResources(CRInstance, CRInstance.Spec.Collator.Resources)
+	dataPersistence := getDataPersistence(CRInstance.Spec.Collator.DataPersistenceSupport)
+	isMetricsSupportEnabled := CRInstance.Spec.MetricsSupport.Enabled
+
+	labels := getCollatorLabels()
+
+	commands := getCommands(nodeKey,nodeKeySecretRef,clientName,dataPersistence.Enabled,isMetricsSupportEnabled,getPorts(CRInstance))
+	commands = append(commands, getGeneratedNodeKeyCommands(nodeKeySecrets)...)
+	commands = append(commands, getPublicAddrCommands(CRInstance, "collator")...)
+	commands = append(commands, getOptionsCommands(CRInstance.Spec.Collator.NodeOptions)...)
+	commands = append(commands, CRInstance.Spec.Collator.ExtraArgs...)
+	collator := CRInstance.Spec.Collator
+	if chain := getChain(CRInstance); chain != "" {
+		collator.RelayChainSpec = chain
+	}
+	commands = getCollatorCommands(collator, commands)
+	// the bootnodes are the ones of the relay chain, passed to the embedded relay chain node
+	commands = append(commands, getBootnodesCommands(CRInstance)...)
+
+	p := Parameters{
+		name:                     CollatorSSName,
+		headlessServiceName:      getHeadlessServiceName(ServiceCollatorName),
+		namespace:                CRInstance.Namespace,
+		labels:                   labels,
+		replicas:                 replicas,
+		version:                  version,
+		image:                    image,
+		commands:                 commands,
+		clientContainerResources: clientContainerResources,
+		dataPersistence:          dataPersistence,
+		isMetricsSupportEnabled:  isMetricsSupportEnabled,
+		options:                  CRInstance.Spec.Collator.NodeOptions,
+		nodeKeySecretRef:         nodeKeySecretRef,
+		nodeKeySecrets:           nodeKeySecrets,
+		metadata:                 CRInstance.Spec.Metadata,
+		imagePullSecrets:         CRInstance.Spec.ImagePullSecrets,
+		isHardened:               isHardened(CRInstance),
+		ports:                    getPorts(CRInstance),
+		chainspecConfigMapRef:    getChainspecConfigMapRef(CRInstance),
+		chainPreset:              getChainPreset(CRInstance),
+	}
+
+	return getStatefulSet(p)
+}
+
+// getCollatorCommands turns the client commands into the parachain ones: the parachain arguments come first,
+// then the arguments of the embedded relay chain node after the "--" separator
+func getCollatorCommands(collator polkadotv1alpha1.Collator, commands []string) []string {
+	binary := collator.Binary
+	if binary == "" {
+		binary = collatorBinary
+	}
+	c := append([]string{binary}, commands[1:]...)
+	c = append(c, "--collator", "--chain", collator.ParachainChainSpec)
+	if collator.RelayChainEndpoint != "" {
+		c = append(c, "--relay-chain-rpc-url", collator.RelayChainEndpoint)
+	}
+	c = append(c, "--", "--chain", collator.RelayChainSpec)
+	return c
+}
+
+// getSafeRPCCommands replaces the unsafe RPC exposure with the external one, which only serves the safe RPC methods to the public
+func getSafeRPCCommands(commands []string) []string {
+	c := []string{}
+	for _, command := range commands {
+		switch command {
+		case "--unsafe-rpc-external":
+			c = append(c, "--rpc-external")
+		case "--unsafe-ws-external":
+			c = append(c, "--ws-external")
+		default:
+			c = append(c, command)
+		}
+	}
+	return c
+}
+
+// getDataPersistence applies the storage settings of the CR (class, size, access mode) to a copy of the volume claim
+func getDataPersistence(dataPersistence polkadotv1alpha1.DataPersistenceSupport) polkadotv1alpha1.DataPersistenceSupport {
+	result := *dataPersistence.DeepCopy()
+	if result.Enabled != true {
+		return result
+	}
+	claim := &result.PersistentVolumeClaim
+	if result.StorageClassName != "" {
+		storageClassName := result.StorageClassName
+		claim.Spec.StorageClassName = &storageClassName
+	}
+	if result.StorageSize != nil {
+		if claim.Spec.Resources.Requests == nil {
+			claim.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = result.StorageSize.DeepCopy()
+	}
+	if result.AccessMode != "" {
+		claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{result.AccessMode}
+	}
+	if result.RestoreFrom != nil && result.RestoreFrom.VolumeSnapshotName != "" {
+		apiGroup := volumeSnapshotAPIGroup
+		claim.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     "VolumeSnapshot",
+			Name:     result.RestoreFrom.VolumeSnapshotName,
+		}
+	}
+	return result
+}
+
+// getArchiveDataPersistence fills the volume claim left unset by the user with defaults sized for the whole chain history
+func getArchiveDataPersistence(dataPersistence polkadotv1alpha1.DataPersistenceSupport) polkadotv1alpha1.DataPersistenceSupport {
+	result := getDataPersistence(dataPersistence)
+	if result.Enabled != true {
+		return result
+	}
+	claim := &result.PersistentVolumeClaim
+	if claim.ObjectMeta.Name == "" {
+		claim.ObjectMeta.Name = archiveVolumeName
+	}
+	if len(claim.Spec.AccessModes) == 0 {
+		claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+	if _, isFound := claim.Spec.Resources.Requests[corev1.ResourceStorage]; !isFound {
+		if claim.Spec.Resources.Requests == nil {
+			claim.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(archiveVolumeSize)
+	}
+	return result
+}
+
+// getCommandsWithOrdinalNodeKey wraps the client commands in a shell that picks the node key matching the pod ordinal,
+// so that each replica of the StatefulSet keeps the same identity across restarts and rescheduling
+func getCommandsWithOrdinalNodeKey(commands, nodeKeys []string) []string {
+	script := "set -- " + getShellQuoted(nodeKeys) + "; " +
+		"shift ${HOSTNAME##*-}; " +
+		"exec " + getShellQuoted(commands) + ` --node-key "$1"`
+	return []string{"/bin/sh", "-c", script}
+}
+
+// getCommandsWithActivePod wraps the client commands in a shell that starts the validator only on the active pod named
+// by the high availability ConfigMap, and only once no previous active pod is fenced. The other pod starts as a standby node
+func getCommandsWithActivePod(activeCommands, standbyCommands []string) []string {
+	script := `if [ "$` + haActivePodEnv + `" = "$HOSTNAME" ] && [ -z "$` + haFencedPodEnv + `" ]; then ` +
+		"exec " + getShellQuoted(activeCommands) + "; fi; " +
+		"exec " + getShellQuoted(standbyCommands)
+	return []string{"/bin/sh", "-c", script}
+}
+
+func getShellQuoted(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
 func getStatefulSet(p Parameters) *appsv1.StatefulSet{
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      p.name,
-			Namespace: p.namespace,
-			Labels:    getCopyLabelsWithVersion(p.labels, p.version),
+			Name:        p.name,
+			Namespace:   p.namespace,
+			Labels:      getCopyLabelsWithCustom(getCopyLabelsWithVersion(p.labels, p.version), p.metadata.Labels),
+			Annotations: getCopy(p.metadata.Annotations),
 		},
 		Spec: getStatefulSetSpec(p),
 	}
 }
 
 func getStatefulSetSpec(p Parameters) appsv1.StatefulSetSpec{
+	if getMaintenanceMode(p.options) == MaintenanceScaleDown {
+		p.replicas = 0
+	}
 	sSpec := appsv1.StatefulSetSpec{
 		Replicas: &p.replicas,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: p.labels,
 		},
-		ServiceName: serviceName,
+		ServiceName: p.headlessServiceName,
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: p.labels,
+				Labels:      getCopyLabelsWithCustom(p.labels, p.metadata.Labels),
+				Annotations: getCopyLabelsWithCustom(getVaultAnnotations(p.options.Vault), p.metadata.Annotations),
 			},
 			Spec: getPodSpec(p),
 		},
 	}
+	if p.isHardened {
+		hardenPodTemplate(&sSpec.Template)
+	}
+	if p.options.UpdateStrategy != nil {
+		sSpec.UpdateStrategy = *p.options.UpdateStrategy.DeepCopy()
+	}
+	sSpec.PodManagementPolicy = p.options.PodManagementPolicy
 	if p.dataPersistence.Enabled == true{
 		sSpec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{ p.dataPersistence.PersistentVolumeClaim }
 	}
 	return sSpec
 }
 
+// getMaintenanceMode returns the maintenance mode of the role, empty if the role is not in maintenance
+func getMaintenanceMode(options polkadotv1alpha1.NodeOptions) string {
+	if !options.Maintenance.Enabled {
+		return ""
+	}
+	if options.Maintenance.Mode == "" {
+		return MaintenanceScaleDown
+	}
+	return options.Maintenance.Mode
+}
+
+// getAffinity returns the affinity of the pods of the role, with the default anti-affinity spreading the pods of the nodes
+// across the zones and the hosts when it is enabled and no podAntiAffinity is set by the user
+func getAffinity(options polkadotv1alpha1.NodeOptions) *corev1.Affinity {
+	if !options.AntiAffinity.Enabled || (options.Affinity != nil && options.Affinity.PodAntiAffinity != nil) {
+		return options.Affinity
+	}
+	affinity := &corev1.Affinity{}
+	if options.Affinity != nil {
+		affinity = options.Affinity.DeepCopy()
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: getAppLabels()}
+	antiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          100,
+			PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: selector, TopologyKey: zoneTopologyKey},
+		}},
+	}
+	hostTerm := corev1.PodAffinityTerm{LabelSelector: selector, TopologyKey: corev1.LabelHostname}
+	if options.AntiAffinity.Mode == AntiAffinityRequired {
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{hostTerm}
+	} else {
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: 50, PodAffinityTerm: hostTerm})
+	}
+	affinity.PodAntiAffinity = antiAffinity
+	return affinity
+}
+
 func getPodSpec(p Parameters) corev1.PodSpec{
 	spec := corev1.PodSpec{
-		SecurityContext: getPodSecurityContext(),
-		Containers: []corev1.Container{
+		SecurityContext: getPodSecurityContext(p.options.PodSecurityContext),
+		Containers: append([]corev1.Container{
 			getContainerClient(p),
-		},
+		}, p.sidecars...),
+		NodeSelector:     p.options.NodeSelector,
+		Affinity:         getAffinity(p.options),
+		Tolerations:      p.options.Tolerations,
+		PriorityClassName: p.options.PriorityClassName,
+		TopologySpreadConstraints: p.options.TopologySpreadConstraints,
+		ImagePullSecrets: p.imagePullSecrets,
+		ServiceAccountName: p.options.ServiceAccountName,
+	}
+	// the pods on the host network resolve the cluster names through the cluster DNS as well
+	if p.options.HostNetwork {
+		spec.HostNetwork = true
+		spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+	if p.nodeKeySecretRef != nil {
+		spec.Volumes = append(spec.Volumes, getNodeKeyVolume(p.nodeKeySecretRef))
+	}
+	if len(p.nodeKeySecrets) > 0 {
+		spec.Volumes = append(spec.Volumes, getNodeKeysVolume(p.nodeKeySecrets), getGeneratedNodeKeyVolume())
 	}
+	if p.keystoreSecretRef != nil {
+		spec.Volumes = append(spec.Volumes, getKeystoreVolume(p.keystoreSecretRef))
+	}
+	if p.chainspecConfigMapRef != nil {
+		spec.Volumes = append(spec.Volumes, getChainspecVolume(p.chainspecConfigMapRef))
+	}
+	if p.isLeaderElectionEnabled {
+		spec.Volumes = append(spec.Volumes, getLeaderVolume())
+	}
+	if p.options.StallDetection.Enabled {
+		spec.Containers = append(spec.Containers, getStallDetectorContainer(p.options.StallDetection, p.ports.RPC))
+		spec.Volumes = append(spec.Volumes, getStallVolume())
+	}
+	if p.options.SyncReadiness.Enabled {
+		spec.Containers = append(spec.Containers, getSyncReadinessContainer(p.options.SyncReadiness, p.ports.RPC))
+	}
+	spec.Containers = append(spec.Containers, p.options.Sidecars...)
 	if p.dataPersistence.Enabled == true{
-		spec.InitContainers = []corev1.Container{ *getVolumePermissionInitContainer(p.dataPersistence.PersistentVolumeClaim.ObjectMeta.Name) }
+		// the fsGroup of the hardened pods owns the data volume, the permissions are not set by a root container
+		if !p.isHardened {
+			spec.InitContainers = []corev1.Container{ *getVolumePermissionInitContainer(p.dataPersistence.PersistentVolumeClaim.ObjectMeta.Name, spec.SecurityContext) }
+		}
+		if p.dataPersistence.Bootstrap != nil && p.dataPersistence.Bootstrap.URL != "" {
+			spec.InitContainers = append(spec.InitContainers, getBootstrapInitContainer(p.dataPersistence.PersistentVolumeClaim.ObjectMeta.Name, *p.dataPersistence.Bootstrap))
+		}
+	}
+	if len(p.nodeKeySecrets) > 0 {
+		spec.InitContainers = append(spec.InitContainers, getNodeKeyInitContainer())
 	}
+	spec.InitContainers = append(spec.InitContainers, p.options.InitContainers...)
 	return spec
 }
 
 func getContainerClient(p Parameters) corev1.Container{
 	container:=corev1.Container{
 			Name:           serviceName,
-			Image:          config.ImageClientEnvVar.Value + ":" + p.version,
+			Image:          getImage(p),
 			Command:        p.commands,
-			Ports:          getContainerPortsClient(),
-			LivenessProbe:  getHealthProbeClient(),
-			ReadinessProbe: getHealthProbeClient(),
+			Ports:          getContainerPortsClient(p.ports),
+			StartupProbe:   getStartupProbeClient(p.options.Probes.Startup),
+			LivenessProbe:  getHealthProbeClient(p.options.Probes.Liveness),
+			ReadinessProbe: getHealthProbeClient(p.options.Probes.Readiness),
 			Resources:     p.clientContainerResources,
+			Env:           getEnv(p.options.Env),
+			SecurityContext: p.options.SecurityContext,
+		}
+		if p.options.HostNetwork {
+			container.Ports = getHostPorts(container.Ports, p.isMetricsSupportEnabled, p.ports.Prometheus)
+		}
+		if p.options.AutoPublicAddr {
+			container.Env = append(container.Env, getNodeIPEnv())
 		}
 		if p.dataPersistence.Enabled == true{
 			container.VolumeMounts=getVolumeMounts(p.dataPersistence.PersistentVolumeClaim.ObjectMeta.Name)
 		}
+		if p.nodeKeySecretRef != nil || len(p.nodeKeySecrets) > 0 {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      nodeKeyVolumeName,
+				MountPath: nodeKeyMountPath,
+				ReadOnly:  true,
+			})
+		}
+		if p.keystoreSecretRef != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      keystoreVolumeName,
+				MountPath: keystoreMountPath,
+				ReadOnly:  true,
+			})
+		}
+		if p.chainspecConfigMapRef != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      chainspecVolumeName,
+				MountPath: chainspecMountPath,
+				ReadOnly:  true,
+			})
+		}
+		if p.isLeaderElectionEnabled {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      leaderVolumeName,
+				MountPath: leaderMountPath,
+				ReadOnly:  true,
+			})
+		}
+		// the progression of the best block replaces the health endpoint as liveness
+		if p.options.StallDetection.Enabled {
+			container.LivenessProbe = getStallProbeClient(p.options.StallDetection)
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      stallVolumeName,
+				MountPath: stallMountPath,
+				ReadOnly:  true,
+			})
+		}
 		return container
 }
 
-func getVolumePermissionInitContainer(volumeMountName string) *corev1.Container {
+// getEnv returns a copy of the environment variables, with the defaults the API server would set on the field references
+func getEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	if len(env) == 0 {
+		return nil
+	}
+	result := make([]corev1.EnvVar, len(env))
+	for i := range env {
+		env[i].DeepCopyInto(&result[i])
+		if result[i].ValueFrom != nil && result[i].ValueFrom.FieldRef != nil && result[i].ValueFrom.FieldRef.APIVersion == "" {
+			result[i].ValueFrom.FieldRef.APIVersion = "v1"
+		}
+	}
+	return result
+}
+
+// getContainerClientFromStatefulSet returns the client container of a StatefulSet, nil if not found
+func getContainerClientFromStatefulSet(statefulSet *appsv1.StatefulSet) *corev1.Container {
+	containers := statefulSet.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == serviceName {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// getClientImage returns the client image of a role, tag included
+func getClientImage(CRInstance *polkadotv1alpha1.Polkadot, role string, options polkadotv1alpha1.NodeOptions) string {
+	if role == "collator" && CRInstance.Spec.Collator.Image != "" {
+		return CRInstance.Spec.Collator.Image
+	}
+	return getImage(Parameters{version: getClientVersion(CRInstance, options), options: options, chainPreset: getChainPreset(CRInstance)})
+}
+
+// getClientVersion returns the client version of the role, the one of the CR unless the role overrides it
+func getClientVersion(CRInstance *polkadotv1alpha1.Polkadot, options polkadotv1alpha1.NodeOptions) string {
+	if options.ClientVersion != "" {
+		return options.ClientVersion
+	}
+	return CRInstance.Spec.ClientVersion
+}
+
+func getImage(p Parameters) string {
+	if p.image != "" {
+		return p.image
+	}
+	if p.options.ClientImage != "" {
+		return p.options.ClientImage + ":" + p.version
+	}
+	if p.chainPreset != nil {
+		return p.chainPreset.Image + ":" + p.version
+	}
+	return config.ImageClientEnvVar.Value + ":" + p.version
+}
+
+// getBootstrapInitContainer downloads and extracts the chain database archive into the data volume, verifying its checksum if given.
+// A marker file prevents the bootstrap from running again, once the volume has been filled
+func getBootstrapInitContainer(volumeMountName string, bootstrap polkadotv1alpha1.Bootstrap) corev1.Container {
+	image := bootstrap.Image
+	if image == "" {
+		image = bootstrapImage
+	}
+	archive := volumeMountPath + "/bootstrap.tar"
+	marker := volumeMountPath + "/.bootstrapped"
+	script := "set -e; " +
+		"if [ -f " + marker + " ]; then exit 0; fi; " +
+		`wget -O ` + archive + ` "$BOOTSTRAP_URL"; ` +
+		`if [ -n "$BOOTSTRAP_CHECKSUM" ]; then echo "$BOOTSTRAP_CHECKSUM  ` + archive + `" | sha256sum -c -; fi; ` +
+		"tar -xf " + archive + " -C " + volumeMountPath + "; " +
+		"rm " + archive + "; " +
+		"touch " + marker
+
+	return corev1.Container{
+		Name:         "bootstrap-data",
+		Image:        image,
+		VolumeMounts: getVolumeMounts(volumeMountName),
+		Command:      []string{"/bin/sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{Name: "BOOTSTRAP_URL", Value: bootstrap.URL},
+			{Name: "BOOTSTRAP_CHECKSUM", Value: bootstrap.Checksum},
+		},
+	}
+}
+
+func getVolumePermissionInitContainer(volumeMountName string, podSecurityContext *corev1.PodSecurityContext) *corev1.Container {
 	rootUser := int64(0)
 	runAsNonRootFalse := false
 
@@ -189,12 +826,32 @@ func getVolumePermissionInitContainer(volumeMountName string) *corev1.Container
 			RunAsUser:          &rootUser,
 			RunAsNonRoot: &runAsNonRootFalse,
 		},
-		Command: []string{"sh", "-c", "chown -R 1000:1000 " + volumeMountPath},
+		Command: []string{"sh", "-c", "chown -R " + getVolumeOwner(podSecurityContext) + " " + volumeMountPath},
+
+	}
+}
 
+// getVolumeOwner returns the user:group owning the data volume, the ones the client container runs with
+func getVolumeOwner(podSecurityContext *corev1.PodSecurityContext) string {
+	user := int64(1000)
+	group := int64(1000)
+	if podSecurityContext != nil && podSecurityContext.RunAsUser != nil {
+		user = *podSecurityContext.RunAsUser
 	}
+	if podSecurityContext != nil && podSecurityContext.FSGroup != nil {
+		group = *podSecurityContext.FSGroup
+	} else if podSecurityContext != nil && podSecurityContext.RunAsGroup != nil {
+		group = *podSecurityContext.RunAsGroup
+	}
+	return strconv.FormatInt(user, 10) + ":" + strconv.FormatInt(group, 10)
 }
 
-func getPodSecurityContext() *corev1.PodSecurityContext {
+// getPodSecurityContext returns the pod security context set in the CR, the default one otherwise
+func getPodSecurityContext(podSecurityContext *corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	if podSecurityContext != nil {
+		return podSecurityContext
+	}
+
 	user := int64(1000)
 	group := int64(1000)
 	runAsNonRoot := true
@@ -207,6 +864,35 @@ func getPodSecurityContext() *corev1.PodSecurityContext {
 	}
 }
 
+// getNodeKeyVolume projects the node key of the Secret in the node-key file, readable by the group of the client
+func getNodeKeyVolume(nodeKeySecretRef *corev1.SecretKeySelector) corev1.Volume {
+	mode := int32(0440)
+	return corev1.Volume{
+		Name: nodeKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  nodeKeySecretRef.Name,
+				Items:       []corev1.KeyToPath{{Key: nodeKeySecretRef.Key, Path: nodeKeyFileName}},
+				DefaultMode: &mode,
+			},
+		},
+	}
+}
+
+// getKeystoreVolume projects every key of the Secret as a keystore file, readable by the group of the client
+func getKeystoreVolume(keystoreSecretRef *corev1.LocalObjectReference) corev1.Volume {
+	mode := int32(0440)
+	return corev1.Volume{
+		Name: keystoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  keystoreSecretRef.Name,
+				DefaultMode: &mode,
+			},
+		},
+	}
+}
+
 func getVolumeMounts(volumeName string) []corev1.VolumeMount{
 	return []corev1.VolumeMount{{
 		Name:      volumeName,
@@ -214,31 +900,74 @@ func getVolumeMounts(volumeName string) []corev1.VolumeMount{
 	}}
 }
 
-func getHealthProbeClient() *corev1.Probe{
-	return &corev1.Probe{
+// getHealthProbeClient returns the HTTP probe of the health endpoint of the RPC port, the thresholds not set in the CR
+// are the defaults of the operator, or the ones of Kubernetes
+func getHealthProbeClient(healthProbe polkadotv1alpha1.HealthProbe) *corev1.Probe{
+	probe := &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
-				Path: "/health",
+				Path: healthProbePath,
 				Port: intstr.IntOrString{Type: intstr.String, StrVal: RPCPortName},
 			},
 		},
-		InitialDelaySeconds: 10,
-		PeriodSeconds:       10,
+		InitialDelaySeconds: healthProbeDelay,
+		PeriodSeconds:       healthProbePeriod,
+		TimeoutSeconds:      healthProbe.TimeoutSeconds,
+		FailureThreshold:    healthProbe.FailureThreshold,
+	}
+	if healthProbe.Path != "" {
+		probe.HTTPGet.Path = healthProbe.Path
+	}
+	if healthProbe.InitialDelaySeconds != 0 {
+		probe.InitialDelaySeconds = healthProbe.InitialDelaySeconds
+	}
+	if healthProbe.PeriodSeconds != 0 {
+		probe.PeriodSeconds = healthProbe.PeriodSeconds
+	}
+	return probe
+}
+
+// getStartupProbeClient returns the probe of the health endpoint holding the other probes back while the node starts, with
+// a failure threshold large enough for an initial sync not to be interrupted by the kubelet
+func getStartupProbeClient(healthProbe polkadotv1alpha1.HealthProbe) *corev1.Probe {
+	probe := getHealthProbeClient(healthProbe)
+	if healthProbe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = startupProbePeriod
+	}
+	if healthProbe.FailureThreshold == 0 {
+		probe.FailureThreshold = startupProbeFailures
+	}
+	return probe
+}
+
+// getHostPorts reserves the ports of the client on the node of a pod on the host network, along with the metrics port it
+// listens on, so that the scheduler does not place two pods listening on the same ports on a node
+func getHostPorts(ports []corev1.ContainerPort, isMetricsSupportEnabled bool, metricsPort int32) []corev1.ContainerPort {
+	if isMetricsSupportEnabled {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: metricsPort,
+			Name:          metricsPortName,
+		})
+	}
+	for i := range ports {
+		ports[i].HostPort = ports[i].ContainerPort
+		ports[i].Protocol = corev1.ProtocolTCP
 	}
+	return ports
 }
 
-func getContainerPortsClient() []corev1.ContainerPort{
+func getContainerPortsClient(ports polkadotv1alpha1.Ports) []corev1.ContainerPort{
 	return []corev1.ContainerPort{
 		{
-			ContainerPort: int32(config.P2PPortEnvVar.Value),
+			ContainerPort: ports.P2P,
 			Name:          P2PPortName,
 		},
 		{
-			ContainerPort: int32(config.RPCPortEnvVar.Value),
+			ContainerPort: ports.RPC,
 			Name:          RPCPortName,
 		},
 		{
-			ContainerPort: int32(config.WSPortEnvVar.Value),
+			ContainerPort: ports.WS,
 			Name:          WSPortName,
 		},
 	}
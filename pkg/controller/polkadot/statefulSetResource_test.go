@@ -0,0 +1,690 @@
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewBootNodeStatefulSetForCR(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Bootnode)
+	polkadot.Spec.Bootnode = polkadotv1alpha1.Bootnode{
+		Replicas:   2,
+		ClientName: "Bootnode's name",
+		NodeKeys:   []string{"0000000000000000000000000000000000000000000000000000000000000031", "0000000000000000000000000000000000000000000000000000000000000032"},
+	}
+
+	statefulSet := newBootNodeStatefulSetForCR(polkadot)
+
+	if statefulSet.Name != BootnodeSSName || *statefulSet.Spec.Replicas != 2 {
+		t.Fatalf("newBootNodeStatefulSetForCR: unexpected StatefulSet (%v)", statefulSet.ObjectMeta)
+	}
+	command := statefulSet.Spec.Template.Spec.Containers[0].Command
+	if len(command) != 3 || command[0] != "/bin/sh" {
+		t.Fatalf("newBootNodeStatefulSetForCR: unexpected command (%v)", command)
+	}
+	script := command[2]
+	if !strings.HasPrefix(script, "set -- '"+polkadot.Spec.Bootnode.NodeKeys[0]+"' '"+polkadot.Spec.Bootnode.NodeKeys[1]+"'; ") {
+		t.Fatalf("newBootNodeStatefulSetForCR: node keys not passed (%v)", script)
+	}
+	if !strings.Contains(script, `'Bootnode'\''s name'`) || !strings.HasSuffix(script, `--node-key "$1"`) {
+		t.Fatalf("newBootNodeStatefulSetForCR: unexpected script (%v)", script)
+	}
+}
+
+func TestNewStatefulSetArchive(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Archive)
+	polkadot.Spec.Archive = polkadotv1alpha1.Archive{
+		Replicas:   1,
+		ClientName: "IronoaArchive",
+		DataPersistenceSupport: polkadotv1alpha1.DataPersistenceSupport{
+			Enabled: true,
+		},
+	}
+
+	statefulSet := newStatefulSetArchive(polkadot)
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--pruning archive") {
+		t.Fatalf("newStatefulSetArchive: archive pruning not set (%v)", command)
+	}
+	if len(statefulSet.Spec.VolumeClaimTemplates) != 1 {
+		t.Fatalf("newStatefulSetArchive: volume claim template not set")
+	}
+	claim := statefulSet.Spec.VolumeClaimTemplates[0]
+	size := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if claim.Name != archiveVolumeName || size.String() != archiveVolumeSize {
+		t.Fatalf("newStatefulSetArchive: unexpected default volume claim (%v)", claim)
+	}
+	if polkadot.Spec.Archive.DataPersistenceSupport.PersistentVolumeClaim.Name != "" {
+		t.Fatalf("newStatefulSetArchive: the CustomResource has been modified")
+	}
+}
+
+func TestNewStatefulSetPruning(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.StatePruning = "1000"
+	polkadot.Spec.Validator.BlocksPruning = "archive-canonical"
+
+	statefulSet := newStatefulSetValidator(polkadot)
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--pruning 1000") || !strings.Contains(command, "--blocks-pruning archive-canonical") {
+		t.Fatalf("newStatefulSetValidator: pruning not set (%v)", command)
+	}
+}
+
+func TestNewStatefulSetTelemetry(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.Sentry.TelemetryURL = "wss://telemetry.example.com/submit/"
+	polkadot.Spec.Sentry.TelemetryVerbosity = 1
+	polkadot.Spec.Validator.NoTelemetry = true
+
+	command := strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--telemetry-url wss://telemetry.example.com/submit/ 1") {
+		t.Fatalf("newStatefulSetSentry: telemetry not set (%v)", command)
+	}
+	command = strings.Join(newStatefulSetValidator(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--no-telemetry") || strings.Contains(command, "--telemetry-url") {
+		t.Fatalf("newStatefulSetValidator: telemetry not disabled (%v)", command)
+	}
+}
+
+func TestNewStatefulSetNodeKeySecret(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.NodeKey = "0000000000000000000000000000000000000000000000000000000000000013"
+	polkadot.Spec.Validator.NodeKeySecretRef = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "validator-keys"},
+		Key:                  "node-key",
+	}
+
+	statefulSet := newStatefulSetValidator(polkadot)
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--node-key-file "+nodeKeyMountPath+"/"+nodeKeyFileName) || strings.Contains(command, "--node-key ") {
+		t.Fatalf("newStatefulSetValidator: node key file not set (%v)", command)
+	}
+	volumes := statefulSet.Spec.Template.Spec.Volumes
+	if len(volumes) != 1 || volumes[0].Secret == nil || volumes[0].Secret.SecretName != "validator-keys" {
+		t.Fatalf("newStatefulSetValidator: node key volume not set (%v)", volumes)
+	}
+}
+
+func TestNewStatefulSetKeystoreSecret(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.KeystoreSecretRef = &corev1.LocalObjectReference{Name: "validator-keystore"}
+
+	statefulSet := newStatefulSetValidator(polkadot)
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--keystore-path "+keystoreMountPath) {
+		t.Fatalf("newStatefulSetValidator: keystore path not set (%v)", command)
+	}
+	mounts := statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts
+	if len(mounts) != 1 || mounts[0].Name != keystoreVolumeName || mounts[0].MountPath != keystoreMountPath {
+		t.Fatalf("newStatefulSetValidator: keystore not mounted (%v)", mounts)
+	}
+}
+
+func TestNewStatefulSetRemoteSigner(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.RemoteSigner = &polkadotv1alpha1.RemoteSigner{
+		Endpoint: "http://localhost:8000",
+		Sidecar:  &corev1.Container{Name: "signer", Image: "signer:v1.0.0"},
+	}
+	statefulSet := newStatefulSetValidator(polkadot)
+
+	command := strings.Join(getContainerClientFromStatefulSet(statefulSet).Command, " ")
+	if !strings.Contains(command, "--keystore-uri http://localhost:8000") {
+		t.Fatalf("newStatefulSetValidator: keystore uri not set (%v)", command)
+	}
+	containers := statefulSet.Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[1].Name != "signer" {
+		t.Fatalf("newStatefulSetValidator: signer sidecar not injected (%v)", containers)
+	}
+}
+
+func TestGetDataPersistence(t *testing.T) {
+
+	storageSize := resource.MustParse("500Gi")
+	dataPersistence := polkadotv1alpha1.DataPersistenceSupport{
+		Enabled:          true,
+		StorageClassName: "managed-premium",
+		StorageSize:      &storageSize,
+		AccessMode:       corev1.ReadWriteOnce,
+		RestoreFrom:      &polkadotv1alpha1.RestoreFrom{VolumeSnapshotName: "polkadot-volume-validator-sset-0-20200601000000"},
+	}
+
+	claim := getDataPersistence(dataPersistence).PersistentVolumeClaim
+	size := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName != "managed-premium" || size.Cmp(storageSize) != 0 {
+		t.Fatalf("getDataPersistence: storage not set (%v)", claim.Spec)
+	}
+	if len(claim.Spec.AccessModes) != 1 || claim.Spec.AccessModes[0] != corev1.ReadWriteOnce {
+		t.Fatalf("getDataPersistence: access mode not set (%v)", claim.Spec.AccessModes)
+	}
+	if claim.Spec.DataSource == nil || claim.Spec.DataSource.Kind != "VolumeSnapshot" || claim.Spec.DataSource.Name != dataPersistence.RestoreFrom.VolumeSnapshotName {
+		t.Fatalf("getDataPersistence: snapshot data source not set (%v)", claim.Spec.DataSource)
+	}
+}
+
+func TestNewStatefulSetBootstrap(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 1
+	polkadot.Spec.Sentry.DataPersistenceSupport = polkadotv1alpha1.DataPersistenceSupport{
+		Enabled:               true,
+		PersistentVolumeClaim: corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "polkadot-volume"}},
+		Bootstrap: &polkadotv1alpha1.Bootstrap{
+			URL:      "https://storage.googleapis.com/snapshots/polkadot.tar.gz",
+			Checksum: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	}
+
+	statefulSet := newStatefulSetSentry(polkadot)
+
+	initContainers := statefulSet.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 {
+		t.Fatalf("newStatefulSetSentry: bootstrap init container not set (%v)", initContainers)
+	}
+	bootstrap := initContainers[1]
+	if bootstrap.Image != bootstrapImage || bootstrap.Env[0].Value != polkadot.Spec.Sentry.DataPersistenceSupport.Bootstrap.URL {
+		t.Fatalf("newStatefulSetSentry: unexpected bootstrap init container (%v)", bootstrap)
+	}
+	if len(bootstrap.VolumeMounts) != 1 || bootstrap.VolumeMounts[0].Name != "polkadot-volume" {
+		t.Fatalf("newStatefulSetSentry: data volume not mounted (%v)", bootstrap.VolumeMounts)
+	}
+}
+
+func TestNewStatefulSetRpcNode(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(RpcNode)
+	polkadot.Spec.RpcNode = polkadotv1alpha1.RpcNode{
+		Replicas:   2,
+		ClientName: "IronoaRpcNode",
+	}
+
+	statefulSet := newStatefulSetRpcNode(polkadot)
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if strings.Contains(command, "--unsafe-rpc-external") || strings.Contains(command, "--unsafe-ws-external") {
+		t.Fatalf("newStatefulSetRpcNode: unsafe RPC exposed (%v)", command)
+	}
+	if !strings.Contains(command, "--rpc-external") || !strings.Contains(command, "--ws-external") {
+		t.Fatalf("newStatefulSetRpcNode: RPC not exposed (%v)", command)
+	}
+}
+
+func TestNewStatefulSetCollator(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Collator)
+	polkadot.Spec.Collator = polkadotv1alpha1.Collator{
+		Replicas:           1,
+		ClientName:         "IronoaCollator",
+		Image:              "parity/polkadot-collator:latest",
+		ParachainChainSpec: "/chainspecs/parachain.json",
+		RelayChainSpec:     "/chainspecs/relay.json",
+	}
+
+	statefulSet := newStatefulSetCollator(polkadot)
+
+	container := statefulSet.Spec.Template.Spec.Containers[0]
+	if container.Image != polkadot.Spec.Collator.Image {
+		t.Fatalf("newStatefulSetCollator: unexpected image (%v)", container.Image)
+	}
+	command := strings.Join(container.Command, " ")
+	if !strings.HasPrefix(command, collatorBinary+" ") {
+		t.Fatalf("newStatefulSetCollator: unexpected binary (%v)", command)
+	}
+	if !strings.HasSuffix(command, "--collator --chain /chainspecs/parachain.json -- --chain /chainspecs/relay.json") {
+		t.Fatalf("newStatefulSetCollator: unexpected arguments (%v)", command)
+	}
+}
+
+func TestNewStatefulSetAntiAffinity(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.AntiAffinity = polkadotv1alpha1.AntiAffinity{Enabled: true}
+
+	antiAffinity := newStatefulSetSentry(polkadot).Spec.Template.Spec.Affinity.PodAntiAffinity
+	preferred := antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	if len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 0 || len(preferred) != 2 || preferred[0].PodAffinityTerm.TopologyKey != zoneTopologyKey || preferred[1].PodAffinityTerm.TopologyKey != corev1.LabelHostname {
+		t.Fatalf("newStatefulSetSentry: unexpected anti-affinity (%v)", antiAffinity)
+	}
+
+	polkadot.Spec.Sentry.AntiAffinity.Mode = AntiAffinityRequired
+	antiAffinity = newStatefulSetSentry(polkadot).Spec.Template.Spec.Affinity.PodAntiAffinity
+	required := antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required) != 1 || required[0].TopologyKey != corev1.LabelHostname || len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Fatalf("newStatefulSetSentry: unexpected anti-affinity (%v)", antiAffinity)
+	}
+
+	// the podAntiAffinity of the user is kept
+	polkadot.Spec.Sentry.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+	antiAffinity = newStatefulSetSentry(polkadot).Spec.Template.Spec.Affinity.PodAntiAffinity
+	if antiAffinity != polkadot.Spec.Sentry.Affinity.PodAntiAffinity {
+		t.Fatalf("newStatefulSetSentry: anti-affinity of the user overridden (%v)", antiAffinity)
+	}
+}
+
+func TestNewStatefulSetHostNetwork(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.MetricsSupport.Enabled = true
+
+	if podSpec := newStatefulSetSentry(polkadot).Spec.Template.Spec; podSpec.HostNetwork || podSpec.Containers[0].Ports[0].HostPort != 0 {
+		t.Fatalf("newStatefulSetSentry: unexpected host network (%v)", podSpec)
+	}
+
+	polkadot.Spec.Sentry.HostNetwork = true
+	podSpec := newStatefulSetSentry(polkadot).Spec.Template.Spec
+	if !podSpec.HostNetwork || podSpec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Fatalf("newStatefulSetSentry: host network not set (%v)", podSpec)
+	}
+	// the P2P, RPC, WebSocket and metrics ports are reserved on the node
+	ports := podSpec.Containers[0].Ports
+	if len(ports) != 4 || ports[3].Name != metricsPortName {
+		t.Fatalf("newStatefulSetSentry: unexpected ports (%v)", ports)
+	}
+	for _, port := range ports {
+		if port.HostPort != port.ContainerPort {
+			t.Fatalf("newStatefulSetSentry: port not reserved on the node (%v)", port)
+		}
+	}
+}
+
+func TestNewStatefulSetPorts(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.MetricsSupport.Enabled = true
+	polkadot.Spec.Ports = polkadotv1alpha1.Ports{P2P: 30334, RPC: 9934, WS: 9945, Prometheus: 9616}
+
+	container := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0]
+	command := strings.Join(container.Command, " ")
+	if !strings.Contains(command, "--port 30334 --rpc-port 9934 --ws-port 9945") || !strings.Contains(command, "--prometheus-port 9616") {
+		t.Fatalf("newStatefulSetSentry: unexpected command (%v)", command)
+	}
+	if ports := container.Ports; ports[0].ContainerPort != 30334 || ports[1].ContainerPort != 9934 || ports[2].ContainerPort != 9945 {
+		t.Fatalf("newStatefulSetSentry: unexpected ports (%v)", ports)
+	}
+	if ports := newServiceSentry(polkadot).Spec.Ports; ports[1].Port != 9934 || ports[1].TargetPort.IntVal != 9934 || ports[3].Port != 9616 {
+		t.Fatalf("newServiceSentry: unexpected ports (%v)", ports)
+	}
+
+	// the RPC of a pod is reached at the port of its client container
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{container}}, Status: corev1.PodStatus{PodIP: "10.0.0.1"}}
+	if endpoint := getPodRPCEndpoint(pod); endpoint != "http://10.0.0.1:9934" {
+		t.Fatalf("getPodRPCEndpoint: unexpected endpoint (%v)", endpoint)
+	}
+}
+
+func TestNewStatefulSetChainspec(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.ChainspecConfigMapRef = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "chainspec"}, Key: "raw.json"}
+
+	for _, statefulSet := range []*appsv1.StatefulSet{newStatefulSetSentry(polkadot), newStatefulSetValidator(polkadot)} {
+		podSpec := statefulSet.Spec.Template.Spec
+		command := strings.Join(podSpec.Containers[0].Command, " ")
+		if !strings.Contains(command, "--chain /config/chainspec.json") {
+			t.Fatalf("%s: unexpected command (%v)", statefulSet.Name, command)
+		}
+		volume := podSpec.Volumes[len(podSpec.Volumes)-1]
+		if volume.ConfigMap == nil || volume.ConfigMap.Name != "chainspec" || volume.ConfigMap.Items[0].Key != "raw.json" {
+			t.Fatalf("%s: unexpected chainspec volume (%v)", statefulSet.Name, volume)
+		}
+		mounts := podSpec.Containers[0].VolumeMounts
+		if mount := mounts[len(mounts)-1]; mount.Name != chainspecVolumeName || mount.MountPath != chainspecMountPath || !mount.ReadOnly {
+			t.Fatalf("%s: unexpected chainspec mount (%v)", statefulSet.Name, mount)
+		}
+	}
+
+	// the chainspec is the one of the embedded relay chain node of the collators
+	polkadot.Spec.Kind = string(Collator)
+	polkadot.Spec.Collator = polkadotv1alpha1.Collator{Replicas: 1, ParachainChainSpec: "/chainspecs/parachain.json", RelayChainSpec: "rococo"}
+	command := strings.Join(newStatefulSetCollator(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--chain /chainspecs/parachain.json -- --chain /config/chainspec.json") {
+		t.Fatalf("newStatefulSetCollator: unexpected arguments (%v)", command)
+	}
+}
+
+func TestNewStatefulSetChain(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Chain = "kusama"
+	polkadot.Spec.Ports.RPC = 9000
+
+	container := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0]
+	command := strings.Join(container.Command, " ")
+	if !strings.Contains(command, "--chain kusama") || !strings.Contains(command, "--port 30334 --rpc-port 9000 --ws-port 9945") {
+		t.Fatalf("newStatefulSetSentry: unexpected command (%v)", command)
+	}
+	if container.Image != "parity/polkadot:"+polkadot.Spec.ClientVersion {
+		t.Fatalf("newStatefulSetSentry: unexpected image (%v)", container.Image)
+	}
+	if requests := container.Resources.Requests; requests.Cpu().String() != "1" || requests.Memory().String() != "2Gi" {
+		t.Fatalf("newStatefulSetSentry: unexpected requests of the chain (%v)", requests)
+	}
+	// the requests set in the CR take precedence over the ones of the chain
+	polkadot.Spec.Sentry.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+	if requests := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Resources.Requests; requests.Cpu().String() != "2" || len(requests) != 1 {
+		t.Fatalf("newStatefulSetSentry: unexpected requests (%v)", requests)
+	}
+
+	// the chain is the one of the embedded relay chain node of the collators
+	polkadot.Spec.Kind = string(Collator)
+	polkadot.Spec.Chain = "rococo"
+	polkadot.Spec.Collator = polkadotv1alpha1.Collator{Replicas: 1, Image: "parity/polkadot-collator:latest", ParachainChainSpec: "/chainspecs/parachain.json", RelayChainSpec: "/chainspecs/relay.json"}
+	container = newStatefulSetCollator(polkadot).Spec.Template.Spec.Containers[0]
+	if command := strings.Join(container.Command, " "); !strings.Contains(command, "-- --chain rococo") || container.Image != polkadot.Spec.Collator.Image {
+		t.Fatalf("newStatefulSetCollator: unexpected container (%v)", container)
+	}
+}
+
+func TestNewStatefulSetProbes(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Probes = polkadotv1alpha1.Probes{
+		Startup:   polkadotv1alpha1.HealthProbe{FailureThreshold: 20160},
+		Liveness:  polkadotv1alpha1.HealthProbe{PeriodSeconds: 30, FailureThreshold: 5},
+		Readiness: polkadotv1alpha1.HealthProbe{Path: "/health/readiness", TimeoutSeconds: 3},
+	}
+
+	container := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0]
+	startup := container.StartupProbe
+	if startup.HTTPGet.Path != healthProbePath || startup.PeriodSeconds != startupProbePeriod || startup.FailureThreshold != 20160 {
+		t.Fatalf("newStatefulSetSentry: unexpected startup probe (%v)", startup)
+	}
+	liveness := container.LivenessProbe
+	if liveness.HTTPGet.Path != healthProbePath || liveness.InitialDelaySeconds != healthProbeDelay || liveness.PeriodSeconds != 30 || liveness.FailureThreshold != 5 {
+		t.Fatalf("newStatefulSetSentry: unexpected liveness probe (%v)", liveness)
+	}
+	readiness := container.ReadinessProbe
+	if readiness.HTTPGet.Path != "/health/readiness" || readiness.PeriodSeconds != healthProbePeriod || readiness.TimeoutSeconds != 3 || readiness.FailureThreshold != 0 {
+		t.Fatalf("newStatefulSetSentry: unexpected readiness probe (%v)", readiness)
+	}
+
+	polkadot.Spec.Sentry.Probes = polkadotv1alpha1.Probes{}
+	startup = newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].StartupProbe
+	if startup.PeriodSeconds != startupProbePeriod || startup.FailureThreshold != startupProbeFailures {
+		t.Fatalf("newStatefulSetSentry: unexpected default startup probe (%v)", startup)
+	}
+}
+
+func TestNewStatefulSetStallDetection(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Ports.RPC = 9934
+	polkadot.Spec.Sentry.StallDetection = polkadotv1alpha1.StallDetection{Enabled: true, Timeout: &metav1.Duration{Duration: 5 * time.Minute}}
+
+	podSpec := newStatefulSetSentry(polkadot).Spec.Template.Spec
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != stallDetectorName || podSpec.Containers[1].Image != stallDetectorImage {
+		t.Fatalf("newStatefulSetSentry: stall detector not injected (%v)", podSpec.Containers)
+	}
+	if script := strings.Join(podSpec.Containers[1].Command, " "); !strings.Contains(script, "http://localhost:9934") || !strings.Contains(script, "chain_getHeader") {
+		t.Fatalf("newStatefulSetSentry: unexpected stall detector (%v)", script)
+	}
+	liveness := podSpec.Containers[0].LivenessProbe
+	if liveness.Exec == nil || !strings.Contains(liveness.Exec.Command[2], "-lt 300 ]") || liveness.InitialDelaySeconds != 300 {
+		t.Fatalf("newStatefulSetSentry: unexpected liveness probe (%v)", liveness)
+	}
+	// the readiness is still the one of the health endpoint
+	if podSpec.Containers[0].ReadinessProbe.HTTPGet == nil {
+		t.Fatalf("newStatefulSetSentry: unexpected readiness probe (%v)", podSpec.Containers[0].ReadinessProbe)
+	}
+}
+
+func TestNewStatefulSetSidecars(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.StallDetection.Enabled = true
+	polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "log-shipper", Image: "fluent/fluent-bit"}}
+
+	statefulSet := newStatefulSetSentry(polkadot)
+	containers := statefulSet.Spec.Template.Spec.Containers
+	if len(containers) != 3 || containers[1].Name != stallDetectorName || containers[2].Name != "log-shipper" {
+		t.Fatalf("newStatefulSetSentry: sidecar not appended (%v)", containers)
+	}
+
+	// a change of a sidecar is rolled out
+	err := setStatefulSetHash(statefulSet)
+	if err != nil {
+		t.Fatalf("setStatefulSetHash: %v", err)
+	}
+	polkadot.Spec.Sentry.Sidecars[0].Image = "fluent/fluent-bit:1.5"
+	changed := newStatefulSetSentry(polkadot)
+	err = setStatefulSetHash(changed)
+	if err != nil {
+		t.Fatalf("setStatefulSetHash: %v", err)
+	}
+	if changed.Annotations[specHashAnnotation] == statefulSet.Annotations[specHashAnnotation] {
+		t.Fatalf("setStatefulSetHash: sidecar change not detected")
+	}
+	if drift := getStatefulSetDrift(statefulSet, changed); len(drift) != 1 || drift[0] != "log-shipper.image" {
+		t.Fatalf("getStatefulSetDrift: unexpected drift (%v)", drift)
+	}
+}
+
+func TestNewStatefulSetClientVersion(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(SentryAndValidator)
+	polkadot.Spec.ClientVersion = "v0.8.2"
+
+	sentry := newStatefulSetSentry(polkadot)
+	validator := newStatefulSetValidator(polkadot)
+	err := setStatefulSetHash(sentry)
+	if err != nil {
+		t.Fatalf("setStatefulSetHash: %v", err)
+	}
+	err = setStatefulSetHash(validator)
+	if err != nil {
+		t.Fatalf("setStatefulSetHash: %v", err)
+	}
+
+	// the new version is canaried on the sentries, the validator keeps the version of the CR
+	polkadot.Spec.Sentry.ClientVersion = "v0.8.3"
+	canary := newStatefulSetSentry(polkadot)
+	if image := canary.Spec.Template.Spec.Containers[0].Image; !strings.HasSuffix(image, ":v0.8.3") {
+		t.Fatalf("newStatefulSetSentry: client version of the role not used (%s)", image)
+	}
+	if drift := getStatefulSetDrift(sentry, canary); len(drift) != 1 || drift[0] != "polkadot.image" {
+		t.Fatalf("getStatefulSetDrift: unexpected drift of the sentries (%v)", drift)
+	}
+	unchanged := newStatefulSetValidator(polkadot)
+	err = setStatefulSetHash(unchanged)
+	if err != nil {
+		t.Fatalf("setStatefulSetHash: %v", err)
+	}
+	if unchanged.Annotations[specHashAnnotation] != validator.Annotations[specHashAnnotation] {
+		t.Fatalf("setStatefulSetHash: validator changed by the version of the sentries")
+	}
+
+	polkadot.Spec.Sentry.ClientImage = "registry.example.com/polkadot"
+	if image := newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Image; image != "registry.example.com/polkadot:v0.8.3" {
+		t.Fatalf("newStatefulSetSentry: client image of the role not used (%s)", image)
+	}
+}
+
+func TestNewStatefulSetInitContainers(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.InitContainers = []corev1.Container{{Name: "fetch-keys", Image: "alpine"}}
+
+	initContainers := newStatefulSetSentry(polkadot).Spec.Template.Spec.InitContainers
+	if len(initContainers) != 1 || initContainers[0].Name != "fetch-keys" {
+		t.Fatalf("newStatefulSetSentry: init container not set (%v)", initContainers)
+	}
+
+	// the init containers of the CR run after the ones of the operator
+	polkadot.Spec.Sentry.DataPersistenceSupport.Enabled = true
+	initContainers = newStatefulSetSentry(polkadot).Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 || initContainers[1].Name != "fetch-keys" {
+		t.Fatalf("newStatefulSetSentry: init container not appended (%v)", initContainers)
+	}
+}
+
+func TestNewStatefulSetSyncReadiness(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Ports.RPC = 9934
+	maxBlocksBehind := int32(20)
+	polkadot.Spec.Sentry.SyncReadiness = polkadotv1alpha1.SyncReadiness{Enabled: true, MaxBlocksBehind: &maxBlocksBehind}
+
+	podSpec := newStatefulSetSentry(polkadot).Spec.Template.Spec
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != syncReadinessName || podSpec.Containers[1].Image != syncReadinessImage {
+		t.Fatalf("newStatefulSetSentry: sync readiness sidecar not injected (%v)", podSpec.Containers)
+	}
+	sidecar := podSpec.Containers[1]
+	if script := strings.Join(sidecar.Command, " "); !strings.Contains(script, "http://localhost:9934") || !strings.Contains(script, "system_syncState") || !strings.Contains(script, "-le 20 ]") {
+		t.Fatalf("newStatefulSetSentry: unexpected sync readiness sidecar (%v)", script)
+	}
+	if sidecar.ReadinessProbe == nil || sidecar.ReadinessProbe.Exec == nil {
+		t.Fatalf("newStatefulSetSentry: unexpected sync readiness probe (%v)", sidecar.ReadinessProbe)
+	}
+
+	polkadot.Spec.Sentry.SyncReadiness.Enabled = false
+	podSpec = newStatefulSetSentry(polkadot).Spec.Template.Spec
+	if len(podSpec.Containers) != 1 {
+		t.Fatalf("newStatefulSetSentry: sync readiness sidecar injected while disabled (%v)", podSpec.Containers)
+	}
+}
+
+func TestNewStatefulSetEraExporter(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Ports.WS = 9945
+	polkadot.Spec.Validator.Staking.Stash = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+	polkadot.Spec.Validator.EraExporter = polkadotv1alpha1.EraExporter{Enabled: true}
+
+	containers := newStatefulSetValidator(polkadot).Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[1].Name != eraExporterName || containers[1].Image != polkadotJsImage {
+		t.Fatalf("newStatefulSetValidator: era exporter not injected (%v)", containers)
+	}
+	env := map[string]string{}
+	for _, envVar := range containers[1].Env {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["ENDPOINT"] != "ws://localhost:9945" || env["STASH"] != polkadot.Spec.Validator.Staking.Stash || env["PORT"] != "9618" {
+		t.Fatalf("newStatefulSetValidator: unexpected era exporter env (%v)", env)
+	}
+	if len(containers[1].Ports) != 1 || containers[1].Ports[0].Name != eraExporterPortName || containers[1].Ports[0].ContainerPort != eraExporterPort {
+		t.Fatalf("newStatefulSetValidator: unexpected era exporter ports (%v)", containers[1].Ports)
+	}
+
+	endpoints := newPodMonitor(polkadot).Spec.PodMetricsEndpoints
+	if len(endpoints) != 2 || endpoints[1].Port != eraExporterPortName {
+		t.Fatalf("newPodMonitor: era exporter not scraped (%v)", endpoints)
+	}
+}
+
+func TestNewStatefulSetExternalValidator(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.ReservedValidatorID = fakePeerID
+	polkadot.Spec.Sentry.ExternalValidatorAddr = "/ip4/198.51.100.7/tcp/30333"
+
+	// the sentries reserve the validator running outside of Kubernetes
+	commands := strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(commands, "--sentry") || !strings.Contains(commands, "--reserved-nodes /ip4/198.51.100.7/tcp/30333/p2p/"+fakePeerID) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", commands)
+	}
+
+	// the validators of other clusters are reserved as well
+	polkadot.Spec.Sentry.ExternalValidators = []string{"/dns4/validator.a.example.com/tcp/30333/p2p/validator-peer-id"}
+	commands = strings.Join(newStatefulSetSentry(polkadot).Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(commands, "--reserved-nodes /dns4/validator.a.example.com/tcp/30333/p2p/validator-peer-id /ip4/198.51.100.7/tcp/30333/p2p/"+fakePeerID) {
+		t.Fatalf("newStatefulSetSentry: unexpected commands (%v)", commands)
+	}
+}
+
+func TestNewStatefulSetHardened(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Hardened = true
+	polkadot.Spec.Sentry.Sidecars = []corev1.Container{{Name: "sidecar", Image: "busybox", SecurityContext: &corev1.SecurityContext{
+		Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_BIND_SERVICE"}},
+	}}}
+	rootUser := int64(0)
+	polkadot.Spec.Sentry.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: &rootUser}
+
+	template := newStatefulSetSentry(polkadot).Spec.Template
+	if template.Annotations[corev1.SeccompPodAnnotationKey] != corev1.SeccompProfileRuntimeDefault {
+		t.Fatalf("newStatefulSetSentry: no seccomp profile (%v)", template.Annotations)
+	}
+	podSecurityContext := template.Spec.SecurityContext
+	if podSecurityContext.RunAsNonRoot == nil || !*podSecurityContext.RunAsNonRoot || podSecurityContext.RunAsUser == nil || *podSecurityContext.RunAsUser == 0 {
+		t.Fatalf("newStatefulSetSentry: unexpected pod security context (%v)", podSecurityContext)
+	}
+	for _, container := range template.Spec.Containers {
+		securityContext := container.SecurityContext
+		if securityContext == nil || !*securityContext.ReadOnlyRootFilesystem || *securityContext.AllowPrivilegeEscalation || securityContext.Capabilities.Drop[0] != "ALL" {
+			t.Fatalf("newStatefulSetSentry: container %s not hardened (%v)", container.Name, securityContext)
+		}
+		if !isMountPath(container.VolumeMounts, tmpMountPath) {
+			t.Fatalf("newStatefulSetSentry: no writable tmp in container %s (%v)", container.Name, container.VolumeMounts)
+		}
+	}
+	// only the capabilities allowed by the restricted profile are added
+	sidecar := template.Spec.Containers[len(template.Spec.Containers)-1]
+	if sidecar.Name != "sidecar" || !reflect.DeepEqual(sidecar.SecurityContext.Capabilities.Add, []corev1.Capability{"NET_BIND_SERVICE"}) {
+		t.Fatalf("newStatefulSetSentry: unexpected capabilities (%v)", sidecar.SecurityContext.Capabilities)
+	}
+	// the data directory is writable without data volume, and the sidecars of the CR are left untouched
+	if !isMountPath(template.Spec.Containers[0].VolumeMounts, volumeMountPath) || len(polkadot.Spec.Sentry.Sidecars[0].SecurityContext.Capabilities.Add) != 2 {
+		t.Fatalf("newStatefulSetSentry: unexpected data volume (%v)", template.Spec.Containers[0].VolumeMounts)
+	}
+
+	// the data volume is owned by the fsGroup of the pods, without root init container
+	polkadot.Spec.Sentry.PodSecurityContext = nil
+	polkadot.Spec.Sentry.DataPersistenceSupport.Enabled = true
+	template = newStatefulSetSentry(polkadot).Spec.Template
+	if len(template.Spec.InitContainers) != 0 || template.Spec.SecurityContext.FSGroup == nil {
+		t.Fatalf("newStatefulSetSentry: unexpected init containers (%v)", template.Spec.InitContainers)
+	}
+	for _, volume := range template.Spec.Volumes {
+		if volume.Name == hardenedDataVolumeName {
+			t.Fatalf("newStatefulSetSentry: data volume replaced (%v)", template.Spec.Volumes)
+		}
+	}
+
+	if securityContext := newStakingJob(polkadot).Spec.Template.Spec.Containers[0].SecurityContext; securityContext == nil || !*securityContext.RunAsNonRoot {
+		t.Fatalf("newStakingJob: job not hardened (%v)", securityContext)
+	}
+}
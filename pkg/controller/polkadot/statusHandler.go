@@ -0,0 +1,327 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+)
+
+// roleResources links a node role to the names of the resources generated for it and to its options
+type roleResources struct {
+	role            string
+	statefulSetName string
+	serviceName     string
+	replicas        int32
+	options         polkadotv1alpha1.NodeOptions
+}
+
+func getRoleResources(CRInstance *polkadotv1alpha1.Polkadot) []roleResources {
+	spec := CRInstance.Spec
+	validatorReplicas := int32(1)
+	if isHighAvailabilityEnabled(CRInstance) {
+		validatorReplicas = validatorHAReplicas
+	}
+	sentry := roleResources{role: "sentry", statefulSetName: SentrySSName, serviceName: ServiceSentryName, replicas: spec.Sentry.Replicas, options: spec.Sentry.NodeOptions}
+	validator := roleResources{role: "validator", statefulSetName: ValidatorSSName, serviceName: ServiceValidatorName, replicas: validatorReplicas, options: spec.Validator.NodeOptions}
+	bootnode := roleResources{role: "bootnode", statefulSetName: BootnodeSSName, serviceName: ServiceBootnodeName, replicas: spec.Bootnode.Replicas, options: spec.Bootnode.NodeOptions}
+	archive := roleResources{role: "archive", statefulSetName: ArchiveSSName, serviceName: ServiceArchiveName, replicas: spec.Archive.Replicas, options: spec.Archive.NodeOptions}
+	rpcNode := roleResources{role: "rpcnode", statefulSetName: RpcNodeSSName, serviceName: ServiceRpcNodeName, replicas: spec.RpcNode.Replicas, options: spec.RpcNode.NodeOptions}
+	collator := roleResources{role: "collator", statefulSetName: CollatorSSName, serviceName: ServiceCollatorName, replicas: spec.Collator.Replicas, options: spec.Collator.NodeOptions}
+
+	roles := map[string]roleResources{"sentry": sentry, "validator": validator, "bootnode": bootnode, "archive": archive, "rpcnode": rpcNode, "collator": collator}
+
+	result := []roleResources{}
+	for _, pool := range getNodePools(CRInstance) {
+		if rr, isFound := roles[pool.Role]; isFound {
+			result = append(result, rr)
+		}
+	}
+	return result
+}
+
+func (r *ReconcilerPolkadot) handleStatus(CRInstance *polkadotv1alpha1.Polkadot) error {
+	logger := getLogger(CRInstance)
+
+	roles := []polkadotv1alpha1.RoleStatus{}
+	nodes := []string{}
+	// the nodes are queried within a single deadline, so that the unresponsive ones do not hold the reconcile worker
+	deadline := time.Now().Add(syncStatusTimeout)
+	for _, rr := range getRoleResources(CRInstance) {
+		roleStatus, err := r.getRoleStatus(CRInstance, rr, deadline)
+		if err != nil {
+			logger.Error(err, "Error on fetch the StatefulSet for the status...")
+			return err
+		}
+		roles = append(roles, roleStatus)
+		for i := int32(0); i < roleStatus.Replicas; i++ {
+			nodes = append(nodes, fmt.Sprintf("%s-%d", rr.statefulSetName, i))
+		}
+	}
+
+	now := metav1.Now()
+	CRInstance.Status.Roles = roles
+	CRInstance.Status.Nodes = nodes
+	CRInstance.Status.Replicas = int32(len(nodes))
+	CRInstance.Status.Synced = isSynced(roles)
+	isNewGeneration := isNewGenerationApplied(CRInstance)
+	if isNewGeneration {
+		setHistory(CRInstance, now)
+	}
+	// the generation of a paused CR is not applied to its resources
+	if !CRInstance.Spec.Paused {
+		CRInstance.Status.ObservedGeneration = CRInstance.Generation
+	}
+	CRInstance.Status.LastReconcileTime = &now
+	// the changes reported by a previous dry run are outdated once the CR leaves the dry-run mode
+	CRInstance.Status.DryRun = nil
+	setNetworkHealthConditions(CRInstance, now)
+	setPausedCondition(CRInstance, now)
+
+	err := r.updateStatus(CRInstance)
+	if err != nil {
+		logger.Error(err, "Update Status Error...")
+		return err
+	}
+	if isNewGeneration {
+		return r.handleAppliedSpec(CRInstance)
+	}
+	return nil
+}
+
+func (r *ReconcilerPolkadot) getRoleStatus(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources, deadline time.Time) (polkadotv1alpha1.RoleStatus, error) {
+	roleStatus := polkadotv1alpha1.RoleStatus{
+		Role:            rr.role,
+		StatefulSetName: rr.statefulSetName,
+		ServiceName:     rr.serviceName,
+		Maintenance:     getMaintenanceMode(rr.options),
+	}
+
+	foundResource := &appsv1.StatefulSet{}
+	isNotFound, err := r.fetchResource(foundResource, types.NamespacedName{Name: rr.statefulSetName, Namespace: CRInstance.Namespace})
+	if err != nil || isNotFound {
+		return roleStatus, err
+	}
+
+	if foundResource.Spec.Replicas != nil {
+		roleStatus.Replicas = *foundResource.Spec.Replicas
+	}
+	roleStatus.ReadyReplicas = foundResource.Status.ReadyReplicas
+	roleStatus.CurrentReplicas = foundResource.Status.CurrentReplicas
+	roleStatus.UpdatedReplicas = foundResource.Status.UpdatedReplicas
+	if container := getContainerClientFromStatefulSet(foundResource); container != nil {
+		roleStatus.Image = container.Image
+	}
+
+	err = r.setRoleSyncStatus(CRInstance, rr, &roleStatus, deadline)
+	return roleStatus, err
+}
+
+// nodeSyncResult is the outcome of the query of the sync state of a pod
+type nodeSyncResult struct {
+	index  int
+	status polkadotv1alpha1.NodeStatus
+	err    error
+}
+
+// setRoleSyncStatus queries the sync state of the running nodes of the role concurrently, the unreachable ones and the
+// ones not answering before the deadline are left out of the status
+func (r *ReconcilerPolkadot) setRoleSyncStatus(CRInstance *polkadotv1alpha1.Polkadot, rr roleResources, roleStatus *polkadotv1alpha1.RoleStatus, deadline time.Time) error {
+	logger := getLogger(CRInstance).WithValues("Role", rr.role)
+
+	labels := getAppLabels()
+	labels["role"] = rr.role
+	pods := &corev1.PodList{}
+	err := r.client.List(context.TODO(), pods, client.InNamespace(CRInstance.Namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return err
+	}
+
+	// the channel is buffered for every query, so that the ones still running after the deadline do not block
+	results := make(chan nodeSyncResult, len(pods.Items))
+	queries := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		queries++
+		go func(index int, name, endpoint string) {
+			status, err := getNodeSyncStatus(name, endpoint)
+			results <- nodeSyncResult{index: index, status: status, err: err}
+		}(i, pod.Name, getPodRPCEndpoint(pod))
+	}
+
+	nodeStatuses := make([]*polkadotv1alpha1.NodeStatus, len(pods.Items))
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+wait:
+	for received := 0; received < queries; received++ {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				logger.V(warnLevel).Info("Not able to get the sync state of the node...", "Pod.Name", result.status.Name, "error", result.err.Error())
+				continue
+			}
+			nodeStatuses[result.index] = &result.status
+		case <-timer.C:
+			logger.V(warnLevel).Info("Sync state of the nodes not received before the deadline...", "Nodes", queries-received)
+			break wait
+		}
+	}
+
+	// the nodes are reported in the order of the pods
+	for _, nodeStatus := range nodeStatuses {
+		if nodeStatus == nil {
+			continue
+		}
+		roleStatus.Nodes = append(roleStatus.Nodes, *nodeStatus)
+		roleStatus.IsSyncing = roleStatus.IsSyncing || nodeStatus.IsSyncing
+		if nodeStatus.BestBlock > roleStatus.BestBlock {
+			roleStatus.BestBlock = nodeStatus.BestBlock
+		}
+		if nodeStatus.FinalizedBlock > roleStatus.FinalizedBlock {
+			roleStatus.FinalizedBlock = nodeStatus.FinalizedBlock
+		}
+	}
+	return nil
+}
+
+// isSynced tells if at least one node has been reached and none of the reached nodes is syncing
+func isSynced(roles []polkadotv1alpha1.RoleStatus) bool {
+	isReached := false
+	for _, role := range roles {
+		if role.IsSyncing {
+			return false
+		}
+		isReached = isReached || len(role.Nodes) > 0
+	}
+	return isReached
+}
+
+// setNetworkHealthConditions derives the NetworkHealthy condition from the peer counts of the reachable nodes.
+// The CR is Degraded once the network has not been healthy for longer than the period
+func setNetworkHealthConditions(CRInstance *polkadotv1alpha1.Polkadot, now metav1.Time) {
+	minPeers := getMinPeers(CRInstance)
+	period := getNetworkHealthPeriod(CRInstance)
+
+	reachableNodes := 0
+	lowPeersNodes := []string{}
+	for _, role := range CRInstance.Status.Roles {
+		for _, node := range role.Nodes {
+			reachableNodes++
+			if node.Peers < minPeers {
+				lowPeersNodes = append(lowPeersNodes, node.Name)
+			}
+		}
+	}
+
+	networkHealthy := polkadotv1alpha1.PolkadotCondition{Type: NetworkHealthyType, Status: corev1.ConditionTrue, Reason: "EnoughPeers"}
+	if reachableNodes == 0 {
+		networkHealthy.Status = corev1.ConditionUnknown
+		networkHealthy.Reason = "NodesNotReachable"
+	} else if len(lowPeersNodes) > 0 {
+		networkHealthy.Status = corev1.ConditionFalse
+		networkHealthy.Reason = "LowPeerCount"
+		networkHealthy.Message = fmt.Sprintf("nodes with less than %d peers: %s", minPeers, strings.Join(lowPeersNodes, ", "))
+	}
+	networkHealthy = setCondition(&CRInstance.Status, networkHealthy, now)
+
+	degraded := polkadotv1alpha1.PolkadotCondition{Type: DegradedType, Status: corev1.ConditionFalse, Reason: networkHealthy.Reason}
+	if networkHealthy.Status == corev1.ConditionFalse && now.Sub(networkHealthy.LastTransitionTime.Time) >= period {
+		degraded.Status = corev1.ConditionTrue
+		degraded.Message = networkHealthy.Message
+	}
+	setCondition(&CRInstance.Status, degraded, now)
+}
+
+// getNetworkHealthPeriod returns how long a node can stay under the minimum peer count before the CR is Degraded
+func getNetworkHealthPeriod(CRInstance *polkadotv1alpha1.Polkadot) time.Duration {
+	if CRInstance.Spec.NetworkHealth.Period == nil {
+		return networkHealthPeriod
+	}
+	return CRInstance.Spec.NetworkHealth.Period.Duration
+}
+
+// getNetworkHealthRequeue returns when the peer counts of a CR whose network is not healthy must be polled again: at the
+// end of the period, when it turns Degraded, or every networkHealthPollPeriod to observe its recovery. The peer counts
+// are not watched, the CR is not requeued while its network is healthy
+func getNetworkHealthRequeue(CRInstance *polkadotv1alpha1.Polkadot, now metav1.Time) time.Duration {
+	var networkHealthy, degraded *polkadotv1alpha1.PolkadotCondition
+	for i, condition := range CRInstance.Status.Conditions {
+		switch condition.Type {
+		case NetworkHealthyType:
+			networkHealthy = &CRInstance.Status.Conditions[i]
+		case DegradedType:
+			degraded = &CRInstance.Status.Conditions[i]
+		}
+	}
+	if networkHealthy == nil || networkHealthy.Status == corev1.ConditionTrue {
+		return 0
+	}
+	requeue := networkHealthPollPeriod
+	if networkHealthy.Status == corev1.ConditionFalse && (degraded == nil || degraded.Status != corev1.ConditionTrue) {
+		left := networkHealthy.LastTransitionTime.Add(getNetworkHealthPeriod(CRInstance)).Sub(now.Time)
+		if left < time.Second {
+			left = time.Second
+		}
+		if left < requeue {
+			requeue = left
+		}
+	}
+	return requeue
+}
+
+// getMinPeers returns the peer count under which a node is not healthy
+func getMinPeers(CRInstance *polkadotv1alpha1.Polkadot) int32 {
+	if CRInstance.Spec.NetworkHealth.MinPeers == 0 {
+		return defaultMinPeers
+	}
+	return CRInstance.Spec.NetworkHealth.MinPeers
+}
+
+// setPausedCondition reports if the reconciliation of the resources of the CR is suspended by spec.paused
+func setPausedCondition(CRInstance *polkadotv1alpha1.Polkadot, now metav1.Time) {
+	paused := polkadotv1alpha1.PolkadotCondition{Type: PausedType, Status: corev1.ConditionFalse, Reason: "Reconciling"}
+	if CRInstance.Spec.Paused {
+		paused.Status = corev1.ConditionTrue
+		paused.Reason = "Paused"
+		paused.Message = "the resources of the CR are neither created nor updated by the operator"
+	}
+	setCondition(&CRInstance.Status, paused, now)
+}
+
+// setCondition adds or replaces a condition of the status, keeping its transition time while the status does not change
+func setCondition(status *polkadotv1alpha1.PolkadotStatus, condition polkadotv1alpha1.PolkadotCondition, now metav1.Time) polkadotv1alpha1.PolkadotCondition {
+	condition.LastTransitionTime = now
+	for i := range status.Conditions {
+		if status.Conditions[i].Type != condition.Type {
+			continue
+		}
+		if status.Conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = status.Conditions[i].LastTransitionTime
+		}
+		status.Conditions[i] = condition
+		return condition
+	}
+	status.Conditions = append(status.Conditions, condition)
+	return condition
+}
+
+// removeCondition removes a condition of the status, when the feature it reports is disabled
+func removeCondition(status *polkadotv1alpha1.PolkadotStatus, conditionType string) {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleStatus(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		kind          CRKind
+		objs          []runtime.Object
+		expectedRoles int
+		expectedNodes int
+		expectedBlock int64
+	}{
+		{
+			name:          "Status Sentry",
+			kind:          Sentry,
+			objs:          []runtime.Object{getFakeStatefulSet(SentrySSName, 2)},
+			expectedRoles: 1,
+			expectedNodes: 2,
+		},
+		{
+			name:          "Status SentryAndValidator",
+			kind:          SentryAndValidator,
+			objs:          []runtime.Object{getFakeStatefulSet(SentrySSName, 1), getFakeStatefulSet(ValidatorSSName, 1)},
+			expectedRoles: 2,
+			expectedNodes: 2,
+		},
+		{
+			name: "Status Sentry synced",
+			kind: Sentry,
+			objs: []runtime.Object{getFakeStatefulSet(SentrySSName, 1), &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: SentrySSName + "-0", Labels: getSentrylabels()},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+			}},
+			expectedRoles: 1,
+			expectedNodes: 1,
+			expectedBlock: 255,
+		},
+		{
+			name:          "Status StatefulSet not yet created",
+			kind:          Validator,
+			objs:          []runtime.Object{},
+			expectedRoles: 1,
+			expectedNodes: 0,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		responses := map[string]string{
+			"system_health":          `{"peers":12,"isSyncing":false,"shouldHavePeers":true}`,
+			"system_syncState":       `{"startingBlock":0,"currentBlock":257,"highestBlock":257}`,
+			"chain_getFinalizedHead": `"0xabcd"`,
+			"chain_getHeader":        `{"number":"0xff"}`,
+		}
+		return json.Unmarshal([]byte(responses[method]), result)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A Polkadot object with metadata and spec.
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(test.kind)
+
+			// Objects to track in the fake client.
+			objs := append([]runtime.Object{polkadot}, test.objs...)
+
+			// Create a fake client to mock API calls.
+			client := newFakeClient(scheme, objs...)
+			reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+			err := reconciler.handleStatus(polkadot)
+			if err != nil {
+				t.Fatalf("handleStatus: (%v)", err)
+			}
+
+			found := &polkadotv1alpha1.Polkadot{}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: CRName}, found)
+			if err != nil {
+				t.Fatalf("handleStatus: (%v)", err)
+			}
+			if len(found.Status.Roles) != test.expectedRoles || len(found.Status.Nodes) != test.expectedNodes {
+				t.Fatalf("handleStatus: unexpected status (%v)", found.Status)
+			}
+			if found.Status.Roles[0].FinalizedBlock != test.expectedBlock || found.Status.Synced != (test.expectedBlock > 0) {
+				t.Fatalf("handleStatus: unexpected sync state (%v)", found.Status.Roles[0])
+			}
+			if found.Status.LastReconcileTime == nil {
+				t.Fatalf("handleStatus: last reconcile time not set")
+			}
+		})
+	}
+}
+
+func TestSetRoleSyncStatusDeadline(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	// the second sentry is running but does not answer
+	unresponsive := make(chan struct{})
+	defer close(unresponsive)
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		if strings.Contains(endpoint, "10.0.0.2") {
+			<-unresponsive
+			return fmt.Errorf("%s: timeout", method)
+		}
+		responses := map[string]string{
+			"system_health":          `{"peers":12,"isSyncing":false,"shouldHavePeers":true}`,
+			"system_syncState":       `{"startingBlock":0,"currentBlock":257,"highestBlock":257}`,
+			"chain_getFinalizedHead": `"0xabcd"`,
+			"chain_getHeader":        `{"number":"0xff"}`,
+		}
+		return json.Unmarshal([]byte(responses[method]), result)
+	}
+
+	objs := []runtime.Object{getFakePolkadot()}
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		objs = append(objs, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", SentrySSName, i), Labels: getSentrylabels()},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		})
+	}
+	client := newFakeClient(scheme, objs...)
+	reconciler := ReconcilerPolkadot{client: client, scheme: scheme}
+
+	start := time.Now()
+	roleStatus := polkadotv1alpha1.RoleStatus{}
+	rr := roleResources{role: "sentry", statefulSetName: SentrySSName}
+	err := reconciler.setRoleSyncStatus(getFakePolkadot(), rr, &roleStatus, start.Add(100*time.Millisecond))
+	if err != nil || time.Since(start) > time.Second {
+		t.Fatalf("setRoleSyncStatus: deadline not respected (%v, %v)", time.Since(start), err)
+	}
+	if len(roleStatus.Nodes) != 1 || roleStatus.Nodes[0].Name != SentrySSName+"-0" || roleStatus.FinalizedBlock != 255 {
+		t.Fatalf("setRoleSyncStatus: unexpected status (%v)", roleStatus)
+	}
+}
+
+func TestSetNetworkHealthConditions(t *testing.T) {
+
+	now := metav1.Now()
+	tenMinutesAgo := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	tests := []struct {
+		name                   string
+		nodes                  []polkadotv1alpha1.NodeStatus
+		conditions             []polkadotv1alpha1.PolkadotCondition
+		expectedNetworkHealthy corev1.ConditionStatus
+		expectedDegraded       corev1.ConditionStatus
+		expectedRequeue        time.Duration
+	}{
+		{
+			name:                   "Nodes not reachable",
+			nodes:                  nil,
+			expectedNetworkHealthy: corev1.ConditionUnknown,
+			expectedDegraded:       corev1.ConditionFalse,
+			expectedRequeue:        networkHealthPollPeriod,
+		},
+		{
+			name:                   "Enough peers",
+			nodes:                  []polkadotv1alpha1.NodeStatus{{Name: "sentry-sset-0", Peers: 25}},
+			expectedNetworkHealthy: corev1.ConditionTrue,
+			expectedDegraded:       corev1.ConditionFalse,
+			expectedRequeue:        0,
+		},
+		{
+			name:                   "Low peers since now",
+			nodes:                  []polkadotv1alpha1.NodeStatus{{Name: "sentry-sset-0", Peers: 1}},
+			expectedNetworkHealthy: corev1.ConditionFalse,
+			expectedDegraded:       corev1.ConditionFalse,
+			expectedRequeue:        networkHealthPollPeriod,
+		},
+		{
+			name:  "Low peers for almost the period",
+			nodes: []polkadotv1alpha1.NodeStatus{{Name: "sentry-sset-0", Peers: 1}},
+			conditions: []polkadotv1alpha1.PolkadotCondition{
+				{Type: NetworkHealthyType, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-270 * time.Second))},
+			},
+			expectedNetworkHealthy: corev1.ConditionFalse,
+			expectedDegraded:       corev1.ConditionFalse,
+			expectedRequeue:        30 * time.Second,
+		},
+		{
+			name:  "Low peers for longer than the period",
+			nodes: []polkadotv1alpha1.NodeStatus{{Name: "sentry-sset-0", Peers: 1}},
+			conditions: []polkadotv1alpha1.PolkadotCondition{
+				{Type: NetworkHealthyType, Status: corev1.ConditionFalse, LastTransitionTime: tenMinutesAgo},
+			},
+			expectedNetworkHealthy: corev1.ConditionFalse,
+			expectedDegraded:       corev1.ConditionTrue,
+			expectedRequeue:        networkHealthPollPeriod,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polkadot := getFakePolkadot()
+			polkadot.Status.Roles = []polkadotv1alpha1.RoleStatus{{Role: "sentry", Nodes: test.nodes}}
+			polkadot.Status.Conditions = test.conditions
+
+			setNetworkHealthConditions(polkadot, now)
+
+			conditions := map[string]corev1.ConditionStatus{}
+			for _, condition := range polkadot.Status.Conditions {
+				conditions[condition.Type] = condition.Status
+			}
+			if conditions[NetworkHealthyType] != test.expectedNetworkHealthy || conditions[DegradedType] != test.expectedDegraded {
+				t.Fatalf("setNetworkHealthConditions: unexpected conditions (%v)", polkadot.Status.Conditions)
+			}
+			if requeue := getNetworkHealthRequeue(polkadot, now); requeue != test.expectedRequeue {
+				t.Fatalf("getNetworkHealthRequeue: unexpected requeue (%v)", requeue)
+			}
+		})
+	}
+}
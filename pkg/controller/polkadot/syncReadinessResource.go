@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getSyncReadinessContainer returns the sidecar gating the readiness of the pod on the sync state of the client: it polls
+// system_syncState and flags the node as synced while its current block is at most maxBlocksBehind blocks behind the
+// highest one. Without a highest block (e.g. no peers yet), the node is synced once system_health reports it not syncing.
+// The pod is only ready once all its containers are, so the readiness probe of the sidecar gates the whole pod
+func getSyncReadinessContainer(syncReadiness polkadotv1alpha1.SyncReadiness, rpcPort int32) corev1.Container {
+	image := syncReadiness.Image
+	if image == "" {
+		image = syncReadinessImage
+	}
+	maxBlocksBehind := int32(syncMaxBlocksBehind)
+	if syncReadiness.MaxBlocksBehind != nil {
+		maxBlocksBehind = *syncReadiness.MaxBlocksBehind
+	}
+	synced := "/tmp/synced"
+	script := `rpc() { wget -qO- --header 'Content-Type: application/json' --post-data "{\"id\":1,\"jsonrpc\":\"2.0\",\"method\":\"$1\",\"params\":[]}" ` +
+		fmt.Sprintf("http://localhost:%d", rpcPort) + " 2>/dev/null; }; " +
+		"while true; do " +
+		"state=$(rpc system_syncState); " +
+		`current=$(echo "$state" | sed -n 's/.*"currentBlock":\([0-9]*\).*/\1/p'); ` +
+		`highest=$(echo "$state" | sed -n 's/.*"highestBlock":\([0-9]*\).*/\1/p'); ` +
+		`if [ -n "$current" ] && [ -z "$highest" ] && rpc system_health | grep -q '"isSyncing":false'; then highest=$current; fi; ` +
+		fmt.Sprintf(`if [ -n "$current" ] && [ -n "$highest" ] && [ $((highest - current)) -le %d ]; then touch %s; else rm -f %s; fi; `, maxBlocksBehind, synced, synced) +
+		fmt.Sprintf("sleep %d; ", syncPollPeriod) +
+		"done"
+
+	return corev1.Container{
+		Name:    syncReadinessName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", script},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				Exec: &corev1.ExecAction{
+					Command: []string{"test", "-f", synced},
+				},
+			},
+			PeriodSeconds: syncPollPeriod,
+		},
+	}
+}
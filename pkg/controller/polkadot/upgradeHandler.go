@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+// handleUpgradePartition sets the rolling update partition of the desired StatefulSet when the upgrades are sync gated
+// or canary. A new pod template is first rolled out to the pod with the highest ordinal only. The canary is promoted once
+// it has been healthy for the soak time, then the partition is lowered by one every time the last updated node is synced
+// and has enough peers (sync gated) or set to zero, so that the nodes of a role are never all out of sync.
+// The roles with the OnDelete update strategy are left to the user, who restarts their pods
+func (r *ReconcilerPolkadot) handleUpgradePartition(CRInstance *polkadotv1alpha1.Polkadot, current *appsv1.StatefulSet, desired *appsv1.StatefulSet) error {
+	if isOnDeleteUpdate(desired) {
+		return r.handleOnDeleteUpdate(CRInstance, current, desired)
+	}
+	upgrade := CRInstance.Spec.Upgrade
+	if !upgrade.SyncGated && !upgrade.Canary.Enabled {
+		return nil
+	}
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", desired.Name)
+
+	templateHash, err := setTemplateHash(desired)
+	if err != nil {
+		return err
+	}
+
+	partition := int32(0)
+	lastOrdinal := *desired.Spec.Replicas - 1
+	if current.Annotations[templateHashAnnotation] != templateHash {
+		if lastOrdinal > 0 {
+			logger.Info("New pod template, upgrading the pod with the highest ordinal first...")
+			partition = lastOrdinal
+		}
+	} else if getPartition(current) > 0 {
+		partition = getPartition(current)
+		if partition > lastOrdinal {
+			partition = lastOrdinal
+		}
+		isUpgraded, err := r.isPodUpgraded(CRInstance, current, partition)
+		if err != nil {
+			return err
+		}
+		if upgrade.Canary.Enabled && partition == lastOrdinal {
+			partition = r.getCanaryPartition(CRInstance, current, desired, isUpgraded)
+		} else if isUpgraded {
+			logger.Info("Upgraded node synced, upgrading the next pod...", "Partition", partition-1)
+			r.recordEvent(CRInstance, corev1.EventTypeNormal, "UpgradeProgressed", "The upgraded node %s-%d is synced, upgrading the next pod of the StatefulSet %s", current.Name, partition, current.Name)
+			partition--
+		}
+	}
+
+	desired.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+	}
+	return nil
+}
+
+// handleOnDeleteUpdate reports the new pod templates staged in the StatefulSet, which only reach the pods the user deletes
+func (r *ReconcilerPolkadot) handleOnDeleteUpdate(CRInstance *polkadotv1alpha1.Polkadot, current *appsv1.StatefulSet, desired *appsv1.StatefulSet) error {
+	templateHash, err := setTemplateHash(desired)
+	if err != nil {
+		return err
+	}
+	if currentHash, isFound := current.Annotations[templateHashAnnotation]; isFound && currentHash != templateHash {
+		getLogger(CRInstance).WithValues("Deployment.Name", desired.Name).Info("New pod template staged, waiting for the pods to be deleted...")
+		r.recordEvent(CRInstance, corev1.EventTypeNormal, "RevisionStaged", "A new revision of the StatefulSet %s is staged, its pods are updated once deleted", desired.Name)
+	}
+	return nil
+}
+
+// setTemplateHash annotates the StatefulSet with the hash of its pod template, the reference of the next reconciliations
+func setTemplateHash(statefulSet *appsv1.StatefulSet) (string, error) {
+	templateHash, err := getTemplateHash(statefulSet)
+	if err != nil {
+		return "", err
+	}
+	annotations := getCopy(statefulSet.Annotations)
+	annotations[templateHashAnnotation] = templateHash
+	statefulSet.Annotations = annotations
+	return templateHash, nil
+}
+
+func isOnDeleteUpdate(statefulSet *appsv1.StatefulSet) bool {
+	return statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType
+}
+
+// getCanaryPartition keeps the upgrade on the canary pod until it has been healthy for the soak time, the start of the
+// soak is kept in an annotation of the StatefulSet. The promoted upgrade goes on one pod at a time if it is sync gated
+func (r *ReconcilerPolkadot) getCanaryPartition(CRInstance *polkadotv1alpha1.Polkadot, current *appsv1.StatefulSet, desired *appsv1.StatefulSet, isHealthy bool) int32 {
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", desired.Name)
+	partition := *desired.Spec.Replicas - 1
+	if !isHealthy {
+		return partition
+	}
+
+	soakTime := canarySoakTime
+	if CRInstance.Spec.Upgrade.Canary.SoakTime != nil {
+		soakTime = CRInstance.Spec.Upgrade.Canary.SoakTime.Duration
+	}
+	now := metav1.Now()
+	healthySince, err := time.Parse(time.RFC3339, current.Annotations[canaryAnnotation])
+	if err != nil {
+		logger.Info("Canary node healthy, starting its soak time...", "SoakTime", soakTime.String())
+		healthySince = now.Time
+	}
+	if now.Sub(healthySince) < soakTime {
+		desired.Annotations[canaryAnnotation] = healthySince.Format(time.RFC3339)
+		return partition
+	}
+
+	logger.Info("Canary node healthy for the soak time, promoting the upgrade...")
+	r.recordEvent(CRInstance, corev1.EventTypeNormal, "CanaryPromoted", "The canary node %s-%d has been healthy for %s, upgrading the other pods of the StatefulSet %s", current.Name, partition, soakTime.String(), current.Name)
+	if CRInstance.Spec.Upgrade.SyncGated {
+		return partition - 1
+	}
+	return 0
+}
+
+// isPodUpgraded tells if the pod with the given ordinal runs the updated revision of the StatefulSet, and if its node
+// is synced and has at least the minimum peers of the network health
+func (r *ReconcilerPolkadot) isPodUpgraded(CRInstance *polkadotv1alpha1.Polkadot, statefulSet *appsv1.StatefulSet, ordinal int32) (bool, error) {
+	logger := getLogger(CRInstance).WithValues("Deployment.Name", statefulSet.Name)
+
+	pod := &corev1.Pod{}
+	name := fmt.Sprintf("%s-%d", statefulSet.Name, ordinal)
+	isNotFound, err := r.fetchResource(pod, types.NamespacedName{Name: name, Namespace: statefulSet.Namespace})
+	if err != nil {
+		logger.Error(err, "Error on fetch the Pod...", "Pod.Name", name)
+		return false, err
+	}
+	if isNotFound || pod.Labels[revisionLabel] != statefulSet.Status.UpdateRevision || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		return false, nil
+	}
+
+	nodeStatus, err := getNodeSyncStatus(pod.Name, getPodRPCEndpoint(pod))
+	if err != nil {
+		logger.V(warnLevel).Info("Not able to get the sync state of the upgraded node...", "Pod.Name", pod.Name, "error", err.Error())
+		return false, nil
+	}
+	if nodeStatus.IsSyncing || nodeStatus.Peers < getMinPeers(CRInstance) {
+		logger.Info("Waiting for the upgraded node to be synced...", "Pod.Name", pod.Name, "IsSyncing", nodeStatus.IsSyncing, "Peers", nodeStatus.Peers)
+		return false, nil
+	}
+	return true, nil
+}
+
+// isUpgradeInProgress tells if a sync gated or canary upgrade is still rolling out, according to the roles of the status.
+// The roles with the OnDelete update strategy wait for the user, they are not polled
+func isUpgradeInProgress(CRInstance *polkadotv1alpha1.Polkadot) bool {
+	if !CRInstance.Spec.Upgrade.SyncGated && !CRInstance.Spec.Upgrade.Canary.Enabled {
+		return false
+	}
+	isOnDelete := map[string]bool{}
+	for _, rr := range getRoleResources(CRInstance) {
+		isOnDelete[rr.role] = rr.options.UpdateStrategy != nil && rr.options.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType
+	}
+	for _, role := range CRInstance.Status.Roles {
+		if role.UpdatedReplicas < role.Replicas && !isOnDelete[role.Role] {
+			return true
+		}
+	}
+	return false
+}
+
+func getPartition(statefulSet *appsv1.StatefulSet) int32 {
+	rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.Partition == nil {
+		return 0
+	}
+	return *rollingUpdate.Partition
+}
+
+func getTemplateHash(statefulSet *appsv1.StatefulSet) (string, error) {
+	data, err := json.Marshal(statefulSet.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -0,0 +1,244 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleUpgradePartition(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	isSyncing := false
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		responses := map[string]string{
+			"system_health":          `{"peers":12,"isSyncing":false,"shouldHavePeers":true}`,
+			"system_syncState":       `{"startingBlock":0,"currentBlock":257,"highestBlock":257}`,
+			"chain_getFinalizedHead": `"0xabcd"`,
+			"chain_getHeader":        `{"number":"0xff"}`,
+		}
+		if isSyncing {
+			responses["system_health"] = `{"peers":12,"isSyncing":true,"shouldHavePeers":true}`
+		}
+		return json.Unmarshal([]byte(responses[method]), result)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 3
+	polkadot.Spec.Upgrade.SyncGated = true
+	polkadot.Spec.ClientVersion = "v0.8.2"
+
+	current := newStatefulSetSentry(polkadot)
+	reconciler := ReconcilerPolkadot{client: newFakeClient(scheme, polkadot), scheme: scheme}
+	if err := reconciler.handleUpgradePartition(polkadot, current, current); err != nil {
+		t.Fatalf("handleUpgradePartition: (%v)", err)
+	}
+
+	// a new client version is rolled out to the pod with the highest ordinal first
+	polkadot.Spec.ClientVersion = "v0.8.3"
+	desired := newStatefulSetSentry(polkadot)
+	if err := reconciler.handleUpgradePartition(polkadot, current, desired); err != nil || getPartition(desired) != 2 {
+		t.Fatalf("handleUpgradePartition: unexpected partition (%v, %v)", getPartition(desired), err)
+	}
+
+	// the upgraded pod is running the update revision
+	current = desired
+	current.Status.UpdateRevision = "sentry-sset-2"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: SentrySSName + "-2", Labels: map[string]string{revisionLabel: "sentry-sset-2"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+
+	tests := []struct {
+		name              string
+		isSyncing         bool
+		expectedPartition int32
+	}{
+		{
+			name:              "Upgraded node syncing",
+			isSyncing:         true,
+			expectedPartition: 2,
+		},
+		{
+			name:              "Upgraded node synced",
+			isSyncing:         false,
+			expectedPartition: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isSyncing = test.isSyncing
+			reconciler := ReconcilerPolkadot{client: newFakeClient(scheme, polkadot, pod), scheme: scheme}
+
+			desired := newStatefulSetSentry(polkadot)
+			if err := reconciler.handleUpgradePartition(polkadot, current, desired); err != nil || getPartition(desired) != test.expectedPartition {
+				t.Fatalf("handleUpgradePartition: unexpected partition (%v, %v)", getPartition(desired), err)
+			}
+		})
+	}
+}
+
+func TestHandleUpgradeCanary(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	isSyncing := false
+	defaultCallNodeRPC := callNodeRPC
+	defer func() { callNodeRPC = defaultCallNodeRPC }()
+	callNodeRPC = func(endpoint string, method string, result interface{}, params ...interface{}) error {
+		responses := map[string]string{
+			"system_health":          `{"peers":12,"isSyncing":false,"shouldHavePeers":true}`,
+			"system_syncState":       `{"startingBlock":0,"currentBlock":257,"highestBlock":257}`,
+			"chain_getFinalizedHead": `"0xabcd"`,
+			"chain_getHeader":        `{"number":"0xff"}`,
+		}
+		if isSyncing {
+			responses["system_health"] = `{"peers":12,"isSyncing":true,"shouldHavePeers":true}`
+		}
+		return json.Unmarshal([]byte(responses[method]), result)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: SentrySSName + "-2", Labels: map[string]string{revisionLabel: "sentry-sset-2"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+
+	tests := []struct {
+		name              string
+		isSyncing         bool
+		isSyncGated       bool
+		healthySince      time.Duration
+		expectedPartition int32
+		expectedSoak      bool
+	}{
+		{
+			name:              "Canary syncing",
+			isSyncing:         true,
+			expectedPartition: 2,
+			expectedSoak:      false,
+		},
+		{
+			name:              "Canary healthy, soak started",
+			expectedPartition: 2,
+			expectedSoak:      true,
+		},
+		{
+			name:              "Canary soaking",
+			healthySince:      5 * time.Minute,
+			expectedPartition: 2,
+			expectedSoak:      true,
+		},
+		{
+			name:              "Canary promoted",
+			healthySince:      11 * time.Minute,
+			expectedPartition: 0,
+			expectedSoak:      false,
+		},
+		{
+			name:              "Canary promoted, sync gated",
+			isSyncGated:       true,
+			healthySince:      11 * time.Minute,
+			expectedPartition: 1,
+			expectedSoak:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isSyncing = test.isSyncing
+			polkadot := getFakePolkadot()
+			polkadot.Spec.Kind = string(Sentry)
+			polkadot.Spec.Sentry.Replicas = 3
+			polkadot.Spec.Upgrade.SyncGated = test.isSyncGated
+			polkadot.Spec.Upgrade.Canary = polkadotv1alpha1.Canary{Enabled: true, SoakTime: &metav1.Duration{Duration: 10 * time.Minute}}
+			reconciler := ReconcilerPolkadot{client: newFakeClient(scheme, polkadot, pod), scheme: scheme}
+
+			// the new version rolled out to the canary
+			current := newStatefulSetSentry(polkadot)
+			if err := reconciler.handleUpgradePartition(polkadot, &appsv1.StatefulSet{}, current); err != nil || getPartition(current) != 2 {
+				t.Fatalf("handleUpgradePartition: unexpected partition (%v, %v)", getPartition(current), err)
+			}
+			current.Status.UpdateRevision = "sentry-sset-2"
+			if test.healthySince > 0 {
+				current.Annotations[canaryAnnotation] = time.Now().Add(-test.healthySince).Format(time.RFC3339)
+			}
+
+			desired := newStatefulSetSentry(polkadot)
+			if err := reconciler.handleUpgradePartition(polkadot, current, desired); err != nil || getPartition(desired) != test.expectedPartition {
+				t.Fatalf("handleUpgradePartition: unexpected partition (%v, %v)", getPartition(desired), err)
+			}
+			if _, isSoaking := desired.Annotations[canaryAnnotation]; isSoaking != test.expectedSoak {
+				t.Fatalf("handleUpgradePartition: unexpected soak annotation (%v)", desired.Annotations)
+			}
+		})
+	}
+}
+
+func TestHandleUpgradeOnDelete(t *testing.T) {
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Errorf("apis.AddToScheme: %v", err)
+	}
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Sentry)
+	polkadot.Spec.Sentry.Replicas = 3
+	polkadot.Spec.Sentry.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	polkadot.Spec.Upgrade.SyncGated = true
+	polkadot.Spec.ClientVersion = "v0.8.2"
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := ReconcilerPolkadot{client: newFakeClient(scheme, polkadot), scheme: scheme, recorder: recorder}
+	current := newStatefulSetSentry(polkadot)
+	if err := reconciler.handleUpgradePartition(polkadot, current, current); err != nil {
+		t.Fatalf("handleUpgradePartition: (%v)", err)
+	}
+
+	// a new client version is staged for all the pods, without partition, and reported
+	polkadot.Spec.ClientVersion = "v0.8.3"
+	desired := newStatefulSetSentry(polkadot)
+	if err := reconciler.handleUpgradePartition(polkadot, current, desired); err != nil || !isOnDeleteUpdate(desired) || desired.Spec.UpdateStrategy.RollingUpdate != nil {
+		t.Fatalf("handleUpgradePartition: unexpected update strategy (%v, %v)", desired.Spec.UpdateStrategy, err)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "RevisionStaged") {
+		t.Fatalf("handleUpgradePartition: unexpected event (%s)", event)
+	}
+
+	// the pods waiting for their deletion are not polled
+	polkadot.Status.Roles = []polkadotv1alpha1.RoleStatus{{Role: "sentry", Replicas: 3, UpdatedReplicas: 0}}
+	if isUpgradeInProgress(polkadot) {
+		t.Fatalf("isUpgradeInProgress: OnDelete role polled")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Swisscom Blockchain AG
+// Licensed under MIT License
+package polkadot
+
+import (
+	"fmt"
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"strconv"
+)
+
+const (
+	vaultAnnotationPrefix = "vault.hashicorp.com/"
+	vaultSecretsPath      = "/vault/secrets"
+	vaultNodeKeyName      = "node-key"
+	vaultKeystoreName     = "keystore"
+)
+
+// getVaultAnnotations returns the pod annotations making the Vault Agent injector render the keys of the node
+// before the client starts. The files are readable by the group of the client only
+func getVaultAnnotations(vault *polkadotv1alpha1.Vault) map[string]string {
+	annotations := map[string]string{}
+	if vault == nil {
+		return annotations
+	}
+	annotations[vaultAnnotationPrefix+"agent-inject"] = "true"
+	annotations[vaultAnnotationPrefix+"agent-pre-populate-only"] = "true"
+	annotations[vaultAnnotationPrefix+"role"] = vault.Role
+	if vault.NodeKey != nil {
+		addVaultSecretAnnotations(annotations, vaultNodeKeyName, *vault.NodeKey, vaultSecretsPath)
+	}
+	for i, secret := range vault.Keystore {
+		addVaultSecretAnnotations(annotations, vaultKeystoreName+"-"+strconv.Itoa(i), secret, vaultSecretsPath+"/"+vaultKeystoreName)
+		annotations[vaultAnnotationPrefix+"agent-inject-file-"+vaultKeystoreName+"-"+strconv.Itoa(i)] = secret.FileName
+	}
+	return annotations
+}
+
+func addVaultSecretAnnotations(annotations map[string]string, name string, secret polkadotv1alpha1.VaultSecret, path string) {
+	annotations[vaultAnnotationPrefix+"agent-inject-secret-"+name] = secret.Path
+	annotations[vaultAnnotationPrefix+"agent-inject-template-"+name] = fmt.Sprintf(`{{ with secret %q }}{{ index .Data.data %q }}{{ end }}`, secret.Path, secret.Field)
+	annotations[vaultAnnotationPrefix+"agent-inject-perms-"+name] = "0440"
+	annotations[vaultAnnotationPrefix+"secret-volume-path-"+name] = path
+}
+
+// getVaultCommands returns the client arguments reading the keys rendered by the Vault Agent
+func getVaultCommands(vault *polkadotv1alpha1.Vault) []string {
+	c := []string{}
+	if vault == nil {
+		return c
+	}
+	if vault.NodeKey != nil {
+		c = append(c, "--node-key-file", vaultSecretsPath+"/"+vaultNodeKeyName)
+	}
+	if len(vault.Keystore) > 0 {
+		c = append(c, "--keystore-path", vaultSecretsPath+"/"+vaultKeystoreName)
+	}
+	return c
+}
@@ -0,0 +1,38 @@
+package polkadot
+
+import (
+	polkadotv1alpha1 "github.com/swisscom-blockchain/polkadot-k8s-operator/pkg/apis/polkadot/v1alpha1"
+	"strings"
+	"testing"
+)
+
+func TestNewStatefulSetVault(t *testing.T) {
+
+	polkadot := getFakePolkadot()
+	polkadot.Spec.Kind = string(Validator)
+	polkadot.Spec.Validator.Vault = &polkadotv1alpha1.Vault{
+		Role:    "polkadot-validator",
+		NodeKey: &polkadotv1alpha1.VaultSecret{Path: "secret/data/polkadot/validator", Field: "nodeKey"},
+		Keystore: []polkadotv1alpha1.VaultSecret{
+			{Path: "secret/data/polkadot/validator", Field: "aura", FileName: "61757261d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"},
+		},
+	}
+
+	statefulSet := newStatefulSetValidator(polkadot)
+
+	annotations := statefulSet.Spec.Template.Annotations
+	if annotations[vaultAnnotationPrefix+"agent-inject"] != "true" || annotations[vaultAnnotationPrefix+"role"] != "polkadot-validator" {
+		t.Fatalf("newStatefulSetValidator: vault injection not requested (%v)", annotations)
+	}
+	if annotations[vaultAnnotationPrefix+"agent-inject-template-node-key"] != `{{ with secret "secret/data/polkadot/validator" }}{{ index .Data.data "nodeKey" }}{{ end }}` {
+		t.Fatalf("newStatefulSetValidator: unexpected node key template (%v)", annotations)
+	}
+	if annotations[vaultAnnotationPrefix+"agent-inject-file-keystore-0"] != polkadot.Spec.Validator.Vault.Keystore[0].FileName {
+		t.Fatalf("newStatefulSetValidator: unexpected keystore file (%v)", annotations)
+	}
+
+	command := strings.Join(statefulSet.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "--node-key-file /vault/secrets/node-key") || !strings.Contains(command, "--keystore-path /vault/secrets/keystore") {
+		t.Fatalf("newStatefulSetValidator: vault files not used (%v)", command)
+	}
+}